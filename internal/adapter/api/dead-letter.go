@@ -0,0 +1,85 @@
+package api
+
+import (
+	"errors"
+	"strconv"
+	configuration "walls-user-service/internal/core/helper/configuration-helper"
+	errorhelper "walls-user-service/internal/core/helper/error-helper"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary List Dead-Lettered Events
+// @Description List the most recent events parked after exhausting their handler retries
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param limit query int false "Maximum number of events, defaults to the page limit"
+// @Success 200 {array} entity.DeadLetterEvent "Success"
+// @Failure 400 {object} helper.ErrorResponse
+// @Failure 500 {object} helper.ErrorResponse
+// @Router /api/admin/dead-letters [get]
+func (hdl *HTTPHandler) GetDeadLetterEvents(c *gin.Context) {
+	limit, err := strconv.ParseInt(c.DefaultQuery("limit", configuration.ServiceConfiguration.PageLimit), 10, 64)
+	if err != nil || limit <= 0 {
+		c.AbortWithStatusJSON(400, gin.H{"error": "invalid limit in request query"})
+		return
+	}
+
+	deadLetterEvents, err := hdl.deadLetterService.GetDeadLetterEvents(c.Request.Context(), limit)
+	if err != nil {
+		c.AbortWithStatusJSON(500, errorhelper.ErrorMessage(errorhelper.RedisError, err.Error()))
+		return
+	}
+	c.JSON(200, deadLetterEvents)
+}
+
+// @Summary Get Dead-Lettered Event
+// @Description Inspect a dead-lettered event with its payload, last error and attempt count
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param dead_letter_reference path string true "Dead letter reference"
+// @Success 200 {object} entity.DeadLetterEvent "Success"
+// @Failure 404 {object} helper.ErrorResponse
+// @Failure 500 {object} helper.ErrorResponse
+// @Router /api/admin/dead-letters/{dead_letter_reference} [get]
+func (hdl *HTTPHandler) GetDeadLetterEvent(c *gin.Context) {
+	deadLetterEvent, err := hdl.deadLetterService.GetDeadLetterEvent(c.Request.Context(), c.Param("dead_letter_reference"))
+	if err != nil {
+		deadLetterError(c, err)
+		return
+	}
+	c.JSON(200, deadLetterEvent)
+}
+
+// @Summary Replay Dead-Lettered Event
+// @Description Publish a dead-lettered event again on its original channel and remove it from the dead-letter stream
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param dead_letter_reference path string true "Dead letter reference"
+// @Success 200 {string} string "Success"
+// @Failure 404 {object} helper.ErrorResponse
+// @Failure 409 {object} helper.ErrorResponse
+// @Failure 500 {object} helper.ErrorResponse
+// @Router /api/admin/dead-letters/{dead_letter_reference}/replay [post]
+func (hdl *HTTPHandler) ReplayDeadLetterEvent(c *gin.Context) {
+	response, err := hdl.deadLetterService.ReplayDeadLetterEvent(c.Request.Context(), c.Param("dead_letter_reference"))
+	if err != nil {
+		deadLetterError(c, err)
+		return
+	}
+	c.JSON(200, gin.H{"dead_letter_reference": response})
+}
+
+// deadLetterError - domain errors, such as an unknown dead letter reference,
+// keep their status; any other failure comes from redis.
+func deadLetterError(c *gin.Context, err error) {
+	var domainError errorhelper.DomainError
+	if errors.As(err, &domainError) {
+		c.Error(err)
+		return
+	}
+	c.AbortWithStatusJSON(500, errorhelper.ErrorMessage(errorhelper.RedisError, err.Error()))
+}
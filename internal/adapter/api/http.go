@@ -6,12 +6,16 @@ import (
 
 // Httphander for the api
 type HTTPHandler struct {
-	userService ports.UserService
+	userService       ports.UserService
+	deadLetterService ports.DeadLetterService
+	tierService       ports.TierService
 }
 
 func NewHTTPHandler(
-	countryService ports.UserService) *HTTPHandler {
+	countryService ports.UserService, deadLetterService ports.DeadLetterService, tierService ports.TierService) *HTTPHandler {
 	return &HTTPHandler{
-		userService: countryService,
+		userService:       countryService,
+		deadLetterService: deadLetterService,
+		tierService:       tierService,
 	}
 }
@@ -0,0 +1,40 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary List Tiers
+// @Description List the tiers in the tier catalogue with their limits
+// @Tags Tier
+// @Accept json
+// @Produce json
+// @Success 200 {array} entity.TierConfig "Success"
+// @Failure 500 {object} helper.ErrorResponse
+// @Router /api/tiers [get]
+func (hdl *HTTPHandler) GetTiers(c *gin.Context) {
+	tiers, err := hdl.tierService.GetTiers(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(200, tiers)
+}
+
+// @Summary Get Tier by Reference
+// @Description Get a tier in the tier catalogue with its limits
+// @Tags Tier
+// @Accept json
+// @Produce json
+// @Param reference path string true "Tier reference"
+// @Success 200 {object} entity.TierConfig "Success"
+// @Failure 404 {object} helper.ErrorResponse
+// @Router /api/tiers/{reference} [get]
+func (hdl *HTTPHandler) GetTierByReference(c *gin.Context) {
+	tier, err := hdl.tierService.GetTierByReference(c.Request.Context(), c.Param("reference"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(200, tier)
+}
@@ -22,6 +22,7 @@ import (
 // @Param X-Device-Model header string true "Device Model"
 // @Param X-Device-Reference header string true "Device Reference"
 // @Success 200 {string} entity.UserReference "Success"
+// @Failure 409 {object} helper.ErrorResponse
 // @Failure 500 {object} helper.ErrorResponse
 // @Param requestBody body dto.CreateUserDto true "Create User request body"
 // @Router /api/user [post]
@@ -30,14 +31,13 @@ func (hdl *HTTPHandler) CreateUser(c *gin.Context) {
 	_ = c.BindJSON(&body)
 
 	currentUser := extensions.GetCurrentUser(c)
-	if !extensions.ValidateBody(c, &body) || !extensions.ValidateHeaders(c, currentUser) {
-		c.AbortWithStatusJSON(400, gin.H{"error": "invalid request in request body or request headers"})
+	if !extensions.ValidateRequest(c, &body, currentUser) {
 		return
 	}
 
 	user, err := hdl.userService.CreateUser(c.Request.Context(), body, currentUser)
 	if err != nil {
-		c.AbortWithStatusJSON(500, errorhelper.ErrorMessage(errorhelper.MongoDBError, err.Error()))
+		c.Error(err)
 		return
 	}
 	c.JSON(201, gin.H{"user_reference:": user})
@@ -65,14 +65,13 @@ func (hdl *HTTPHandler) CreateOtpRequest(c *gin.Context) {
 	fmt.Println(body)
 	currentUser := extensions.GetCurrentUser(c)
 	fmt.Println(currentUser)
-	if !extensions.ValidateBody(c, &body) || !extensions.ValidateHeaders(c, currentUser) {
-		c.AbortWithStatusJSON(400, gin.H{"error": "invalid request in request body or request headers"})
+	if !extensions.ValidateRequest(c, &body, currentUser) {
 		return
 	}
 
 	user, err := hdl.userService.CreateOtpRequest(c.Request.Context(), body, currentUser)
 	if err != nil {
-		c.AbortWithStatusJSON(500, errorhelper.ErrorMessage(errorhelper.MongoDBError, err.Error()))
+		c.Error(err)
 		return
 	}
 	c.JSON(201, gin.H{"request_reference:": user})
@@ -100,14 +99,13 @@ func (hdl *HTTPHandler) ValidateOtpRequest(c *gin.Context) {
 	userReference := c.Param("user_reference")
 
 	currentUser := extensions.GetCurrentUser(c)
-	if !extensions.ValidateBody(c, &body) || !extensions.ValidateHeaders(c, currentUser) {
-		c.AbortWithStatusJSON(400, gin.H{"error": "invalid request in request body or request headers"})
+	if !extensions.ValidateRequest(c, &body, currentUser) {
 		return
 	}
 
 	identity, err := hdl.userService.ValidateOtpRequest(c.Request.Context(), userReference, body, currentUser)
 	if err != nil {
-		c.AbortWithStatusJSON(500, errorhelper.ErrorMessage(errorhelper.MongoDBError, err.Error()))
+		c.Error(err)
 		return
 	}
 
@@ -136,14 +134,13 @@ func (hdl *HTTPHandler) CreateIdentityRequest(c *gin.Context) {
 	userReference := c.Param("user_reference")
 
 	currentUser := extensions.GetCurrentUser(c)
-	if !extensions.ValidateBody(c, &body) || !extensions.ValidateHeaders(c, currentUser) {
-		c.AbortWithStatusJSON(400, gin.H{"error": "invalid request in request body or request headers"})
+	if !extensions.ValidateRequest(c, &body, currentUser) {
 		return
 	}
 
 	user, err := hdl.userService.CreateIdentityRequest(c.Request.Context(), userReference, body, currentUser)
 	if err != nil {
-		c.AbortWithStatusJSON(500, errorhelper.ErrorMessage(errorhelper.MongoDBError, err.Error()))
+		c.Error(err)
 		return
 	}
 	c.JSON(201, gin.H{"request_reference:": user})
@@ -171,14 +168,13 @@ func (hdl *HTTPHandler) UpgradeTierRequest(c *gin.Context) {
 	userReference := c.Param("user_reference")
 
 	currentUser := extensions.GetCurrentUser(c)
-	if !extensions.ValidateBody(c, &body) || !extensions.ValidateHeaders(c, currentUser) {
-		c.AbortWithStatusJSON(400, gin.H{"error": "invalid request in request body or request headers"})
+	if !extensions.ValidateRequest(c, &body, currentUser) {
 		return
 	}
 
 	user, err := hdl.userService.UpgradeTierRequest(c.Request.Context(), userReference, body, currentUser)
 	if err != nil {
-		c.AbortWithStatusJSON(500, errorhelper.ErrorMessage(errorhelper.MongoDBError, err.Error()))
+		c.Error(err)
 		return
 	}
 	c.JSON(201, gin.H{"request_reference:": user})
@@ -206,14 +202,13 @@ func (hdl *HTTPHandler) CreateTransactionRequest(c *gin.Context) {
 	userReference := c.Param("user_reference")
 
 	currentUser := extensions.GetCurrentUser(c)
-	if !extensions.ValidateBody(c, &body) || !extensions.ValidateHeaders(c, currentUser) {
-		c.AbortWithStatusJSON(400, gin.H{"error": "invalid request in request body or request headers"})
+	if !extensions.ValidateRequest(c, &body, currentUser) {
 		return
 	}
 
 	user, err := hdl.userService.CreateTransactionRequest(c.Request.Context(), userReference, body, currentUser)
 	if err != nil {
-		c.AbortWithStatusJSON(500, errorhelper.ErrorMessage(errorhelper.MongoDBError, err.Error()))
+		c.Error(err)
 		return
 	}
 	c.JSON(201, gin.H{"request_reference:": user})
@@ -278,14 +273,13 @@ func (hdl *HTTPHandler) CreateCompanyProfile(c *gin.Context) {
 	_ = c.BindJSON(&body)
 
 	currentUser := extensions.GetCurrentUser(c)
-	if !extensions.ValidateBody(c, &body) || !extensions.ValidateHeaders(c, currentUser) {
-		c.AbortWithStatusJSON(400, gin.H{"error": "invalid request in request body or request headers"})
+	if !extensions.ValidateRequest(c, &body, currentUser) {
 		return
 	}
 
 	user, err := hdl.userService.CreateCompanyProfile(c.Request.Context(), reference, body, currentUser)
 	if err != nil {
-		c.AbortWithStatusJSON(500, errorhelper.ErrorMessage(errorhelper.MongoDBError, err.Error()))
+		c.Error(err)
 		return
 	}
 	c.JSON(201, gin.H{"user_reference:": user})
@@ -305,6 +299,7 @@ func (hdl *HTTPHandler) CreateCompanyProfile(c *gin.Context) {
 // @Param X-Device-Reference header string true "Device Reference"
 // @Param user_reference path string true "User reference"
 // @Success 200 {string} string "Success"
+// @Failure 409 {object} helper.ErrorResponse
 // @Failure 500 {object} helper.ErrorResponse
 // @Param requestBody body dto.CompanyWallsBadgeDto true "Walls Badge request body"
 // @Router /api/user/{user_reference}/company/walls-badge [post]
@@ -315,13 +310,12 @@ func (hdl *HTTPHandler) CreateCompanyWallsBadge(c *gin.Context) {
 	_ = c.BindJSON(&body)
 
 	currentUser := extensions.GetCurrentUser(c)
-	if !extensions.ValidateBody(c, &body) || !extensions.ValidateHeaders(c, currentUser) {
-		c.AbortWithStatusJSON(400, gin.H{"error": "invalid request in request body or request headers"})
+	if !extensions.ValidateRequest(c, &body, currentUser) {
 		return
 	}
 	user, err := hdl.userService.CreateCompanyWallsBadge(c.Request.Context(), reference, body, currentUser)
 	if err != nil {
-		c.AbortWithStatusJSON(500, errorhelper.ErrorMessage(errorhelper.MongoDBError, err.Error()))
+		c.Error(err)
 		return
 	}
 	c.JSON(201, gin.H{"user_reference:": user})
@@ -341,6 +335,7 @@ func (hdl *HTTPHandler) CreateCompanyWallsBadge(c *gin.Context) {
 // @Param X-Device-Reference header string true "Device Reference"
 // @Param user_reference path string true "User reference"
 // @Success 200 {string} string "Success"
+// @Failure 409 {object} helper.ErrorResponse
 // @Failure 500 {object} helper.ErrorResponse
 // @Param requestBody body dto.UserWallsBadgeDto true "Create User Walls Badge request body"
 // @Router /api/user/{user_reference}/user/walls-badge [post]
@@ -351,13 +346,12 @@ func (hdl *HTTPHandler) CreateUserWallsBadge(c *gin.Context) {
 	_ = c.BindJSON(&body)
 
 	currentUser := extensions.GetCurrentUser(c)
-	if !extensions.ValidateBody(c, &body) || !extensions.ValidateHeaders(c, currentUser) {
-		c.AbortWithStatusJSON(400, gin.H{"error": "invalid request in request body or request headers"})
+	if !extensions.ValidateRequest(c, &body, currentUser) {
 		return
 	}
 	user, err := hdl.userService.CreateUserWallsBadge(c.Request.Context(), reference, body, currentUser)
 	if err != nil {
-		c.AbortWithStatusJSON(500, errorhelper.ErrorMessage(errorhelper.MongoDBError, err.Error()))
+		c.Error(err)
 		return
 	}
 	c.JSON(201, gin.H{"user_reference:": user})
@@ -389,13 +383,12 @@ func (hdl *HTTPHandler) UpdateCompanyProfile(c *gin.Context) {
 	_ = c.BindJSON(&body)
 
 	currentUser := extensions.GetCurrentUser(c)
-	if !extensions.ValidateBody(c, &body) || !extensions.ValidateHeaders(c, currentUser) {
-		c.AbortWithStatusJSON(400, gin.H{"error": "invalid request in request body or request headers"})
+	if !extensions.ValidateRequest(c, &body, currentUser) {
 		return
 	}
 	user, err := hdl.userService.UpdateCompanyProfile(c.Request.Context(), reference, coompanyReference, body, currentUser)
 	if err != nil {
-		c.AbortWithStatusJSON(500, errorhelper.ErrorMessage(errorhelper.MongoDBError, err.Error()))
+		c.Error(err)
 		return
 	}
 	c.JSON(201, gin.H{"user_reference:": user})
@@ -426,13 +419,12 @@ func (hdl *HTTPHandler) DisableCompanyWallsBadge(c *gin.Context) {
 
 	currentUser := extensions.GetCurrentUser(c)
 	if !extensions.ValidateHeaders(c, currentUser) {
-		c.AbortWithStatusJSON(400, gin.H{"error": "invalid request in request body or request headers"})
 		return
 	}
 
 	result, err := hdl.userService.DisableCompanyWallsBadge(c.Request.Context(), reference, companyReference, companyWallsBadgeReference, currentUser)
 	if err != nil {
-		c.AbortWithStatusJSON(500, errorhelper.ErrorMessage(errorhelper.MongoDBError, err.Error()))
+		c.Error(err)
 		return
 	}
 
@@ -462,12 +454,11 @@ func (hdl *HTTPHandler) DisableUserWallsBadge(c *gin.Context) {
 	currentUser := extensions.GetCurrentUser(c)
 
 	if !extensions.ValidateHeaders(c, currentUser) {
-		c.AbortWithStatusJSON(400, gin.H{"error": "invalid request in request body or request headers"})
 		return
 	}
 	result, err := hdl.userService.DisableUserWallsBadge(c.Request.Context(), reference, wallsBadgeReference, currentUser)
 	if err != nil {
-		c.AbortWithStatusJSON(500, errorhelper.ErrorMessage(errorhelper.MongoDBError, err.Error()))
+		c.Error(err)
 		return
 	}
 
@@ -490,7 +481,7 @@ func (hdl *HTTPHandler) GetCompanyWallsBadgeList(c *gin.Context) {
 
 	result, err := hdl.userService.GetCompanyWallsBadgeList(c.Request.Context(), reference, companyReference)
 	if err != nil {
-		c.AbortWithStatusJSON(500, errorhelper.ErrorMessage(errorhelper.MongoDBError, err.Error()))
+		c.Error(err)
 		return
 	}
 
@@ -511,7 +502,7 @@ func (hdl *HTTPHandler) GetUserWallsBadgeList(c *gin.Context) {
 
 	result, err := hdl.userService.GetUserWallsBadgeList(c.Request.Context(), reference)
 	if err != nil {
-		c.AbortWithStatusJSON(500, errorhelper.ErrorMessage(errorhelper.MongoDBError, err.Error()))
+		c.Error(err)
 		return
 	}
 
@@ -534,7 +525,7 @@ func (hdl *HTTPHandler) GetDefaultCompanyWallsBadge(c *gin.Context) {
 
 	result, err := hdl.userService.GetDefaultCompanyWallsBadge(c.Request.Context(), reference, companyReference)
 	if err != nil {
-		c.AbortWithStatusJSON(500, errorhelper.ErrorMessage(errorhelper.MongoDBError, err.Error()))
+		c.Error(err)
 		return
 	}
 
@@ -555,7 +546,7 @@ func (hdl *HTTPHandler) GetDefaultUserWallsBadge(c *gin.Context) {
 
 	result, err := hdl.userService.GetDefaultUserWallsBadge(c.Request.Context(), reference)
 	if err != nil {
-		c.AbortWithStatusJSON(500, errorhelper.ErrorMessage(errorhelper.MongoDBError, err.Error()))
+		c.Error(err)
 		return
 	}
 
@@ -585,7 +576,7 @@ func (hdl *HTTPHandler) DisableCompanyProfile(c *gin.Context) {
 
 	result, err := hdl.userService.DisableCompanyProfile(c.Request.Context(), reference, companyReference)
 	if err != nil {
-		c.AbortWithStatusJSON(500, errorhelper.ErrorMessage(errorhelper.MongoDBError, err.Error()))
+		c.Error(err)
 		return
 	}
 
@@ -618,13 +609,12 @@ func (hdl *HTTPHandler) UpdateCompanyLogo(c *gin.Context) {
 	_ = c.BindJSON(&body)
 
 	currentUser := extensions.GetCurrentUser(c)
-	if !extensions.ValidateBody(c, &body) || !extensions.ValidateHeaders(c, currentUser) {
-		c.AbortWithStatusJSON(400, gin.H{"error": "invalid request in request body or request headers"})
+	if !extensions.ValidateRequest(c, &body, currentUser) {
 		return
 	}
 	user, err := hdl.userService.UpdateCompanyLogo(c.Request.Context(), reference, coompanyReference, body, currentUser)
 	if err != nil {
-		c.AbortWithStatusJSON(500, errorhelper.ErrorMessage(errorhelper.MongoDBError, err.Error()))
+		c.Error(err)
 		return
 	}
 	c.JSON(201, gin.H{"user_reference:": user})
@@ -644,7 +634,7 @@ func (hdl *HTTPHandler) UpdateUserProfileEmailStatus(c *gin.Context) {
 
 	response, err := hdl.userService.UpdateUserProfileEmailStatus(c.Request.Context(), reference)
 	if err != nil {
-		c.AbortWithStatusJSON(500, errorhelper.ErrorMessage(errorhelper.MongoDBError, err.Error()))
+		c.Error(err)
 		return
 	}
 	c.JSON(200, gin.H{"user_reference:": response})
@@ -686,7 +676,7 @@ func (hdl *HTTPHandler) UpdateCompanyProfileEmailStatus(c *gin.Context) {
 
 	response, err := hdl.userService.UpdateCompanyProfileEmailStatus(c.Request.Context(), reference, companyReference)
 	if err != nil {
-		c.AbortWithStatusJSON(500, errorhelper.ErrorMessage(errorhelper.MongoDBError, err.Error()))
+		c.Error(err)
 		return
 	}
 	c.JSON(200, gin.H{"user_reference:": response})
@@ -717,13 +707,12 @@ func (hdl *HTTPHandler) SetDefaultBank(c *gin.Context) {
 
 	currentUser := extensions.GetCurrentUser(c)
 	if !extensions.ValidateHeaders(c, currentUser) {
-		c.AbortWithStatusJSON(400, gin.H{"error": "invalid request in request body or request headers"})
 		return
 	}
 
 	response, err := hdl.userService.SetDefaultBank(c.Request.Context(), reference, bankReference, currentUser)
 	if err != nil {
-		c.AbortWithStatusJSON(500, errorhelper.ErrorMessage(errorhelper.MongoDBError, err.Error()))
+		c.Error(err)
 		return
 	}
 	c.JSON(200, gin.H{"user_reference:": response})
@@ -752,13 +741,12 @@ func (hdl *HTTPHandler) SetDefaultCard(c *gin.Context) {
 
 	currentUser := extensions.GetCurrentUser(c)
 	if !extensions.ValidateHeaders(c, currentUser) {
-		c.AbortWithStatusJSON(400, gin.H{"error": "invalid request in request body or request headers"})
 		return
 	}
 
 	response, err := hdl.userService.SetDefaultCard(c.Request.Context(), reference, cardReference, currentUser)
 	if err != nil {
-		c.AbortWithStatusJSON(500, errorhelper.ErrorMessage(errorhelper.MongoDBError, err.Error()))
+		c.Error(err)
 		return
 	}
 	c.JSON(200, gin.H{"user_reference:": response})
@@ -776,7 +764,7 @@ func (hdl *HTTPHandler) SetDefaultCard(c *gin.Context) {
 func (hdl *HTTPHandler) GetUserByReference(c *gin.Context) {
 	user, err := hdl.userService.GetUserByReference(c.Request.Context(), c.Param("user_reference"))
 	if err != nil {
-		c.AbortWithStatusJSON(500, errorhelper.ErrorMessage(errorhelper.MongoDBError, err.Error()))
+		c.Error(err)
 		return
 	}
 	c.JSON(200, user)
@@ -805,13 +793,12 @@ func (hdl *HTTPHandler) UpdateUserName(c *gin.Context) {
 	_ = c.BindJSON(&body)
 
 	currentUser := extensions.GetCurrentUser(c)
-	if !extensions.ValidateBody(c, &body) || !extensions.ValidateHeaders(c, currentUser) {
-		c.AbortWithStatusJSON(400, gin.H{"error": "invalid request in request body or request headers"})
+	if !extensions.ValidateRequest(c, &body, currentUser) {
 		return
 	}
 	response, err := hdl.userService.UpdateUserName(c.Request.Context(), reference, body, currentUser)
 	if err != nil {
-		c.AbortWithStatusJSON(500, errorhelper.ErrorMessage(errorhelper.MongoDBError, err.Error()))
+		c.Error(err)
 		return
 	}
 	c.JSON(200, gin.H{"user_reference:": response})
@@ -842,13 +829,12 @@ func (hdl *HTTPHandler) UpdateEmail(c *gin.Context) {
 	fmt.Println(body)
 	currentUser := extensions.GetCurrentUser(c)
 	fmt.Println(currentUser)
-	if !extensions.ValidateBody(c, &body) || !extensions.ValidateHeaders(c, currentUser) {
-		c.AbortWithStatusJSON(400, gin.H{"error": "invalid request in request body or request headers"})
+	if !extensions.ValidateRequest(c, &body, currentUser) {
 		return
 	}
 	response, err := hdl.userService.UpdateEmail(c.Request.Context(), reference, body, currentUser)
 	if err != nil {
-		c.AbortWithStatusJSON(500, errorhelper.ErrorMessage(errorhelper.MongoDBError, err.Error()))
+		c.Error(err)
 		return
 	}
 	c.JSON(200, gin.H{"user_reference:": response})
@@ -877,13 +863,12 @@ func (hdl *HTTPHandler) UpdateDateOfBirth(c *gin.Context) {
 	_ = c.BindJSON(&body)
 
 	currentUser := extensions.GetCurrentUser(c)
-	if !extensions.ValidateBody(c, &body) || !extensions.ValidateHeaders(c, currentUser) {
-		c.AbortWithStatusJSON(400, gin.H{"error": "invalid request in request body or request headers"})
+	if !extensions.ValidateRequest(c, &body, currentUser) {
 		return
 	}
 	response, err := hdl.userService.UpdateDateOfBirth(c.Request.Context(), reference, body, currentUser)
 	if err != nil {
-		c.AbortWithStatusJSON(500, errorhelper.ErrorMessage(errorhelper.MongoDBError, err.Error()))
+		c.Error(err)
 		return
 	}
 	c.JSON(200, gin.H{"user_reference:": response})
@@ -912,13 +897,12 @@ func (hdl *HTTPHandler) UpdateAddress(c *gin.Context) {
 	_ = c.BindJSON(&body)
 
 	currentUser := extensions.GetCurrentUser(c)
-	if !extensions.ValidateBody(c, &body) || !extensions.ValidateHeaders(c, currentUser) {
-		c.AbortWithStatusJSON(400, gin.H{"error": "invalid request in request body or request headers"})
+	if !extensions.ValidateRequest(c, &body, currentUser) {
 		return
 	}
 	response, err := hdl.userService.UpdateAddress(c.Request.Context(), reference, body, currentUser)
 	if err != nil {
-		c.AbortWithStatusJSON(500, errorhelper.ErrorMessage(errorhelper.MongoDBError, err.Error()))
+		c.Error(err)
 		return
 	}
 	c.JSON(200, gin.H{"user_reference:": response})
@@ -936,7 +920,7 @@ func (hdl *HTTPHandler) UpdateAddress(c *gin.Context) {
 func (hdl *HTTPHandler) GetUserByWallsTag(c *gin.Context) {
 	user, err := hdl.userService.GetUserByWallsTag(c.Request.Context(), c.Param("wallsTag"))
 	if err != nil {
-		c.AbortWithStatusJSON(500, errorhelper.ErrorMessage(errorhelper.MongoDBError, err.Error()))
+		c.Error(err)
 		return
 	}
 	c.JSON(200, user)
@@ -954,12 +938,51 @@ func (hdl *HTTPHandler) GetUserByWallsTag(c *gin.Context) {
 func (hdl *HTTPHandler) GetUserByWallsBagdeReference(c *gin.Context) {
 	user, err := hdl.userService.GetUserByWallsBagdeReference(c.Request.Context(), c.Param("walls_badge_reference"))
 	if err != nil {
-		c.AbortWithStatusJSON(500, errorhelper.ErrorMessage(errorhelper.MongoDBError, err.Error()))
+		c.Error(err)
 		return
 	}
 	c.JSON(200, user)
 }
 
+// @Summary Search Users
+// @Description Page through users for the back office, filtered, sorted and continued with the next cursor of the previous page
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param is_active query bool false "Active users only, or inactive ones"
+// @Param tier query string false "Tier name"
+// @Param kyc_verified query bool false "Users whose KYC is verified, or not"
+// @Param email_verified query bool false "Users with a verified email, or without one"
+// @Param created_from query string false "Created on or after, 2006-01-02"
+// @Param created_to query string false "Created on or before, 2006-01-02"
+// @Param phone_prefix query string false "Phone number prefix"
+// @Param company_name query string false "Part of a company name"
+// @Param sort query string false "created_on, updated_on or full_name, defaults to created_on"
+// @Param order query string false "asc or desc, defaults to desc"
+// @Param limit query int false "Users per page, defaults to the page limit"
+// @Param cursor query string false "Next cursor of the previous page"
+// @Success 200 {object} dto.UserPageDto "Success"
+// @Failure 400 {object} helper.ErrorResponse
+// @Failure 500 {object} helper.ErrorResponse
+// @Router /api/users [get]
+func (hdl *HTTPHandler) SearchUsers(c *gin.Context) {
+	userSearchDto := dto.UserSearchDto{}
+	if err := c.ShouldBindQuery(&userSearchDto); err != nil {
+		c.AbortWithStatusJSON(400, errorhelper.ErrorFrom(errorhelper.Validation("INVALID_QUERY", err.Error())))
+		return
+	}
+	if !extensions.ValidateBody(c, &userSearchDto) {
+		return
+	}
+
+	users, err := hdl.userService.SearchUsers(c.Request.Context(), userSearchDto)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(200, users)
+}
+
 // @Summary Get User by Phone
 // @Description Get user details by Phone
 // @Tags User
@@ -972,7 +995,7 @@ func (hdl *HTTPHandler) GetUserByWallsBagdeReference(c *gin.Context) {
 func (hdl *HTTPHandler) GetUserByPhone(c *gin.Context) {
 	user, err := hdl.userService.GetUserByPhone(c.Request.Context(), c.Param("phone"))
 	if err != nil {
-		c.AbortWithStatusJSON(500, errorhelper.ErrorMessage(errorhelper.MongoDBError, err.Error()))
+		c.Error(err)
 		return
 	}
 	c.JSON(200, user)
@@ -992,13 +1015,12 @@ func (hdl *HTTPHandler) GetUserByDevice(c *gin.Context) {
 	_ = c.BindJSON(&body)
 
 	if !extensions.ValidateBody(c, &body) {
-		c.AbortWithStatusJSON(400, gin.H{"error": "invalid request in request body or request headers"})
 		return
 	}
 
 	user, err := hdl.userService.GetUserByDevice(c.Request.Context(), body)
 	if err != nil {
-		c.AbortWithStatusJSON(500, errorhelper.ErrorMessage(errorhelper.MongoDBError, err.Error()))
+		c.Error(err)
 		return
 	}
 	c.JSON(200, user)
@@ -1027,13 +1049,12 @@ func (hdl *HTTPHandler) UpdatePhoto(c *gin.Context) {
 	_ = c.BindJSON(&body)
 
 	currentUser := extensions.GetCurrentUser(c)
-	if !extensions.ValidateBody(c, &body) || !extensions.ValidateHeaders(c, currentUser) {
-		c.AbortWithStatusJSON(400, gin.H{"error": "invalid request in request body or request headers"})
+	if !extensions.ValidateRequest(c, &body, currentUser) {
 		return
 	}
 	user, err := hdl.userService.UpdatePhoto(c.Request.Context(), reference, body, currentUser)
 	if err != nil {
-		c.AbortWithStatusJSON(500, errorhelper.ErrorMessage(errorhelper.MongoDBError, err.Error()))
+		c.Error(err)
 		return
 	}
 	c.JSON(200, gin.H{"user_reference": user})
@@ -1062,13 +1083,12 @@ func (hdl *HTTPHandler) UpdateWallet(c *gin.Context) {
 	_ = c.BindJSON(&body)
 
 	currentUser := extensions.GetCurrentUser(c)
-	if !extensions.ValidateBody(c, &body) || !extensions.ValidateHeaders(c, currentUser) {
-		c.AbortWithStatusJSON(400, gin.H{"error": "invalid request in request body or request headers"})
+	if !extensions.ValidateRequest(c, &body, currentUser) {
 		return
 	}
 	user, err := hdl.userService.UpdateWallet(c.Request.Context(), reference, body, currentUser)
 	if err != nil {
-		c.AbortWithStatusJSON(500, errorhelper.ErrorMessage(errorhelper.MongoDBError, err.Error()))
+		c.Error(err)
 		return
 	}
 	c.JSON(200, gin.H{"user_reference": user})
@@ -1097,13 +1117,12 @@ func (hdl *HTTPHandler) AddBank(c *gin.Context) {
 	_ = c.BindJSON(&body)
 
 	currentUser := extensions.GetCurrentUser(c)
-	if !extensions.ValidateBody(c, &body) || !extensions.ValidateHeaders(c, currentUser) {
-		c.AbortWithStatusJSON(400, gin.H{"error": "invalid request in request body or request headers"})
+	if !extensions.ValidateRequest(c, &body, currentUser) {
 		return
 	}
 	user, err := hdl.userService.AddBank(c.Request.Context(), reference, body, currentUser)
 	if err != nil {
-		c.AbortWithStatusJSON(500, errorhelper.ErrorMessage(errorhelper.MongoDBError, err.Error()))
+		c.Error(err)
 		return
 	}
 	c.JSON(201, gin.H{"user_reference": user})
@@ -1134,13 +1153,12 @@ func (hdl *HTTPHandler) UpdateBank(c *gin.Context) {
 	_ = c.BindJSON(&body)
 
 	currentUser := extensions.GetCurrentUser(c)
-	if !extensions.ValidateBody(c, &body) || !extensions.ValidateHeaders(c, currentUser) {
-		c.AbortWithStatusJSON(400, gin.H{"error": "invalid request in request body or request headers"})
+	if !extensions.ValidateRequest(c, &body, currentUser) {
 		return
 	}
 	user, err := hdl.userService.UpdateBank(c.Request.Context(), reference, bank_reference, body, currentUser)
 	if err != nil {
-		c.AbortWithStatusJSON(500, errorhelper.ErrorMessage(errorhelper.MongoDBError, err.Error()))
+		c.Error(err)
 		return
 	}
 	c.JSON(200, gin.H{"user_reference": user})
@@ -1169,13 +1187,12 @@ func (hdl *HTTPHandler) AddCard(c *gin.Context) {
 	_ = c.BindJSON(&body)
 
 	currentUser := extensions.GetCurrentUser(c)
-	if !extensions.ValidateBody(c, &body) || !extensions.ValidateHeaders(c, currentUser) {
-		c.AbortWithStatusJSON(400, gin.H{"error": "invalid request in request body or request headers"})
+	if !extensions.ValidateRequest(c, &body, currentUser) {
 		return
 	}
 	user, err := hdl.userService.AddCard(c.Request.Context(), reference, body, currentUser)
 	if err != nil {
-		c.AbortWithStatusJSON(500, errorhelper.ErrorMessage(errorhelper.MongoDBError, err.Error()))
+		c.Error(err)
 		return
 	}
 	c.JSON(201, gin.H{"user_reference": user})
@@ -1206,13 +1223,12 @@ func (hdl *HTTPHandler) UpdateCard(c *gin.Context) {
 	_ = c.BindJSON(&body)
 
 	currentUser := extensions.GetCurrentUser(c)
-	if !extensions.ValidateBody(c, &body) || !extensions.ValidateHeaders(c, currentUser) {
-		c.AbortWithStatusJSON(400, gin.H{"error": "invalid request in request body or request headers"})
+	if !extensions.ValidateRequest(c, &body, currentUser) {
 		return
 	}
 	user, err := hdl.userService.UpdateCard(c.Request.Context(), reference, card_reference, body, currentUser)
 	if err != nil {
-		c.AbortWithStatusJSON(500, errorhelper.ErrorMessage(errorhelper.MongoDBError, err.Error()))
+		c.Error(err)
 		return
 	}
 	c.JSON(200, gin.H{"user_reference": user})
@@ -1241,20 +1257,19 @@ func (hdl *HTTPHandler) UpdateNotificationOptions(c *gin.Context) {
 	_ = c.BindJSON(&body)
 
 	currentUser := extensions.GetCurrentUser(c)
-	if !extensions.ValidateBody(c, &body) || !extensions.ValidateHeaders(c, currentUser) {
-		c.AbortWithStatusJSON(400, gin.H{"error": "invalid request in request body or request headers"})
+	if !extensions.ValidateRequest(c, &body, currentUser) {
 		return
 	}
 	user, err := hdl.userService.UpdateNotificationOptions(c.Request.Context(), reference, body, currentUser)
 	if err != nil {
-		c.AbortWithStatusJSON(500, errorhelper.ErrorMessage(errorhelper.MongoDBError, err.Error()))
+		c.Error(err)
 		return
 	}
 	c.JSON(200, gin.H{"user_reference": user})
 }
 
 // @Summary Update User's Device
-// @Description Update a User's Device
+// @Description Start binding a new device to a user. The device is trusted once the OTP sent to the registered phone number is validated with otp_type bind_device
 // @Tags User
 // @Accept json
 // @Produce json
@@ -1276,13 +1291,97 @@ func (hdl *HTTPHandler) UpdateDevice(c *gin.Context) {
 	_ = c.BindJSON(&body)
 
 	currentUser := extensions.GetCurrentUser(c)
-	if !extensions.ValidateBody(c, &body) || !extensions.ValidateHeaders(c, currentUser) {
-		c.AbortWithStatusJSON(400, gin.H{"error": "invalid request in request body or request headers"})
+	if !extensions.ValidateRequest(c, &body, currentUser) {
 		return
 	}
 	user, err := hdl.userService.UpdateDevice(c.Request.Context(), reference, body, currentUser)
 	if err != nil {
-		c.AbortWithStatusJSON(500, errorhelper.ErrorMessage(errorhelper.MongoDBError, err.Error()))
+		c.Error(err)
+		return
+	}
+	c.JSON(200, gin.H{"user_reference": user})
+}
+
+// @Summary Request Device Binding
+// @Description Start binding the requesting device to a user who has no trusted device at hand. The device is trusted once the OTP sent to the registered phone number or verified email is validated with otp_type bind_device
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param X-User-Reference header string true "User Reference"
+// @Param X-Phone header string true "Phone"
+// @Param X-Imei header string true "IMEI"
+// @Param X-Device-Type header string true "Device Type"
+// @Param X-Device-Brand header string true "Device Brand"
+// @Param X-Device-Model header string true "Device Model"
+// @Param X-Device-Reference header string true "Device Reference"
+// @Success 200 {string} string "Success"
+// @Failure 500 {object} helper.ErrorResponse
+// @Param user_reference path string true "User reference"
+// @Param requestBody body dto.BindDeviceDto true "Device binding request body"
+// @Router /api/user/{user_reference}/devices/bind [post]
+func (hdl *HTTPHandler) RequestDeviceBinding(c *gin.Context) {
+	reference := c.Param("user_reference")
+	body := dto.BindDeviceDto{}
+	_ = c.BindJSON(&body)
+
+	currentUser := extensions.GetCurrentUser(c)
+	if !extensions.ValidateRequest(c, &body, currentUser) {
+		return
+	}
+	user, err := hdl.userService.RequestDeviceBinding(c.Request.Context(), reference, body, currentUser)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(200, gin.H{"user_reference": user})
+}
+
+// @Summary List User's Devices
+// @Description List the devices registered to a user with their trust status
+// @Tags User
+// @Accept json
+// @Produce json
+// @Success 200 {array} entity.RegisteredDevice "Success"
+// @Failure 500 {object} helper.ErrorResponse
+// @Param user_reference path string true "User reference"
+// @Router /api/user/{user_reference}/devices [get]
+func (hdl *HTTPHandler) ListDevices(c *gin.Context) {
+	devices, err := hdl.userService.ListDevices(c.Request.Context(), c.Param("user_reference"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(200, devices)
+}
+
+// @Summary Revoke User's Device
+// @Description Revoke a device so it can no longer act for the user
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param X-User-Reference header string true "User Reference"
+// @Param X-Phone header string true "Phone"
+// @Param X-Imei header string true "IMEI"
+// @Param X-Device-Type header string true "Device Type"
+// @Param X-Device-Brand header string true "Device Brand"
+// @Param X-Device-Model header string true "Device Model"
+// @Param X-Device-Reference header string true "Device Reference"
+// @Success 200 {string} string "Success"
+// @Failure 500 {object} helper.ErrorResponse
+// @Param user_reference path string true "User reference"
+// @Param device_reference path string true "Device reference"
+// @Router /api/user/{user_reference}/devices/{device_reference}/revoke [put]
+func (hdl *HTTPHandler) RevokeDevice(c *gin.Context) {
+	reference := c.Param("user_reference")
+	deviceReference := c.Param("device_reference")
+
+	currentUser := extensions.GetCurrentUser(c)
+	if !extensions.ValidateHeaders(c, currentUser) {
+		return
+	}
+	user, err := hdl.userService.RevokeDevice(c.Request.Context(), reference, deviceReference, currentUser)
+	if err != nil {
+		c.Error(err)
 		return
 	}
 	c.JSON(200, gin.H{"user_reference": user})
@@ -1311,13 +1410,12 @@ func (hdl *HTTPHandler) AddDocumentation(c *gin.Context) {
 	_ = c.BindJSON(&body)
 
 	currentUser := extensions.GetCurrentUser(c)
-	if !extensions.ValidateBody(c, &body) || !extensions.ValidateHeaders(c, currentUser) {
-		c.AbortWithStatusJSON(400, gin.H{"error": "invalid request in request body or request headers"})
+	if !extensions.ValidateRequest(c, &body, currentUser) {
 		return
 	}
 	documentation, err := hdl.userService.AddDocumentation(c.Request.Context(), reference, body, currentUser)
 	if err != nil {
-		c.AbortWithStatusJSON(500, errorhelper.ErrorMessage(errorhelper.MongoDBError, err.Error()))
+		c.Error(err)
 		return
 	}
 	c.JSON(201, gin.H{"user_reference": documentation})
@@ -1347,13 +1445,12 @@ func (hdl *HTTPHandler) UpdateDocumentation(c *gin.Context) {
 	body := dto.AddDocumentationDto{}
 	_ = c.BindJSON(&body)
 	currentUser := extensions.GetCurrentUser(c)
-	if !extensions.ValidateBody(c, &body) || !extensions.ValidateHeaders(c, currentUser) {
-		c.AbortWithStatusJSON(400, gin.H{"error": "invalid request in request body or request headers"})
+	if !extensions.ValidateRequest(c, &body, currentUser) {
 		return
 	}
 	updatedDocumentation, err := hdl.userService.UpdateDocumentation(c.Request.Context(), reference, documentationReference, body, currentUser)
 	if err != nil {
-		c.AbortWithStatusJSON(500, errorhelper.ErrorMessage(errorhelper.MongoDBError, err.Error()))
+		c.Error(err)
 		return
 	}
 	c.JSON(200, gin.H{"user_reference": updatedDocumentation})
@@ -1381,13 +1478,12 @@ func (hdl *HTTPHandler) AddContact(c *gin.Context) {
 	body := dto.ContactDto{}
 	_ = c.BindJSON(&body)
 	currentUser := extensions.GetCurrentUser(c)
-	if !extensions.ValidateBody(c, &body) || !extensions.ValidateHeaders(c, currentUser) {
-		c.AbortWithStatusJSON(400, gin.H{"error": "invalid request in request body or request headers"})
+	if !extensions.ValidateRequest(c, &body, currentUser) {
 		return
 	}
 	contact, err := hdl.userService.AddContact(c.Request.Context(), reference, body, currentUser)
 	if err != nil {
-		c.AbortWithStatusJSON(500, errorhelper.ErrorMessage(errorhelper.MongoDBError, err.Error()))
+		c.Error(err)
 		return
 	}
 	c.JSON(201, gin.H{"user_reference": contact})
@@ -1398,13 +1494,6 @@ func (hdl *HTTPHandler) AddContact(c *gin.Context) {
 // @Tags User
 // @Accept json
 // @Produce json
-// @Param X-User-Reference header string true "User Reference"
-// @Param X-Phone header string true "Phone"
-// @Param X-Imei header string true "IMEI"
-// @Param X-Device-Type header string true "Device Type"
-// @Param X-Device-Brand header string true "Device Brand"
-// @Param X-Device-Model header string true "Device Model"
-// @Param X-Device-Reference header string true "Device Reference"
 // @Param user_reference path string true "User reference"
 // @Param requestBody body dto.BalanceDto true "Balance request body"
 // @Success 200 {string} string "Success"
@@ -1419,15 +1508,13 @@ func (hdl *HTTPHandler) UpdateBalance(c *gin.Context) {
 		return
 	}
 
-	currentUser := extensions.GetCurrentUser(c)
-	if !extensions.ValidateBody(c, &body) || !extensions.ValidateHeaders(c, currentUser) {
-		c.AbortWithStatusJSON(400, gin.H{"error": "invalid request in request body or request headers"})
+	if !extensions.ValidateBody(c, &body) {
 		return
 	}
 
-	result, err := hdl.userService.UpdateBalance(c.Request.Context(), reference, body, currentUser)
+	result, err := hdl.userService.UpdateBalance(c.Request.Context(), reference, body)
 	if err != nil {
-		c.AbortWithStatusJSON(500, errorhelper.ErrorMessage(errorhelper.MongoDBError, err.Error()))
+		c.Error(err)
 		return
 	}
 	c.JSON(200, gin.H{"user_reference": result})
@@ -1438,13 +1525,6 @@ func (hdl *HTTPHandler) UpdateBalance(c *gin.Context) {
 // @Tags User
 // @Accept json
 // @Produce json
-// @Param X-User-Reference header string true "User Reference"
-// @Param X-Phone header string true "Phone"
-// @Param X-Imei header string true "IMEI"
-// @Param X-Device-Type header string true "Device Type"
-// @Param X-Device-Brand header string true "Device Brand"
-// @Param X-Device-Model header string true "Device Model"
-// @Param X-Device-Reference header string true "Device Reference"
 // @Param user_reference path string true "User reference"
 // @Param requestBody body dto.TierDto true "Tier request body"
 // @Success 200 {string} interface{} "Success"
@@ -1455,15 +1535,13 @@ func (hdl *HTTPHandler) UpdateTier(c *gin.Context) {
 	body := dto.TierDto{}
 	_ = c.BindJSON(&body)
 
-	currentUser := extensions.GetCurrentUser(c)
-	if !extensions.ValidateBody(c, &body) || !extensions.ValidateHeaders(c, currentUser) {
-		c.AbortWithStatusJSON(400, gin.H{"error": "invalid request in request body or request headers"})
+	if !extensions.ValidateBody(c, &body) {
 		return
 	}
 
-	result, err := hdl.userService.UpdateTier(c.Request.Context(), reference, body, currentUser)
+	result, err := hdl.userService.UpdateTier(c.Request.Context(), reference, body)
 	if err != nil {
-		c.AbortWithStatusJSON(500, errorhelper.ErrorMessage(errorhelper.MongoDBError, err.Error()))
+		c.Error(err)
 		return
 	}
 
@@ -1487,7 +1565,7 @@ func (hdl *HTTPHandler) AddCoupon(c *gin.Context) {
 
 	result, err := hdl.userService.AddCoupon(c.Request.Context(), reference, body)
 	if err != nil {
-		c.AbortWithStatusJSON(500, errorhelper.ErrorMessage(errorhelper.MongoDBError, err.Error()))
+		c.Error(err)
 		return
 	}
 
@@ -1495,13 +1573,15 @@ func (hdl *HTTPHandler) AddCoupon(c *gin.Context) {
 }
 
 // @Summary Update User Rewards
-// @Description Update the user's rewards
+// @Description Add points to the user's rewards. The points are added to the current total rather than replacing it, so a repeated request adds them again.
 // @Tags User
 // @Accept json
 // @Produce json
 // @Param user_reference path string true "User reference"
 // @Param requestBody body dto.RewardDto true "Reward request body"
 // @Success 200 {string} interface{} "Success"
+// @Failure 400 {object} helper.ErrorResponse
+// @Failure 404 {object} helper.ErrorResponse
 // @Failure 500 {object} helper.ErrorResponse
 // @Router /api/user/{user_reference}/reward [put]
 func (hdl *HTTPHandler) UpdateRewards(c *gin.Context) {
@@ -1509,9 +1589,13 @@ func (hdl *HTTPHandler) UpdateRewards(c *gin.Context) {
 	body := dto.RewardDto{}
 	_ = c.BindJSON(&body)
 
+	if !extensions.ValidateBody(c, &body) {
+		return
+	}
+
 	result, err := hdl.userService.UpdateRewards(c.Request.Context(), reference, body)
 	if err != nil {
-		c.AbortWithStatusJSON(500, errorhelper.ErrorMessage(errorhelper.MongoDBError, err.Error()))
+		c.Error(err)
 		return
 	}
 
@@ -1533,7 +1617,7 @@ func (hdl *HTTPHandler) EnableUser(c *gin.Context) {
 
 	result, err := hdl.userService.EnableUser(c.Request.Context(), reference)
 	if err != nil {
-		c.AbortWithStatusJSON(500, errorhelper.ErrorMessage(errorhelper.MongoDBError, err.Error()))
+		c.Error(err)
 		return
 	}
 
@@ -1554,7 +1638,7 @@ func (hdl *HTTPHandler) DisableUser(c *gin.Context) {
 
 	result, err := hdl.userService.DisableUser(c.Request.Context(), reference)
 	if err != nil {
-		c.AbortWithStatusJSON(500, errorhelper.ErrorMessage(errorhelper.MongoDBError, err.Error()))
+		c.Error(err)
 		return
 	}
 
@@ -0,0 +1,103 @@
+package outbox
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"time"
+	"walls-user-service/internal/core/domain/entity"
+	configuration "walls-user-service/internal/core/helper/configuration-helper"
+	helper "walls-user-service/internal/core/helper/event-helper"
+	logger "walls-user-service/internal/core/helper/log-helper"
+	ports "walls-user-service/internal/port"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var (
+	defaultPollInterval = 5 * time.Second
+	defaultBatchSize    = int64(50)
+	maxRetryBackoff     = 5 * time.Minute
+)
+
+type OutboxRelay struct {
+	outboxRepository ports.OutboxRepository
+	redisClient      *redis.Client
+}
+
+func NewOutboxRelay(outboxRepository ports.OutboxRepository, redisClient *redis.Client) *OutboxRelay {
+	return &OutboxRelay{
+		outboxRepository: outboxRepository,
+		redisClient:      redisClient,
+	}
+}
+
+// Start - drains pending outbox events into the event bus until the context is
+// cancelled. Events are marked dispatched only after a successful publish, so
+// each is delivered at least once.
+func (r *OutboxRelay) Start(ctx context.Context) error {
+	ticker := time.NewTicker(pollInterval())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			r.dispatchPending(ctx)
+		}
+	}
+}
+
+func (r *OutboxRelay) dispatchPending(ctx context.Context) {
+	outboxData, err := r.outboxRepository.GetPendingOutboxEvents(ctx, batchSize())
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to fetch pending outbox events: "+err.Error())
+		return
+	}
+
+	redisHelper := helper.NewRedisClient(r.redisClient)
+	for _, outboxEvent := range outboxData.([]entity.OutboxEvent) {
+		err := redisHelper.PublishToChannel(ctx, outboxEvent.Channel, []byte(outboxEvent.Payload))
+		if err != nil {
+			nextAttemptOn := time.Now().UTC().Add(retryBackoff(outboxEvent.Attempts + 1)).Format(time.RFC3339)
+			logger.LogEvent("ERROR", "Failed to dispatch outbox event "+outboxEvent.EventReference+", retrying at "+nextAttemptOn+": "+err.Error())
+			_, err = r.outboxRepository.MarkOutboxEventFailed(ctx, outboxEvent.OutboxReference, err.Error(), nextAttemptOn)
+			if err != nil {
+				logger.LogEvent("ERROR", "Failed to record outbox event failure "+outboxEvent.OutboxReference+": "+err.Error())
+			}
+			continue
+		}
+
+		_, err = r.outboxRepository.MarkOutboxEventDispatched(ctx, outboxEvent.OutboxReference)
+		if err != nil {
+			// The event will be published again; consumers see the same EventReference.
+			logger.LogEvent("ERROR", "Failed to mark outbox event "+outboxEvent.OutboxReference+" dispatched: "+err.Error())
+		}
+	}
+}
+
+// retryBackoff - exponential backoff from one second, capped at maxRetryBackoff.
+func retryBackoff(attempts int) time.Duration {
+	backoff := time.Duration(math.Pow(2, float64(attempts-1))) * time.Second
+	if backoff <= 0 || backoff > maxRetryBackoff {
+		return maxRetryBackoff
+	}
+	return backoff
+}
+
+func pollInterval() time.Duration {
+	seconds, err := strconv.Atoi(configuration.ServiceConfiguration.OutboxPollInterval)
+	if err != nil || seconds <= 0 {
+		return defaultPollInterval
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+func batchSize() int64 {
+	size, err := strconv.ParseInt(configuration.ServiceConfiguration.OutboxBatchSize, 10, 64)
+	if err != nil || size <= 0 {
+		return defaultBatchSize
+	}
+	return size
+}
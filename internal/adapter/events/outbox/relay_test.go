@@ -0,0 +1,103 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	memoryRepository "walls-user-service/internal/adapter/repository/memory"
+	"walls-user-service/internal/core/domain/entity"
+	"walls-user-service/internal/core/domain/shared"
+	configuration "walls-user-service/internal/core/helper/configuration-helper"
+	"walls-user-service/internal/core/helper/event-helper/redistest"
+)
+
+// failureRecordingOutbox - a memory outbox that records the failures marked on it.
+type failureRecordingOutbox struct {
+	*memoryRepository.OutboxInfra
+	nextAttemptOn map[string]string
+}
+
+func (r *failureRecordingOutbox) MarkOutboxEventFailed(ctx context.Context, outboxReference string, lastError string, nextAttemptOn string) (interface{}, error) {
+	r.nextAttemptOn[outboxReference] = nextAttemptOn
+	return r.OutboxInfra.MarkOutboxEventFailed(ctx, outboxReference, lastError, nextAttemptOn)
+}
+
+func newOutboxEvent(t *testing.T, outboxRepository *failureRecordingOutbox, outboxReference string, channel string, createdOn time.Time) {
+	t.Helper()
+	outboxEvent := entity.OutboxEvent{
+		OutboxReference: outboxReference,
+		EventReference:  outboxReference + "-event",
+		Channel:         channel,
+		Payload:         `{"EventReference":"` + outboxReference + `-event"}`,
+		Status:          shared.OutboxPending,
+		CreatedOn:       createdOn.UTC().Format(time.RFC3339),
+		NextAttemptOn:   createdOn.UTC().Format(time.RFC3339),
+	}
+	if _, err := outboxRepository.CreateOutboxEvent(context.Background(), outboxEvent); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func pendingOutboxEvents(t *testing.T, outboxRepository *failureRecordingOutbox) []entity.OutboxEvent {
+	t.Helper()
+	outboxEvents, err := outboxRepository.GetPendingOutboxEvents(context.Background(), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return outboxEvents.([]entity.OutboxEvent)
+}
+
+func TestRelayPublishesPendingEventsAndBacksOffFailures(t *testing.T) {
+	transport := configuration.ServiceConfiguration.EBTransport
+	configuration.ServiceConfiguration.EBTransport = "streams"
+	defer func() { configuration.ServiceConfiguration.EBTransport = transport }()
+
+	outboxRepository := &failureRecordingOutbox{OutboxInfra: memoryRepository.NewOutbox(), nextAttemptOn: map[string]string{}}
+	client, store := redistest.NewClient()
+	relay := NewOutboxRelay(outboxRepository, client)
+	ctx := context.Background()
+
+	now := time.Now()
+	newOutboxEvent(t, outboxRepository, "outbox-2", "USERUPDATEDEVENT", now.Add(-time.Second))
+	newOutboxEvent(t, outboxRepository, "outbox-1", "USERUPDATEDEVENT", now.Add(-time.Minute))
+	relay.dispatchPending(ctx)
+
+	entries := store.Entries("USERUPDATEDEVENT")
+	if len(entries) != 2 || entries[0].Values["payload"] != `{"EventReference":"outbox-1-event"}` {
+		t.Fatalf("expected the events to be published oldest first, got %+v", entries)
+	}
+	if pending := pendingOutboxEvents(t, outboxRepository); len(pending) != 0 {
+		t.Errorf("expected published events to be marked dispatched, got %+v", pending)
+	}
+
+	store.Fail("xadd", errors.New("connection refused"))
+	newOutboxEvent(t, outboxRepository, "outbox-3", "BANKUPDATEDEVENT", now)
+	relay.dispatchPending(ctx)
+
+	nextAttemptOn, err := time.Parse(time.RFC3339, outboxRepository.nextAttemptOn["outbox-3"])
+	if err != nil {
+		t.Fatalf("expected the failed event to be scheduled again, got %v", err)
+	}
+	if wait := time.Until(nextAttemptOn); wait < -time.Second || wait > 2*time.Second {
+		t.Errorf("expected the first retry about a second later, got %v", wait)
+	}
+	if pending := pendingOutboxEvents(t, outboxRepository); len(pending) != 0 {
+		t.Errorf("expected the failed event to wait for its retry, got %+v", pending)
+	}
+}
+
+func TestRelayRetryBackoff(t *testing.T) {
+	for attempts, expected := range map[int]time.Duration{
+		1:   time.Second,
+		2:   2 * time.Second,
+		3:   4 * time.Second,
+		9:   256 * time.Second,
+		10:  maxRetryBackoff,
+		100: maxRetryBackoff,
+	} {
+		if backoff := retryBackoff(attempts); backoff != expected {
+			t.Errorf("attempt %d: expected a backoff of %v, got %v", attempts, expected, backoff)
+		}
+	}
+}
@@ -2,7 +2,9 @@ package publisher
 
 import (
 	"context"
+	event "walls-user-service/internal/core/domain/event/eto"
 	helper "walls-user-service/internal/core/helper/event-helper"
+	eto "walls-user-service/internal/core/helper/event-helper/eto"
 
 	"github.com/redis/go-redis/v9"
 )
@@ -17,239 +19,19 @@ func NewPublisher(redisClient *redis.Client) *EventPublisher {
 	}
 }
 
-func (p *EventPublisher) PublishUserCreatedEvent(ctx context.Context, event interface{}, eventType ...string) error {
-	redisHelper := helper.NewRedisClient(p.redisClient)
-	return redisHelper.PublishEvent(ctx, event)
-}
-
-func (p *EventPublisher) PublishWallsTagCreatedEvent(ctx context.Context, event interface{}, eventType ...string) error {
-	redisHelper := helper.NewRedisClient(p.redisClient)
-	return redisHelper.PublishEvent(ctx, event)
-}
-func (p *EventPublisher) PublishUserNameUpdatedEvent(ctx context.Context, event interface{}, eventType ...string) error {
-	redisHelper := helper.NewRedisClient(p.redisClient)
-	return redisHelper.PublishEvent(ctx, event)
-}
-
-func (p *EventPublisher) PublishEmailUpdatedEvent(ctx context.Context, event interface{}, eventType ...string) error {
-	redisHelper := helper.NewRedisClient(p.redisClient)
-	return redisHelper.PublishEvent(ctx, event)
-}
-
-func (p *EventPublisher) PublishDobUpdatedEvent(ctx context.Context, event interface{}, eventType ...string) error {
-	redisHelper := helper.NewRedisClient(p.redisClient)
-	return redisHelper.PublishEvent(ctx, event)
-}
-
-func (p *EventPublisher) PublishAddressUpdatedEvent(ctx context.Context, event interface{}, eventType ...string) error {
-	redisHelper := helper.NewRedisClient(p.redisClient)
-	return redisHelper.PublishEvent(ctx, event)
-}
-
-func (p *EventPublisher) PublishPhotoUpdatedEvent(ctx context.Context, event interface{}, eventType ...string) error {
-	redisHelper := helper.NewRedisClient(p.redisClient)
-	return redisHelper.PublishEvent(ctx, event)
-}
-
-func (p *EventPublisher) PublishWalletUpdatedEvent(ctx context.Context, event interface{}, eventType ...string) error {
-	redisHelper := helper.NewRedisClient(p.redisClient)
-	return redisHelper.PublishEvent(ctx, event)
-}
-
-func (p *EventPublisher) PublishBankAddedEvent(ctx context.Context, event interface{}, eventType ...string) error {
-	redisHelper := helper.NewRedisClient(p.redisClient)
-	return redisHelper.PublishEvent(ctx, event)
-}
-
-func (p *EventPublisher) PublishBankUpdatedEvent(ctx context.Context, event interface{}, eventType ...string) error {
-	redisHelper := helper.NewRedisClient(p.redisClient)
-	return redisHelper.PublishEvent(ctx, event)
-}
-
-func (p *EventPublisher) PublishCardAddedEvent(ctx context.Context, event interface{}, eventType ...string) error {
-	redisHelper := helper.NewRedisClient(p.redisClient)
-	return redisHelper.PublishEvent(ctx, event)
-}
-
-func (p *EventPublisher) PublishCardUpdatedEvent(ctx context.Context, event interface{}, eventType ...string) error {
-	redisHelper := helper.NewRedisClient(p.redisClient)
-	return redisHelper.PublishEvent(ctx, event)
-}
-
-func (p *EventPublisher) PublishNotificationOptionsUpdatedEvent(ctx context.Context, event interface{}, eventType ...string) error {
-	redisHelper := helper.NewRedisClient(p.redisClient)
-	return redisHelper.PublishEvent(ctx, event)
-}
-
-func (p *EventPublisher) PublishDeviceUpdatedEvent(ctx context.Context, event interface{}, eventType ...string) error {
-	redisHelper := helper.NewRedisClient(p.redisClient)
-	return redisHelper.PublishEvent(ctx, event)
-}
-
-func (p *EventPublisher) PublishDocumentationAddedEvent(ctx context.Context, event interface{}, eventType ...string) error {
-	redisHelper := helper.NewRedisClient(p.redisClient)
-	return redisHelper.PublishEvent(ctx, event)
-}
-
-func (p *EventPublisher) PublishDocumentationUpdatedEvent(ctx context.Context, event interface{}, eventType ...string) error {
-	redisHelper := helper.NewRedisClient(p.redisClient)
-	return redisHelper.PublishEvent(ctx, event)
-}
-
-func (p *EventPublisher) PublishContactAddedEvent(ctx context.Context, event interface{}, eventType ...string) error {
-	redisHelper := helper.NewRedisClient(p.redisClient)
-	return redisHelper.PublishEvent(ctx, event)
-}
-
-func (p *EventPublisher) PublishCompanyProfileCreatedEvent(ctx context.Context, event interface{}, eventType ...string) error {
-
-	redisHelper := helper.NewRedisClient(p.redisClient)
-	return redisHelper.PublishEvent(ctx, event)
-
-}
-
-func (p *EventPublisher) PublishCompanyWallsBadgeCreatedEvent(ctx context.Context, event interface{}, eventType ...string) error {
-	redisHelper := helper.NewRedisClient(p.redisClient)
-	return redisHelper.PublishEvent(ctx, event)
-}
-func (p *EventPublisher) PublishUserWallsBadgeCreatedEvent(ctx context.Context, event interface{}, eventType ...string) error {
-	redisHelper := helper.NewRedisClient(p.redisClient)
-	return redisHelper.PublishEvent(ctx, event)
-}
-
-func (p *EventPublisher) PublishCompanyProfileUpdatedEvent(ctx context.Context, event interface{}, eventType ...string) error {
-	redisHelper := helper.NewRedisClient(p.redisClient)
-	return redisHelper.PublishEvent(ctx, event)
-}
-
-func (p *EventPublisher) PublishCompanyWallsBadgeDisabledEvent(ctx context.Context, event interface{}, eventType ...string) error {
-	redisHelper := helper.NewRedisClient(p.redisClient)
-	return redisHelper.PublishEvent(ctx, event)
-}
-
-func (p *EventPublisher) PublishUserWallsBadgeDisabledEvent(ctx context.Context, event interface{}, eventType ...string) error {
-	redisHelper := helper.NewRedisClient(p.redisClient)
-	return redisHelper.PublishEvent(ctx, event)
-}
-
-func (p *EventPublisher) PublishCompanyProfileDisabledEvent(ctx context.Context, event interface{}, eventType ...string) error {
-	redisHelper := helper.NewRedisClient(p.redisClient)
-	return redisHelper.PublishEvent(ctx, event)
-}
-
-func (p *EventPublisher) PublishCompanyLogoUpdatedEvent(ctx context.Context, event interface{}, eventType ...string) error {
-	redisHelper := helper.NewRedisClient(p.redisClient)
-	return redisHelper.PublishEvent(ctx, event)
-}
-
-func (p *EventPublisher) PublishUserProfileEmailStatusUpdatedEvent(ctx context.Context, event interface{}, eventType ...string) error {
-	redisHelper := helper.NewRedisClient(p.redisClient)
-	return redisHelper.PublishEvent(ctx, event)
-}
-
-func (p *EventPublisher) PublishUserProfilePhoneStatusUpdatedEvent(ctx context.Context, event interface{}, eventType ...string) error {
-	redisHelper := helper.NewRedisClient(p.redisClient)
-	return redisHelper.PublishEvent(ctx, event)
-}
-
-func (p *EventPublisher) PublishCompanyProfileEmailStatusUpdatedEvent(ctx context.Context, event interface{}, eventType ...string) error {
-	redisHelper := helper.NewRedisClient(p.redisClient)
-	return redisHelper.PublishEvent(ctx, event)
-}
-
-func (p *EventPublisher) PublishKycStatusUpdatedEvent(ctx context.Context, event interface{}, eventType ...string) error {
-	redisHelper := helper.NewRedisClient(p.redisClient)
-	return redisHelper.PublishEvent(ctx, event)
-}
-
-func (p *EventPublisher) PublishDefaultBankSetEvent(ctx context.Context, event interface{}, eventType ...string) error {
-	redisHelper := helper.NewRedisClient(p.redisClient)
-	return redisHelper.PublishEvent(ctx, event)
-}
-
-func (p *EventPublisher) PublishDefaultCardSetEvent(ctx context.Context, event interface{}, eventType ...string) error {
-	redisHelper := helper.NewRedisClient(p.redisClient)
-	return redisHelper.PublishEvent(ctx, event)
-}
-func (p *EventPublisher) PublishUsernameUpdatedEvent(ctx context.Context, event interface{}, eventType ...string) error {
-	redisHelper := helper.NewRedisClient(p.redisClient)
-	return redisHelper.PublishEvent(ctx, event)
-}
-
-func (p *EventPublisher) PublishDOBUpdatedEvent(ctx context.Context, event interface{}, eventType ...string) error {
-	redisHelper := helper.NewRedisClient(p.redisClient)
-	return redisHelper.PublishEvent(ctx, event)
-}
-
-func (p *EventPublisher) PublishPhotosUpdatedEvent(ctx context.Context, event interface{}, eventType ...string) error {
-	redisHelper := helper.NewRedisClient(p.redisClient)
-	return redisHelper.PublishEvent(ctx, event)
-}
-
-func (p *EventPublisher) PublishBalanceUpdatedEvent(ctx context.Context, event interface{}, eventType ...string) error {
-	redisHelper := helper.NewRedisClient(p.redisClient)
-	return redisHelper.PublishEvent(ctx, event)
-}
-
-func (p *EventPublisher) PublishTierUpdatedEvent(ctx context.Context, event interface{}, eventType ...string) error {
-	redisHelper := helper.NewRedisClient(p.redisClient)
-	return redisHelper.PublishEvent(ctx, event)
-}
-
-func (p *EventPublisher) PublishCouponAddedEvent(ctx context.Context, event interface{}, eventType ...string) error {
-	redisHelper := helper.NewRedisClient(p.redisClient)
-	return redisHelper.PublishEvent(ctx, event)
-}
-
-func (p *EventPublisher) PublishRewardsUpdatedEvent(ctx context.Context, event interface{}, eventType ...string) error {
-	redisHelper := helper.NewRedisClient(p.redisClient)
-	return redisHelper.PublishEvent(ctx, event)
-}
-
-func (p *EventPublisher) PublishKycScoreUpdatedEvent(ctx context.Context, event interface{}, eventType ...string) error {
-	redisHelper := helper.NewRedisClient(p.redisClient)
-	return redisHelper.PublishEvent(ctx, event)
-}
-
-func (p *EventPublisher) PublishUserEnabledEvent(ctx context.Context, event interface{}, eventType ...string) error {
-	redisHelper := helper.NewRedisClient(p.redisClient)
-	return redisHelper.PublishEvent(ctx, event)
-}
-
-func (p *EventPublisher) PublishUserDisabledEvent(ctx context.Context, event interface{}, eventType ...string) error {
-	redisHelper := helper.NewRedisClient(p.redisClient)
-	return redisHelper.PublishEvent(ctx, event)
-}
-func (p *EventPublisher) PublishOnboardingCreateRequestEvent(ctx context.Context, event interface{}, eventType ...string) error {
-	redisHelper := helper.NewRedisClient(p.redisClient)
-	return redisHelper.PublishEvent(ctx, event)
-}
-
-func (p *EventPublisher) PublishCreateIdentityRequestEvent(ctx context.Context, event interface{}, eventType ...string) error {
-	redisHelper := helper.NewRedisClient(p.redisClient)
-	return redisHelper.PublishEvent(ctx, event)
-}
-
-func (p *EventPublisher) PublishValidateOtpRequestEvent(ctx context.Context, event interface{}, eventType ...string) error {
-	redisHelper := helper.NewRedisClient(p.redisClient)
-	return redisHelper.PublishEvent(ctx, event)
-}
-
-func (p *EventPublisher) PublishCreateOtpRequestEvent(ctx context.Context, event interface{}, eventType ...string) error {
-	redisHelper := helper.NewRedisClient(p.redisClient)
-	return redisHelper.PublishEvent(ctx, event, eventType...)
-}
+// Publish - publishes any registered event on the channel the event registry
+// holds for its type, suffixed with the event type when one is given.
+func (p *EventPublisher) Publish(ctx context.Context, domainEvent interface{ Envelope() eto.Event }, eventType ...string) error {
+	channel, err := event.ChannelFor(domainEvent, eventType...)
+	if err != nil {
+		return err
+	}
 
-func (p *EventPublisher) PublishValidateIdentityRequestEvent(ctx context.Context, event interface{}, eventType ...string) error {
-	redisHelper := helper.NewRedisClient(p.redisClient)
-	return redisHelper.PublishEvent(ctx, event)
-}
+	eventBytes, err := helper.EncodeEvent(domainEvent, channel)
+	if err != nil {
+		return err
+	}
 
-func (p *EventPublisher) PublishUpgradeTierRequestEvent(ctx context.Context, event interface{}, eventType ...string) error {
-	redisHelper := helper.NewRedisClient(p.redisClient)
-	return redisHelper.PublishEvent(ctx, event)
-}
-func (p *EventPublisher) PublishCreateTransactionRequestEvent(ctx context.Context, event interface{}, eventType ...string) error {
 	redisHelper := helper.NewRedisClient(p.redisClient)
-	return redisHelper.PublishEvent(ctx, event)
+	return redisHelper.PublishToChannel(ctx, channel, eventBytes)
 }
@@ -0,0 +1,78 @@
+package subscriber
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	helper "walls-user-service/internal/core/helper/event-helper"
+	eto "walls-user-service/internal/core/helper/event-helper/eto"
+	logger "walls-user-service/internal/core/helper/log-helper"
+)
+
+// withIdempotency - wraps an event handler so an event whose EventReference has
+// already been processed within the retention window is skipped and counted
+// rather than handled again. A failed handler releases the event, so retries
+// and dead-letter replays still reach it.
+func (s *EventSubscriber) withIdempotency(eventHandler helper.EventHandler) helper.EventHandler {
+	return func(ctx context.Context, event interface{}) error {
+		eventReference := eventReferenceOf(event)
+		if eventReference == "" {
+			logger.LogEvent("ERROR", "Event from "+helper.EventChannel(ctx)+" has no event reference, handling without de-duplication")
+			return eventHandler(ctx, event)
+		}
+
+		claimed, err := s.processedEventRepository.ClaimEvent(ctx, eventReference)
+		if err != nil {
+			return fmt.Errorf("failed to check event %s for duplicates: %v", eventReference, err)
+		}
+		if !claimed {
+			s.countDuplicate(ctx, eventReference)
+			return nil
+		}
+
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				s.releaseEvent(ctx, eventReference)
+				panic(recovered)
+			}
+		}()
+
+		err = eventHandler(ctx, event)
+		if err != nil {
+			s.releaseEvent(ctx, eventReference)
+			return err
+		}
+
+		if _, markErr := s.processedEventRepository.MarkEventProcessed(ctx, eventReference); markErr != nil {
+			// The handler has succeeded; the processing lease still holds off
+			// redeliveries until it lapses.
+			logger.LogEvent("ERROR", "Failed to mark event "+eventReference+" as processed: "+markErr.Error())
+		}
+		return nil
+	}
+}
+
+func (s *EventSubscriber) releaseEvent(ctx context.Context, eventReference string) {
+	if _, err := s.processedEventRepository.ReleaseEvent(ctx, eventReference); err != nil {
+		logger.LogEvent("ERROR", "Failed to release event "+eventReference+": "+err.Error())
+	}
+}
+
+func (s *EventSubscriber) countDuplicate(ctx context.Context, eventReference string) {
+	channel := helper.EventChannel(ctx)
+	count, err := s.processedEventRepository.CountDuplicateEvent(ctx, channel)
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to count duplicate event "+eventReference+": "+err.Error())
+		count = "unknown"
+	}
+	logger.LogEvent("INFO", fmt.Sprintf("Skipping duplicate event %s from %s (%v duplicates on this channel)", eventReference, channel, count))
+}
+
+func eventReferenceOf(event interface{}) string {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return ""
+	}
+	envelope, _ := eto.DecodeEvent(payload)
+	return envelope.EventReference
+}
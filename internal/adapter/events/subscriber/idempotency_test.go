@@ -0,0 +1,115 @@
+package subscriber
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	helper "walls-user-service/internal/core/helper/event-helper"
+)
+
+func TestProcessedEventsAreSkippedAndCounted(t *testing.T) {
+	s, store := newTestSubscriber(t, helper.StreamsTransport, "5")
+	ctx := context.Background()
+	retention := 7 * 24 * time.Hour // the default retention
+
+	handled := 0
+	eventHandler := s.withIdempotency(func(ctx context.Context, event interface{}) error {
+		handled++
+		return nil
+	})
+
+	for i := 0; i < 2; i++ {
+		if err := eventHandler(ctx, map[string]interface{}{"EventReference": "event-1"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if handled != 1 {
+		t.Errorf("expected the redelivered event to be skipped, handled %d times", handled)
+	}
+	status, ttl, _ := store.Get("PROCESSEDEVENT:WALLS-USER-SERVICE:event-1")
+	if status != "processed" || ttl != retention {
+		t.Errorf("expected the event to be kept as processed for the retention window, got %q for %v", status, ttl)
+	}
+	if duplicates := store.HashField("PROCESSEDEVENT:WALLS-USER-SERVICE:DUPLICATES", ""); duplicates != 1 {
+		t.Errorf("expected one duplicate to be counted, got %d", duplicates)
+	}
+
+	// Once the retention window has passed the event is handled again.
+	store.Advance(retention)
+	if err := eventHandler(ctx, map[string]interface{}{"EventReference": "event-1"}); err != nil {
+		t.Fatal(err)
+	}
+	if handled != 2 {
+		t.Errorf("expected an event past the retention window to be handled, handled %d times", handled)
+	}
+
+	// Without a reference there is nothing to de-duplicate on.
+	for i := 0; i < 2; i++ {
+		if err := eventHandler(ctx, map[string]interface{}{"EventName": "bankverifiedevent"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if handled != 4 {
+		t.Errorf("expected events without a reference to be handled every time, handled %d times", handled)
+	}
+}
+
+func TestFailedEventsAreReleasedForTheirRetry(t *testing.T) {
+	s, store := newTestSubscriber(t, helper.StreamsTransport, "5")
+	ctx := context.Background()
+	event := map[string]interface{}{"EventReference": "event-1"}
+
+	failure := errors.New("user not found")
+	if err := s.withIdempotency(func(ctx context.Context, event interface{}) error { return failure })(ctx, event); !errors.Is(err, failure) {
+		t.Fatalf("expected the handler failure to be returned, got %v", err)
+	}
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("expected the panic to reach the retry policy")
+			}
+		}()
+		s.withIdempotency(func(ctx context.Context, event interface{}) error { panic("nil map") })(ctx, event)
+	}()
+	if _, _, claimed := store.Get("PROCESSEDEVENT:WALLS-USER-SERVICE:event-1"); claimed {
+		t.Fatal("expected a failed event to be released")
+	}
+
+	handled := false
+	if err := s.withIdempotency(func(ctx context.Context, event interface{}) error { handled = true; return nil })(ctx, event); err != nil || !handled {
+		t.Errorf("expected the retry to be handled, got %v", err)
+	}
+}
+
+func TestEventsBeingHandledElsewhereAreSkippedUntilTheirLeaseLapses(t *testing.T) {
+	s, store := newTestSubscriber(t, helper.StreamsTransport, "5")
+	ctx := context.Background()
+	event := map[string]interface{}{"EventReference": "event-1"}
+
+	// Another consumer has claimed the event and not yet finished with it.
+	if claimed, err := s.processedEventRepository.ClaimEvent(ctx, "event-1"); err != nil || !claimed {
+		t.Fatalf("expected the event to be claimed, got %v, %v", claimed, err)
+	}
+
+	handled := 0
+	eventHandler := s.withIdempotency(func(ctx context.Context, event interface{}) error {
+		handled++
+		return nil
+	})
+	if err := eventHandler(ctx, event); err != nil || handled != 0 {
+		t.Fatalf("expected a claimed event to be skipped, got %v after %d", err, handled)
+	}
+
+	// The claim lasts for the claim window, after which a consumer that died
+	// mid-event no longer holds it.
+	store.Advance(time.Second)
+	if err := eventHandler(ctx, event); err != nil || handled != 1 {
+		t.Errorf("expected the event to be handled once its lease lapsed, got %v after %d", err, handled)
+	}
+
+	store.Fail("set", errors.New("connection refused"))
+	if err := eventHandler(ctx, map[string]interface{}{"EventReference": "event-2"}); err == nil || handled != 1 {
+		t.Errorf("expected an event that cannot be checked for duplicates to fail unhandled, got %v after %d", err, handled)
+	}
+}
@@ -0,0 +1,134 @@
+package subscriber
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"runtime/debug"
+	"strconv"
+	"time"
+	"walls-user-service/internal/core/domain/entity"
+	configuration "walls-user-service/internal/core/helper/configuration-helper"
+	helper "walls-user-service/internal/core/helper/event-helper"
+	eto "walls-user-service/internal/core/helper/event-helper/eto"
+	logger "walls-user-service/internal/core/helper/log-helper"
+)
+
+var (
+	defaultMaxAttempts  = 5
+	defaultRetryBackoff = 1 * time.Second
+	maxRetryBackoff     = 30 * time.Second
+)
+
+// withRetry - wraps an event handler so a panic is treated as a failure, a
+// failure is retried, and an event still failing after the configured attempts
+// is parked on the dead-letter stream. A parked event counts as handled so the
+// transport acknowledges it. Retries never hold up the next event: a stream
+// entry is left pending for the transport to deliver again, while a pub/sub
+// event, which is never redelivered, is retried in the background with
+// exponential backoff.
+func (s *EventSubscriber) withRetry(eventHandler helper.EventHandler) helper.EventHandler {
+	return func(ctx context.Context, event interface{}) error {
+		attempts := maxAttempts()
+
+		attempt := helper.DeliveryAttempt(ctx)
+		if attempt == 0 {
+			return s.retryInBackground(ctx, eventHandler, event, 1, attempts)
+		}
+
+		err := handleSafely(ctx, eventHandler, event)
+		if err == nil {
+			return nil
+		}
+		logger.LogEvent("ERROR", fmt.Sprintf("Attempt %d of %d handling event from %s failed: %v", attempt, attempts, helper.EventChannel(ctx), err))
+
+		if attempt < attempts {
+			return err
+		}
+		return s.deadLetter(ctx, event, err, attempt)
+	}
+}
+
+// retryInBackground - handles an event the transport will not redeliver,
+// scheduling the next attempt after the backoff instead of waiting for it.
+func (s *EventSubscriber) retryInBackground(ctx context.Context, eventHandler helper.EventHandler, event interface{}, attempt int, attempts int) error {
+	err := handleSafely(ctx, eventHandler, event)
+	if err == nil {
+		return nil
+	}
+	logger.LogEvent("ERROR", fmt.Sprintf("Attempt %d of %d handling event from %s failed: %v", attempt, attempts, helper.EventChannel(ctx), err))
+
+	if attempt >= attempts {
+		return s.deadLetter(ctx, event, err, attempt)
+	}
+	time.AfterFunc(retryBackoff(attempt), func() {
+		if ctx.Err() != nil {
+			return
+		}
+		s.retryInBackground(ctx, eventHandler, event, attempt+1, attempts)
+	})
+	return nil
+}
+
+// handleSafely - runs the handler, turning a panic into an error.
+func handleSafely(ctx context.Context, eventHandler helper.EventHandler, event interface{}) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			logger.LogEvent("ERROR", fmt.Sprintf("Recovered from panic handling event: %v\n%s", recovered, debug.Stack()))
+			err = fmt.Errorf("panic handling event: %v", recovered)
+		}
+	}()
+
+	return eventHandler(ctx, event)
+}
+
+func (s *EventSubscriber) deadLetter(ctx context.Context, event interface{}, handlerErr error, attempts int) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	envelope, _ := eto.DecodeEvent(payload)
+
+	deadLetterEvent := entity.DeadLetterEvent{
+		EventReference: envelope.EventReference,
+		Channel:        helper.EventChannel(ctx),
+		Payload:        string(payload),
+		Error:          handlerErr.Error(),
+		Attempts:       attempts,
+		FailedOn:       time.Now().UTC().Format(time.RFC3339),
+	}
+
+	_, err = s.deadLetterRepository.CreateDeadLetterEvent(ctx, deadLetterEvent)
+	if err != nil {
+		// Leave the event unacknowledged so the transport can deliver it again.
+		logger.LogEvent("ERROR", "Failed to dead-letter event "+envelope.EventReference+": "+err.Error())
+		return err
+	}
+
+	logger.LogEvent("ERROR", fmt.Sprintf("Event %s from %s dead-lettered after %d attempts: %v", envelope.EventReference, deadLetterEvent.Channel, attempts, handlerErr))
+	return nil
+}
+
+// retryBackoff - exponential backoff from the configured base, capped at maxRetryBackoff.
+func retryBackoff(attempt int) time.Duration {
+	base := defaultRetryBackoff
+	if seconds, err := strconv.Atoi(configuration.ServiceConfiguration.EBRetryBackoff); err == nil && seconds > 0 {
+		base = time.Duration(seconds) * time.Second
+	}
+
+	backoff := time.Duration(math.Pow(2, float64(attempt-1))) * base
+	if backoff <= 0 || backoff > maxRetryBackoff {
+		return maxRetryBackoff
+	}
+	return backoff
+}
+
+func maxAttempts() int {
+	attempts, err := strconv.Atoi(configuration.ServiceConfiguration.EBMaxAttempts)
+	if err != nil || attempts <= 0 {
+		return defaultMaxAttempts
+	}
+	return attempts
+}
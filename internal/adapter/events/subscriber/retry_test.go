@@ -0,0 +1,176 @@
+package subscriber
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	redisRepository "walls-user-service/internal/adapter/repository/redis"
+	"walls-user-service/internal/core/domain/entity"
+	configuration "walls-user-service/internal/core/helper/configuration-helper"
+	helper "walls-user-service/internal/core/helper/event-helper"
+	"walls-user-service/internal/core/helper/event-helper/redistest"
+)
+
+// newTestSubscriber - a subscriber on an in-memory Redis with the event bus
+// configured for the test, restoring the configuration afterwards.
+func newTestSubscriber(t *testing.T, transport string, attempts string) (*EventSubscriber, *redistest.Store) {
+	t.Helper()
+	saved := configuration.ServiceConfiguration
+	configuration.ServiceConfiguration.ServiceName = "walls-user-service"
+	configuration.ServiceConfiguration.EBTransport = transport
+	configuration.ServiceConfiguration.EBConsumerGroup = "walls-user-service"
+	configuration.ServiceConfiguration.EBConsumerName = "consumer-1"
+	configuration.ServiceConfiguration.EBClaimIdle = "1"
+	configuration.ServiceConfiguration.EBMaxAttempts = attempts
+	configuration.ServiceConfiguration.EBRetryBackoff = "1"
+	configuration.ServiceConfiguration.EBDeadLetterStream = ""
+	configuration.ServiceConfiguration.EBDedupRetention = ""
+	t.Cleanup(func() { configuration.ServiceConfiguration = saved })
+
+	client, store := redistest.NewClient()
+	return NewEventSubscriber(client, redisRepository.NewDeadLetter(client), redisRepository.NewProcessedEvent(client)), store
+}
+
+func deadLetters(t *testing.T, s *EventSubscriber) []entity.DeadLetterEvent {
+	t.Helper()
+	deadLetterEvents, err := s.deadLetterRepository.GetDeadLetterEvents(context.Background(), 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return deadLetterEvents.([]entity.DeadLetterEvent)
+}
+
+func TestStreamEventsAreDeadLetteredAfterTheLastAttempt(t *testing.T) {
+	s, store := newTestSubscriber(t, helper.StreamsTransport, "2")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stream := "BANKVERIFIEDEVENT"
+	redisHelper := helper.NewRedisClient(s.redisClient)
+	if err := redisHelper.PublishToChannel(ctx, stream, []byte(`{"EventReference":"event-1"}`)); err != nil {
+		t.Fatal(err)
+	}
+
+	attempts := make(chan int, 10)
+	eventHandler := func(ctx context.Context, event interface{}) error {
+		attempts <- helper.DeliveryAttempt(ctx)
+		return errors.New("bank not found")
+	}
+	go redisHelper.SubscribeToEvent(ctx, stream, s.withRetry(eventHandler))
+
+	for expected := 1; expected <= 2; expected++ {
+		select {
+		case attempt := <-attempts:
+			if attempt != expected {
+				t.Fatalf("expected attempt %d, got %d", expected, attempt)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for attempt %d", expected)
+		}
+		// The failed entry stays pending until it has been idle for the claim window.
+		if expected == 1 {
+			if len(deadLetters(t, s)) != 0 {
+				t.Fatal("expected an event with attempts left not to be dead-lettered")
+			}
+			store.Advance(time.Second)
+		}
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(store.Pending(stream, "walls-user-service")) != 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("expected the dead-lettered entry to be acknowledged")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	cancel()
+
+	parked := deadLetters(t, s)
+	if len(parked) != 1 {
+		t.Fatalf("expected one dead letter, got %+v", parked)
+	}
+	if deadLetter := parked[0]; deadLetter.EventReference != "event-1" || deadLetter.Channel != stream || deadLetter.Attempts != 2 || deadLetter.Error != "bank not found" {
+		t.Errorf("expected the dead letter to record the event and its last failure, got %+v", deadLetter)
+	}
+	if len(attempts) != 0 {
+		t.Errorf("expected no attempts after the event was dead-lettered, got %d", len(attempts))
+	}
+}
+
+func TestPubSubEventsAreRetriedInTheBackground(t *testing.T) {
+	s, _ := newTestSubscriber(t, helper.PubSubTransport, "2")
+
+	attempts := make(chan struct{}, 10)
+	eventHandler := func(ctx context.Context, event interface{}) error {
+		attempts <- struct{}{}
+		if len(attempts) == 1 {
+			return errors.New("user not found")
+		}
+		return nil
+	}
+
+	started := time.Now()
+	if err := s.withRetry(eventHandler)(context.Background(), map[string]interface{}{"EventReference": "event-1"}); err != nil {
+		t.Fatalf("expected a failed pub/sub event to be retried rather than reported, got %v", err)
+	}
+	if elapsed := time.Since(started); elapsed >= time.Second {
+		t.Errorf("expected the retry not to hold up the subscriber, took %v", elapsed)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(attempts) < 2 {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for the retry")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if len(deadLetters(t, s)) != 0 {
+		t.Error("expected an event handled on retry not to be dead-lettered")
+	}
+}
+
+func TestPanicsAreDeadLetteredAsFailures(t *testing.T) {
+	s, store := newTestSubscriber(t, helper.PubSubTransport, "1")
+	ctx := context.Background()
+	eventHandler := func(ctx context.Context, event interface{}) error {
+		panic("nil map")
+	}
+
+	if err := s.withRetry(eventHandler)(ctx, map[string]interface{}{"EventReference": "event-1"}); err != nil {
+		t.Fatalf("expected the dead-lettered event to count as handled, got %v", err)
+	}
+	parked := deadLetters(t, s)
+	if len(parked) != 1 || parked[0].Error != "panic handling event: nil map" || parked[0].Attempts != 1 {
+		t.Fatalf("expected the panic to be dead-lettered, got %+v", parked)
+	}
+
+	// An event that cannot be parked is reported so the transport delivers it again.
+	store.Fail("xadd", errors.New("connection refused"))
+	if err := s.withRetry(eventHandler)(ctx, map[string]interface{}{"EventReference": "event-2"}); err == nil {
+		t.Error("expected a failed dead-letter write to be reported")
+	}
+}
+
+func TestRetryBackoff(t *testing.T) {
+	saved := configuration.ServiceConfiguration.EBRetryBackoff
+	defer func() { configuration.ServiceConfiguration.EBRetryBackoff = saved }()
+
+	for _, test := range []struct {
+		base     string
+		attempt  int
+		expected time.Duration
+	}{
+		{"", 1, time.Second},
+		{"", 3, 4 * time.Second},
+		{"2", 1, 2 * time.Second},
+		{"2", 4, 16 * time.Second},
+		{"2", 5, maxRetryBackoff},
+		{"1", 100, maxRetryBackoff},
+	} {
+		configuration.ServiceConfiguration.EBRetryBackoff = test.base
+		if backoff := retryBackoff(test.attempt); backoff != test.expected {
+			t.Errorf("base %q, attempt %d: expected a backoff of %v, got %v", test.base, test.attempt, test.expected, backoff)
+		}
+	}
+}
@@ -5,21 +5,61 @@ import (
 
 	"walls-user-service/internal/adapter/handlers"
 	helper "walls-user-service/internal/core/helper/event-helper"
+	ports "walls-user-service/internal/port"
 
 	"github.com/redis/go-redis/v9"
 )
 
 type EventSubscriber struct {
-	redisClient *redis.Client
+	redisClient              *redis.Client
+	deadLetterRepository     ports.DeadLetterRepository
+	processedEventRepository ports.ProcessedEventRepository
 }
 
-func NewEventSubscriber(redisClient *redis.Client) *EventSubscriber {
+func NewEventSubscriber(redisClient *redis.Client, deadLetterRepository ports.DeadLetterRepository, processedEventRepository ports.ProcessedEventRepository) *EventSubscriber {
 	return &EventSubscriber{
-		redisClient: redisClient,
+		redisClient:              redisClient,
+		deadLetterRepository:     deadLetterRepository,
+		processedEventRepository: processedEventRepository,
 	}
 }
 
 func (s *EventSubscriber) SubscribeToOtpValidatedEvent(ctx context.Context, event interface{}) error {
 	redisHelper := helper.NewRedisClient(s.redisClient)
-	return redisHelper.SubscribeToEvent(ctx, event, handlers.OtpValidatedEventHandler)
+	return redisHelper.SubscribeToEvent(ctx, event, s.withRetry(s.withIdempotency(handlers.OtpValidatedEventHandler)))
+}
+
+func (s *EventSubscriber) SubscribeToIdentificationVerifiedEvent(ctx context.Context, event interface{}) error {
+	redisHelper := helper.NewRedisClient(s.redisClient)
+	return redisHelper.SubscribeToEvent(ctx, event, s.withRetry(s.withIdempotency(handlers.IdentificationVerifiedEventHandler)))
+}
+
+func (s *EventSubscriber) SubscribeToTierUpgradeApprovedEvent(ctx context.Context, event interface{}) error {
+	redisHelper := helper.NewRedisClient(s.redisClient)
+	return redisHelper.SubscribeToEvent(ctx, event, s.withRetry(s.withIdempotency(handlers.TierUpgradeApprovedEventHandler)))
+}
+
+func (s *EventSubscriber) SubscribeToBankVerifiedEvent(ctx context.Context, event interface{}) error {
+	redisHelper := helper.NewRedisClient(s.redisClient)
+	return redisHelper.SubscribeToEvent(ctx, event, s.withRetry(s.withIdempotency(handlers.BankVerifiedEventHandler)))
+}
+
+func (s *EventSubscriber) SubscribeToCardVerifiedEvent(ctx context.Context, event interface{}) error {
+	redisHelper := helper.NewRedisClient(s.redisClient)
+	return redisHelper.SubscribeToEvent(ctx, event, s.withRetry(s.withIdempotency(handlers.CardVerifiedEventHandler)))
+}
+
+func (s *EventSubscriber) SubscribeToPhotoVerifiedEvent(ctx context.Context, event interface{}) error {
+	redisHelper := helper.NewRedisClient(s.redisClient)
+	return redisHelper.SubscribeToEvent(ctx, event, s.withRetry(s.withIdempotency(handlers.PhotoVerifiedEventHandler)))
+}
+
+func (s *EventSubscriber) SubscribeToBalanceUpdateRequestEvent(ctx context.Context, event interface{}) error {
+	redisHelper := helper.NewRedisClient(s.redisClient)
+	return redisHelper.SubscribeToEvent(ctx, event, s.withRetry(s.withIdempotency(handlers.BalanceUpdateRequestEventHandler)))
+}
+
+func (s *EventSubscriber) SubscribeToTierConfigUpdatedEvent(ctx context.Context, event interface{}) error {
+	redisHelper := helper.NewRedisClient(s.redisClient)
+	return redisHelper.SubscribeToEvent(ctx, event, s.withRetry(s.withIdempotency(handlers.TierConfigUpdatedEventHandler)))
 }
@@ -1,6 +1,7 @@
 package extensions
 
 import (
+	memoryRepository "walls-user-service/internal/adapter/repository/memory"
 	mongoRepository "walls-user-service/internal/adapter/repository/mongodb"
 	logger "walls-user-service/internal/core/helper/log-helper"
 
@@ -11,8 +12,18 @@ import (
 
 func StartDatabase(dbType string) interface{} {
 
-	switch dbType {
-	case strings.ToLower(dbType):
+	switch strings.ToLower(dbType) {
+	case "memory":
+		logger.LogEvent("INFO", "Initializing in-memory store!")
+		memoryRepo, err := memoryRepository.ConnectToMemory()
+		if err != nil {
+			fmt.Println(err)
+			logger.LogEvent("ERROR", "In-memory store Initialization Error: "+err.Error())
+			log.Fatal()
+		}
+
+		return memoryRepo
+	default:
 		logger.LogEvent("INFO", "Initializing Mongo!")
 		mongoRepo, err := mongoRepository.ConnectToMongo()
 		if err != nil {
@@ -23,6 +34,5 @@ func StartDatabase(dbType string) interface{} {
 
 		return mongoRepo
 	}
-	return nil
 
 }
@@ -2,11 +2,29 @@ package extensions
 
 import (
 	"walls-user-service/internal/core/domain/dto"
+	"walls-user-service/internal/core/middleware"
 
 	"github.com/gin-gonic/gin"
 )
 
+// CurrentUserHeaders - the header each field of the current user is read from.
+var CurrentUserHeaders = map[string]string{
+	"user_reference":          "X-User-Reference",
+	"phone":                   "X-Phone",
+	"device.device_reference": "X-Device-Reference",
+	"device.imei":             "X-Imei",
+	"device.type":             "X-Device-Type",
+	"device.brand":            "X-Device-Brand",
+	"device.model":            "X-Device-Model",
+}
+
+// GetCurrentUser - the user authenticated from the request's token or, in header
+// mode, the user described by the request headers.
 func GetCurrentUser(c *gin.Context) dto.CurrentUserDto {
+	if currentUser, ok := c.Get(middleware.CurrentUserKey); ok {
+		return currentUser.(dto.CurrentUserDto)
+	}
+
 	currentUser := dto.CurrentUserDto{
 		UserReference: c.GetHeader("X-User-Reference"),
 		Phone:         c.GetHeader("X-Phone"),
@@ -0,0 +1,90 @@
+package extensions
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+	mongoRepository "walls-user-service/internal/adapter/repository/mongodb"
+	configuration "walls-user-service/internal/core/helper/configuration-helper"
+	logger "walls-user-service/internal/core/helper/log-helper"
+)
+
+// RunMigrations - the migrate subcommand, run instead of the service:
+//
+//	walls-user-service migrate [-dry-run] [-steps n] up|down|status
+//
+// up applies the pending migrations, down rolls back the last n applied ones
+// (1 by default) and status lists every migration with the date it was applied.
+func RunMigrations(args []string) error {
+	flags := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	dryRun := flags.Bool("dry-run", false, "report the migrations without running them")
+	steps := flags.Int("steps", 1, "number of migrations to roll back")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	command := flags.Arg(0)
+	if command == "" {
+		command = "up"
+	}
+
+	if strings.ToLower(configuration.ServiceConfiguration.DBConnectionType) == "memory" {
+		return errors.New("migrations only apply to a mongodb database")
+	}
+	db, err := mongoRepository.ConnectToMongoDatabase()
+	if err != nil {
+		return err
+	}
+	migrator := mongoRepository.NewMigrator(db)
+	ctx := context.Background()
+
+	switch command {
+	case "up":
+		applied, err := migrator.Up(ctx, *dryRun)
+		for _, migration := range applied {
+			fmt.Printf("%s %d: %s\n", verb(*dryRun, "would apply", "applied"), migration.Version, migration.Description)
+		}
+		return err
+	case "down":
+		if *steps < 1 {
+			return errors.New("steps must be at least 1")
+		}
+		rolledBack, err := migrator.Down(ctx, *steps, *dryRun)
+		for _, migration := range rolledBack {
+			fmt.Printf("%s %d: %s\n", verb(*dryRun, "would roll back", "rolled back"), migration.Version, migration.Description)
+		}
+		return err
+	case "status":
+		applied, err := migrator.Applied(ctx)
+		if err != nil {
+			return err
+		}
+		appliedOn := map[int]string{}
+		for _, migration := range applied {
+			appliedOn[migration.Version] = migration.AppliedOn
+		}
+		for _, migration := range migrator.Migrations {
+			status, ok := appliedOn[migration.Version]
+			if !ok {
+				status = "pending"
+			}
+			if migration.Down == nil {
+				status += ", irreversible"
+			}
+			fmt.Printf("%d: %s [%s]\n", migration.Version, migration.Description, status)
+		}
+		return nil
+	default:
+		logger.LogEvent("ERROR", "Unknown migrate command: "+command)
+		return fmt.Errorf("unknown migrate command %q, expected up, down or status", command)
+	}
+}
+
+func verb(dryRun bool, planned string, done string) string {
+	if dryRun {
+		return planned
+	}
+	return done
+}
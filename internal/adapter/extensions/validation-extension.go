@@ -1,30 +1,35 @@
 package extensions
 
 import (
-	"fmt"
+	errorhelper "walls-user-service/internal/core/helper/error-helper"
 	validation "walls-user-service/internal/core/helper/validation-helper"
 
 	"github.com/gin-gonic/gin"
 )
 
+// ValidateRequest - validates the request body and the current user read from
+// the headers. When either is invalid the request is answered with a 400 listing
+// every failing field, body and headers reported apart, and false is returned.
+func ValidateRequest(c *gin.Context, body interface{}, currentUser interface{}) bool {
+	err := errorhelper.JoinValidationErrors(
+		validation.Validate(body),
+		validation.ValidateHeaders(currentUser, CurrentUserHeaders),
+	)
+	return abortOnValidationError(c, err)
+}
+
 func ValidateBody(c *gin.Context, body interface{}) bool {
-	err := validation.Validate(body)
-	fmt.Println("body error:", err)
-	// if err != nil {
-	// 	c.AbortWithStatusJSON(400, err)
-	// 	return false
-	// }
-	// return true
-	return err == nil
+	return abortOnValidationError(c, validation.Validate(body))
 }
 
 func ValidateHeaders(c *gin.Context, currentUser interface{}) bool {
-	err := validation.Validate(currentUser)
-	fmt.Println("header error:", err)
-	// if err != nil {
-	// 	c.AbortWithStatusJSON(400, err)
-	// 	return false
-	// }
-	// return true
-	return err == nil
+	return abortOnValidationError(c, validation.ValidateHeaders(currentUser, CurrentUserHeaders))
+}
+
+func abortOnValidationError(c *gin.Context, err error) bool {
+	if err == nil {
+		return true
+	}
+	c.AbortWithStatusJSON(400, errorhelper.ErrorFrom(err))
+	return false
 }
@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	extraction "walls-user-service/internal/adapter/handlers/extraction"
+	"walls-user-service/internal/core/domain/dto"
+	events "walls-user-service/internal/core/domain/event/data"
+	logger "walls-user-service/internal/core/helper/log-helper"
+	"walls-user-service/internal/core/services"
+)
+
+// BalanceUpdateRequestEventHandler - applies a balance pushed by the ledger.
+// Out-of-order updates are dropped; retrying them could never succeed.
+func BalanceUpdateRequestEventHandler(ctx context.Context, event interface{}) error {
+	_, data, err := extraction.ExtractEventData(event, events.BalanceUpdateRequestEventData{})
+	if err != nil {
+		logger.LogEvent("ERROR", "Error extracting balance update request event: "+err.Error())
+		return err
+	}
+
+	var iEventData events.BalanceUpdateRequestEventData
+	err = extraction.DecodeEventData(data, &iEventData)
+	if err != nil {
+		return err
+	}
+
+	if iEventData.UserReference == "" || iEventData.LedgerSequence <= 0 {
+		logger.LogEvent("ERROR", "Balance update request event is missing the user reference or ledger sequence")
+		return errors.New("balance update request event is missing the user reference or ledger sequence")
+	}
+
+	ledgerBalanceDto := dto.LedgerBalanceDto{
+		WalletReference:       iEventData.WalletReference,
+		AvailableAmount:       iEventData.AvailableAmount,
+		PendingIncomingAmount: iEventData.PendingIncomingAmount,
+		Currency:              iEventData.Currency,
+		LedgerSequence:        iEventData.LedgerSequence,
+	}
+
+	_, err = services.UserService.ApplyLedgerBalance(ctx, iEventData.UserReference, ledgerBalanceDto)
+	if errors.Is(err, services.ErrStaleBalanceUpdate) {
+		return nil
+	}
+	return err
+}
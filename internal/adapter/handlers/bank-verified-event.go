@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	extraction "walls-user-service/internal/adapter/handlers/extraction"
+	"walls-user-service/internal/core/domain/dto"
+	events "walls-user-service/internal/core/domain/event/data"
+	logger "walls-user-service/internal/core/helper/log-helper"
+	"walls-user-service/internal/core/services"
+)
+
+// BankVerifiedEventHandler - applies the payment integration service's
+// verification result to the bank account it names.
+func BankVerifiedEventHandler(ctx context.Context, event interface{}) error {
+	_, data, err := extraction.ExtractEventData(event, events.BankVerifiedEventData{})
+	if err != nil {
+		logger.LogEvent("ERROR", "Error extracting bank verified event: "+err.Error())
+		return err
+	}
+
+	var iEventData events.BankVerifiedEventData
+	err = extraction.DecodeEventData(data, &iEventData)
+	if err != nil {
+		return err
+	}
+
+	if iEventData.UserReference == "" || iEventData.BankReference == "" {
+		logger.LogEvent("ERROR", "Bank verified event is missing the user or bank reference")
+		return errors.New("bank verified event is missing the user or bank reference")
+	}
+
+	verificationDto := dto.PaymentMethodVerificationDto{
+		IntegrationType:      iEventData.IntegrationType,
+		IntegrationReference: iEventData.IntegrationReference,
+		Status:               iEventData.Status,
+		StatusReason:         iEventData.StatusReason,
+		VerifiedOn:           iEventData.VerifiedOn,
+	}
+
+	_, err = services.UserService.VerifyBank(ctx, iEventData.UserReference, iEventData.BankReference, verificationDto)
+	return err
+}
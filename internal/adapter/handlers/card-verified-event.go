@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	extraction "walls-user-service/internal/adapter/handlers/extraction"
+	"walls-user-service/internal/core/domain/dto"
+	events "walls-user-service/internal/core/domain/event/data"
+	logger "walls-user-service/internal/core/helper/log-helper"
+	"walls-user-service/internal/core/services"
+)
+
+// CardVerifiedEventHandler - applies the payment integration service's
+// verification result to the card it names.
+func CardVerifiedEventHandler(ctx context.Context, event interface{}) error {
+	_, data, err := extraction.ExtractEventData(event, events.CardVerifiedEventData{})
+	if err != nil {
+		logger.LogEvent("ERROR", "Error extracting card verified event: "+err.Error())
+		return err
+	}
+
+	var iEventData events.CardVerifiedEventData
+	err = extraction.DecodeEventData(data, &iEventData)
+	if err != nil {
+		return err
+	}
+
+	if iEventData.UserReference == "" || iEventData.CardReference == "" {
+		logger.LogEvent("ERROR", "Card verified event is missing the user or card reference")
+		return errors.New("card verified event is missing the user or card reference")
+	}
+
+	verificationDto := dto.PaymentMethodVerificationDto{
+		IntegrationType:      iEventData.IntegrationType,
+		IntegrationReference: iEventData.IntegrationReference,
+		Status:               iEventData.Status,
+		StatusReason:         iEventData.StatusReason,
+		VerifiedOn:           iEventData.VerifiedOn,
+	}
+
+	_, err = services.UserService.VerifyCard(ctx, iEventData.UserReference, iEventData.CardReference, verificationDto)
+	return err
+}
@@ -11,9 +11,14 @@ import (
 
 // Event handler function
 // extractEventData takes in an event and extracts the otpValidatedEventData from it.
+// The event may use the legacy envelope or the CloudEvents encoding.
 func ExtractEventData(event interface{}, data interface{}) (interface{}, interface{}, error) {
-	var iEvent eto.Event
-	err := convertEvent(event, &iEvent)
+	jsonBytes, err := json.Marshal(event)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error converting event to validated event: %v", err)
+	}
+
+	iEvent, err := eto.DecodeEvent(jsonBytes)
 	if err != nil {
 		return nil, nil, fmt.Errorf("error converting event to validated event: %v", err)
 	}
@@ -32,6 +37,16 @@ func ExtractEventData(event interface{}, data interface{}) (interface{}, interfa
 	return iEvent, iEventData, nil
 }
 
+// DecodeEventData - decodes extracted event data into the handler's typed
+// payload, so a malformed payload surfaces as an error rather than a panic.
+func DecodeEventData(data interface{}, outputData interface{}) error {
+	err := convertEvent(data, outputData)
+	if err != nil {
+		return fmt.Errorf("error decoding event data: %v", err)
+	}
+	return nil
+}
+
 func convertEvent(event interface{}, outputEvent interface{}) error {
 	// Convert interface{} to byte array
 	jsonBytes, err := json.Marshal(event)
@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	memoryRepository "walls-user-service/internal/adapter/repository/memory"
+	"walls-user-service/internal/core/domain/entity"
+	events "walls-user-service/internal/core/domain/event/data"
+	"walls-user-service/internal/core/domain/shared"
+	eto "walls-user-service/internal/core/helper/event-helper/eto"
+	"walls-user-service/internal/core/services"
+)
+
+// newTestServices - points the handlers' services at memory repositories
+// holding the users.
+func newTestServices(t *testing.T, users ...entity.User) *memoryRepository.UserInfra {
+	t.Helper()
+	outbox := memoryRepository.NewOutbox()
+	userRepository := memoryRepository.NewUser(outbox)
+	tierRepository := memoryRepository.NewTier()
+	for _, user := range users {
+		if _, err := userRepository.CreateUser(context.Background(), user); err != nil {
+			t.Fatal(err)
+		}
+	}
+	services.NewUserService(userRepository, outbox, tierRepository)
+	services.NewTierService(tierRepository)
+	return userRepository
+}
+
+func storedUser(t *testing.T, userRepository *memoryRepository.UserInfra, user_reference string) entity.User {
+	t.Helper()
+	userData, err := userRepository.GetUserByReference(context.Background(), user_reference)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return userData.(entity.User)
+}
+
+// newEvent - an event in the legacy envelope, as the subscriber hands it over.
+func newEvent(eventType string, eventData interface{}) eto.Event {
+	return eto.Event{
+		EventReference: eventType + "-1",
+		EventName:      eventType,
+		EventType:      eventType,
+		EventData:      eventData,
+	}
+}
+
+func TestPaymentMethodVerifiedEventsUpdateTheirStatus(t *testing.T) {
+	user := entity.User{
+		UserReference: "user-1",
+		UserProfile:   entity.UserProfile{Phone: "+2348000000001"},
+		BankAccounts:  []entity.Bank{{BankReference: "bank-1", Status: shared.VerificationPending}},
+		Cards:         []entity.Card{{CardReference: "card-1", Status: shared.VerificationVerified, IsDefault: true}},
+	}
+	userRepository := newTestServices(t, user)
+	ctx := context.Background()
+
+	bankVerified := events.BankVerifiedEventData{UserReference: "user-1", BankReference: "bank-1", IntegrationType: "paystack", Status: shared.VerificationVerified}
+	if err := BankVerifiedEventHandler(ctx, newEvent("bankverifiedevent", bankVerified)); err != nil {
+		t.Fatal(err)
+	}
+	cardFailed := events.CardVerifiedEventData{UserReference: "user-1", CardReference: "card-1", Status: shared.VerificationFailed, StatusReason: "expired"}
+	if err := CardVerifiedEventHandler(ctx, newEvent("cardverifiedevent", cardFailed)); err != nil {
+		t.Fatal(err)
+	}
+
+	stored := storedUser(t, userRepository, "user-1")
+	if bank := stored.BankAccounts[0]; bank.Status != shared.VerificationVerified || bank.IntegrationType != "paystack" || bank.VerifiedOn == "" {
+		t.Errorf("expected the bank account to be verified, got %+v", bank)
+	}
+	if card := stored.Cards[0]; card.Status != shared.VerificationFailed || card.StatusReason != "expired" || card.IsDefault {
+		t.Errorf("expected the failed card to be no longer the default, got %+v", card)
+	}
+
+	unknownBank := events.BankVerifiedEventData{UserReference: "user-1", BankReference: "bank-9", Status: shared.VerificationVerified}
+	if err := BankVerifiedEventHandler(ctx, newEvent("bankverifiedevent", unknownBank)); !errors.Is(err, services.ErrBankNotFound) {
+		t.Errorf("expected ErrBankNotFound, got %v", err)
+	}
+	missingCard := events.CardVerifiedEventData{UserReference: "user-1", Status: shared.VerificationVerified}
+	if err := CardVerifiedEventHandler(ctx, newEvent("cardverifiedevent", missingCard)); err == nil {
+		t.Error("expected an event without a card reference to fail")
+	}
+	if err := CardVerifiedEventHandler(ctx, newEvent("cardupdatedevent", cardFailed)); err == nil {
+		t.Error("expected an event on a channel the service does not accept to fail")
+	}
+}
+
+func TestBalanceUpdateRequestEventsDropOutOfOrderBalances(t *testing.T) {
+	user := entity.User{UserReference: "user-1", UserProfile: entity.UserProfile{Phone: "+2348000000001"}}
+	userRepository := newTestServices(t, user)
+	ctx := context.Background()
+
+	for _, balance := range []events.BalanceUpdateRequestEventData{
+		{UserReference: "user-1", AvailableAmount: 200, Currency: "NGN", LedgerSequence: 2},
+		{UserReference: "user-1", AvailableAmount: 100, Currency: "NGN", LedgerSequence: 1},
+		{UserReference: "user-1", AvailableAmount: 150, Currency: "NGN", LedgerSequence: 2},
+	} {
+		// A stale balance is acknowledged, so it is neither retried nor dead-lettered.
+		if err := BalanceUpdateRequestEventHandler(ctx, newEvent("balanceupdaterequestevent", balance)); err != nil {
+			t.Errorf("sequence %d: %v", balance.LedgerSequence, err)
+		}
+	}
+	if balance := storedUser(t, userRepository, "user-1").Wallet.Balance; balance.AvailableAmount != 200 || balance.LedgerSequence != 2 {
+		t.Errorf("expected the first balance of the latest sequence to be kept, got %+v", balance)
+	}
+
+	unsequenced := events.BalanceUpdateRequestEventData{UserReference: "user-1", AvailableAmount: 300}
+	if err := BalanceUpdateRequestEventHandler(ctx, newEvent("balanceupdaterequestevent", unsequenced)); err == nil {
+		t.Error("expected a balance without a ledger sequence to fail")
+	}
+}
+
+func TestPhotoVerifiedEventsMakeTheVerifiedPhotoTheDefault(t *testing.T) {
+	user := entity.User{
+		UserReference: "user-1",
+		UserProfile: entity.UserProfile{
+			Phone: "+2348000000001",
+			Photos: []entity.Photo{
+				{PhotoReference: "photo-1", Status: shared.PhotoVerified, IsVerified: true, IsDefault: true},
+				{PhotoReference: "photo-2", Status: shared.PhotoPending},
+			},
+		},
+	}
+	userRepository := newTestServices(t, user)
+	ctx := context.Background()
+
+	photoVerified := events.PhotoVerifiedEventData{UserReference: "user-1", PhotoReference: "photo-2", Status: shared.PhotoVerified, VerifiedOn: "2024-01-01T00:00:00Z"}
+	if err := PhotoVerifiedEventHandler(ctx, newEvent("photoverifiedevent", photoVerified)); err != nil {
+		t.Fatal(err)
+	}
+	photos := storedUser(t, userRepository, "user-1").UserProfile.Photos
+	if photos[0].IsDefault || !photos[1].IsDefault || photos[1].VerifiedOn != "2024-01-01T00:00:00Z" {
+		t.Errorf("expected the verified photo to become the default, got %+v", photos)
+	}
+
+	unknownPhoto := events.PhotoVerifiedEventData{UserReference: "user-1", PhotoReference: "photo-9", Status: shared.PhotoVerified}
+	if err := PhotoVerifiedEventHandler(ctx, newEvent("photoverifiedevent", unknownPhoto)); !errors.Is(err, services.ErrPhotoNotFound) {
+		t.Errorf("expected ErrPhotoNotFound, got %v", err)
+	}
+}
+
+func TestTierConfigUpdatedEventsKeepTheLatestTier(t *testing.T) {
+	newTestServices(t)
+	ctx := context.Background()
+
+	for _, tierConfig := range []events.TierConfigUpdatedEventData{
+		{Tier: entity.Tier{TierReference: "tier-1", TierName: "basic", SendingLimit: 500}, IsActive: true, UpdatedOn: "2024-01-02T00:00:00Z"},
+		{Tier: entity.Tier{TierReference: "tier-1", TierName: "basic", SendingLimit: 100}, IsActive: true, UpdatedOn: "2024-01-01T00:00:00Z"},
+	} {
+		// An older tier is acknowledged rather than retried.
+		if err := TierConfigUpdatedEventHandler(ctx, newEvent("tierconfigupdatedevent", tierConfig)); err != nil {
+			t.Errorf("tier updated on %s: %v", tierConfig.UpdatedOn, err)
+		}
+	}
+	tierData, err := services.TierService.GetTierByReference(ctx, "tier-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tier := tierData.(entity.TierConfig); tier.SendingLimit != 500 || !tier.IsActive {
+		t.Errorf("expected the later tier to be kept, got %+v", tier)
+	}
+
+	unreferenced := events.TierConfigUpdatedEventData{Tier: entity.Tier{TierName: "premium"}}
+	if err := TierConfigUpdatedEventHandler(ctx, newEvent("tierconfigupdatedevent", unreferenced)); !errors.Is(err, services.ErrMissingTierReference) {
+		t.Errorf("expected ErrMissingTierReference, got %v", err)
+	}
+}
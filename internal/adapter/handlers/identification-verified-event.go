@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	extraction "walls-user-service/internal/adapter/handlers/extraction"
+	"walls-user-service/internal/core/domain/dto"
+	events "walls-user-service/internal/core/domain/event/data"
+	logger "walls-user-service/internal/core/helper/log-helper"
+	"walls-user-service/internal/core/services"
+)
+
+// IdentificationVerifiedEventHandler - applies the identity service's
+// verification result to the documentation it names.
+func IdentificationVerifiedEventHandler(ctx context.Context, event interface{}) error {
+	_, data, err := extraction.ExtractEventData(event, events.IdentificationVerifiedEventData{})
+	if err != nil {
+		logger.LogEvent("ERROR", "Error extracting identification verified event: "+err.Error())
+		return err
+	}
+
+	var iEventData events.IdentificationVerifiedEventData
+	err = extraction.DecodeEventData(data, &iEventData)
+	if err != nil {
+		return err
+	}
+
+	if iEventData.UserReference == "" || iEventData.DocumentationReference == "" {
+		logger.LogEvent("ERROR", "Identification verified event is missing the user or documentation reference")
+		return errors.New("identification verified event is missing the user or documentation reference")
+	}
+
+	documentationVerificationDto := dto.DocumentationVerificationDto{
+		IsVerified:         iEventData.IsVerified,
+		VerifiedOn:         iEventData.VerifiedOn,
+		VerificationMethod: iEventData.VerificationMethod,
+	}
+
+	_, err = services.UserService.VerifyDocumentation(ctx, iEventData.UserReference, iEventData.DocumentationReference, documentationVerificationDto)
+	return err
+}
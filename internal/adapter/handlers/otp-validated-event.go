@@ -14,32 +14,22 @@ import (
 	"walls-user-service/internal/core/services"
 )
 
-func OtpValidatedEventHandler(ctx context.Context, event interface{}) {
+func OtpValidatedEventHandler(ctx context.Context, event interface{}) error {
 	event, data, err := extraction.ExtractEventData(event, events.OtpValidatedEventData{})
 	if err != nil {
-		fmt.Println("extracting event:", err)
-		return
+		logger.LogEvent("ERROR", "Error extracting otp validated event: "+err.Error())
+		return err
 	}
 
-	// iEvent := event.(eto.Event)
-	// iEventData := data.(events.OtpValidatedEventData)
-	// currentUserDto := dto.CurrentUserDto{
-	// 	UserReference: iEventData.UserReference,
-	// 	Phone:         iEventData.Contact,
-	// 	Device: dto.DeviceDto{
-	// 		DeviceReference: iEventData.Device.DeviceReference,
-	// 		Imei:            iEventData.Device.Imei,
-	// 		Brand:           iEventData.Device.Brand,
-	// 		Model:           iEventData.Device.Model,
-	// 		Type:            iEventData.Device.Type,
-	// 	},
-	// }
+	var iEventData events.OtpValidatedEventData
+	err = extraction.DecodeEventData(data, &iEventData)
+	if err != nil {
+		return err
+	}
 
 	eventType := event.(eto.Event).EventType
-	iEventData := data.(map[string]interface{})
-	device := iEventData["device"].(map[string]interface{})
-	userReference := iEventData["user_reference"].(string)
-	contact := iEventData["contact"].(string)
+	userReference := iEventData.UserReference
+	contact := iEventData.Contact
 
 	switch eventType {
 	case "create_user":
@@ -48,11 +38,11 @@ func OtpValidatedEventHandler(ctx context.Context, event interface{}) {
 			UserReference: userReference,
 			Phone:         contact,
 			Device: dto.DeviceDto{
-				DeviceReference: device["device_reference"].(string),
-				Imei:            device["imei"].(string),
-				Brand:           device["brand"].(string),
-				Model:           device["model"].(string),
-				Type:            device["type"].(string),
+				DeviceReference: iEventData.Device.DeviceReference,
+				Imei:            iEventData.Device.Imei,
+				Brand:           iEventData.Device.Brand,
+				Model:           iEventData.Device.Model,
+				Type:            iEventData.Device.Type,
 			},
 		}
 
@@ -63,16 +53,19 @@ func OtpValidatedEventHandler(ctx context.Context, event interface{}) {
 				Phone: contact,
 			}
 			// Create an instance of the UserService
-			services.UserService.CreateUser(ctx, createUserDto, currentUserDto)
+			_, err = services.UserService.CreateUser(ctx, createUserDto, currentUserDto)
 		}
 
 	case "verify_email":
 		user, _ := services.UserService.GetUserByReference(ctx, userReference)
 
 		if user != nil {
-			services.UserService.UpdateUserProfileEmailStatus(ctx, userReference)
+			_, err = services.UserService.UpdateUserProfileEmailStatus(ctx, userReference)
 		}
 
+	case "bind_device":
+		_, err = services.UserService.CompleteDeviceBinding(ctx, userReference, iEventData.Device)
+
 	// case "verify_phone":
 	// 	user, _ := services.UserService.GetUserByReference(ctx, userReference)
 
@@ -83,6 +76,8 @@ func OtpValidatedEventHandler(ctx context.Context, event interface{}) {
 		logger.LogEvent("ERROR:", fmt.Sprintf("invalid otp even type: %v", eventType))
 	}
 
+	return err
+
 	// userReference := iEventData["user_reference"].(string)
 	// contact := iEventData["contact"].(string)
 
@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	extraction "walls-user-service/internal/adapter/handlers/extraction"
+	"walls-user-service/internal/core/domain/dto"
+	events "walls-user-service/internal/core/domain/event/data"
+	logger "walls-user-service/internal/core/helper/log-helper"
+	"walls-user-service/internal/core/services"
+)
+
+// PhotoVerifiedEventHandler - applies the moderation result to the photo it names.
+func PhotoVerifiedEventHandler(ctx context.Context, event interface{}) error {
+	_, data, err := extraction.ExtractEventData(event, events.PhotoVerifiedEventData{})
+	if err != nil {
+		logger.LogEvent("ERROR", "Error extracting photo verified event: "+err.Error())
+		return err
+	}
+
+	var iEventData events.PhotoVerifiedEventData
+	err = extraction.DecodeEventData(data, &iEventData)
+	if err != nil {
+		return err
+	}
+
+	if iEventData.UserReference == "" || iEventData.PhotoReference == "" {
+		logger.LogEvent("ERROR", "Photo verified event is missing the user or photo reference")
+		return errors.New("photo verified event is missing the user or photo reference")
+	}
+
+	photoVerificationDto := dto.PhotoVerificationDto{
+		Status:          iEventData.Status,
+		RejectionReason: iEventData.RejectionReason,
+		VerifiedOn:      iEventData.VerifiedOn,
+	}
+
+	_, err = services.UserService.VerifyPhoto(ctx, iEventData.UserReference, iEventData.PhotoReference, photoVerificationDto)
+	return err
+}
@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"context"
+	extraction "walls-user-service/internal/adapter/handlers/extraction"
+	"walls-user-service/internal/core/domain/dto"
+	events "walls-user-service/internal/core/domain/event/data"
+	logger "walls-user-service/internal/core/helper/log-helper"
+	"walls-user-service/internal/core/services"
+)
+
+// TierConfigUpdatedEventHandler - keeps the tier catalogue current.
+func TierConfigUpdatedEventHandler(ctx context.Context, event interface{}) error {
+	_, data, err := extraction.ExtractEventData(event, events.TierConfigUpdatedEventData{})
+	if err != nil {
+		logger.LogEvent("ERROR", "Error extracting tier config updated event: "+err.Error())
+		return err
+	}
+
+	var iEventData events.TierConfigUpdatedEventData
+	err = extraction.DecodeEventData(data, &iEventData)
+	if err != nil {
+		return err
+	}
+
+	tierConfigDto := dto.TierConfigDto{
+		Tier:      iEventData.Tier,
+		IsActive:  iEventData.IsActive,
+		UpdatedOn: iEventData.UpdatedOn,
+	}
+
+	_, err = services.TierService.UpdateTierConfig(ctx, tierConfigDto)
+	return err
+}
@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	extraction "walls-user-service/internal/adapter/handlers/extraction"
+	"walls-user-service/internal/core/domain/dto"
+	events "walls-user-service/internal/core/domain/event/data"
+	logger "walls-user-service/internal/core/helper/log-helper"
+	"walls-user-service/internal/core/services"
+)
+
+// TierUpgradeApprovedEventHandler - records the decision on a tier upgrade
+// request, applying the tier when it was approved.
+func TierUpgradeApprovedEventHandler(ctx context.Context, event interface{}) error {
+	_, data, err := extraction.ExtractEventData(event, events.TierUpgradeApprovedEventData{})
+	if err != nil {
+		logger.LogEvent("ERROR", "Error extracting tier upgrade approved event: "+err.Error())
+		return err
+	}
+
+	var iEventData events.TierUpgradeApprovedEventData
+	err = extraction.DecodeEventData(data, &iEventData)
+	if err != nil {
+		return err
+	}
+
+	if iEventData.UserReference == "" || iEventData.RequestReference == "" {
+		logger.LogEvent("ERROR", "Tier upgrade approved event is missing the user or request reference")
+		return errors.New("tier upgrade approved event is missing the user or request reference")
+	}
+
+	tierUpgradeDecisionDto := dto.TierUpgradeDecisionDto{
+		RequestStatus: iEventData.RequestStatus,
+		ApprovedTier:  iEventData.ApprovedTier,
+		Reason:        iEventData.Reason,
+		DecidedOn:     iEventData.DecidedOn,
+	}
+
+	_, err = services.UserService.ApplyTierUpgradeDecision(ctx, iEventData.UserReference, iEventData.RequestReference, tierUpgradeDecisionDto)
+	return err
+}
@@ -0,0 +1,38 @@
+package repository
+
+import (
+	logger "walls-user-service/internal/core/helper/log-helper"
+	ports "walls-user-service/internal/port"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+type MemoryRepositories struct {
+	User   ports.UserRepository
+	Outbox ports.OutboxRepository
+	Tier   ports.TierRepository
+}
+
+func ConnectToMemory() (MemoryRepositories, error) {
+	logger.LogEvent("INFO", "Establishing in-memory store...")
+
+	outbox := NewOutbox()
+
+	repo := MemoryRepositories{
+		User:   NewUser(outbox),
+		Outbox: outbox,
+		Tier:   NewTier(),
+	}
+
+	return repo, nil
+}
+
+// clone - round-trips a document through bson so the store never shares
+// slices or maps with its callers, the same way a Mongo round trip would not.
+func clone(in interface{}, out interface{}) error {
+	data, err := bson.Marshal(in)
+	if err != nil {
+		return err
+	}
+	return bson.Unmarshal(data, out)
+}
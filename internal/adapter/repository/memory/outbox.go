@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+	"walls-user-service/internal/core/domain/entity"
+	"walls-user-service/internal/core/domain/shared"
+	logger "walls-user-service/internal/core/helper/log-helper"
+	ports "walls-user-service/internal/port"
+)
+
+type OutboxInfra struct {
+	mutex        sync.RWMutex
+	outboxEvents []entity.OutboxEvent
+}
+
+func NewOutbox() *OutboxInfra {
+	return &OutboxInfra{}
+}
+
+// OutboxRepo implements the repository.OutboxRepository interface
+var _ ports.OutboxRepository = &OutboxInfra{}
+
+func (r *OutboxInfra) CreateOutboxEvent(ctx context.Context, outboxEvent entity.OutboxEvent) (interface{}, error) {
+	logger.LogEvent("INFO", "Persisting outbox event with reference: "+outboxEvent.OutboxReference)
+
+	r.append(outboxEvent)
+
+	return outboxEvent.OutboxReference, nil
+}
+
+func (r *OutboxInfra) GetPendingOutboxEvents(ctx context.Context, limit int64) (interface{}, error) {
+	now := time.Now().UTC().Format(time.RFC3339)
+
+	r.mutex.RLock()
+	outboxEvents := []entity.OutboxEvent{}
+	for _, outboxEvent := range r.outboxEvents {
+		if outboxEvent.Status == shared.OutboxPending && outboxEvent.NextAttemptOn <= now {
+			outboxEvents = append(outboxEvents, outboxEvent)
+		}
+	}
+	r.mutex.RUnlock()
+
+	sort.SliceStable(outboxEvents, func(i, j int) bool {
+		return outboxEvents[i].CreatedOn < outboxEvents[j].CreatedOn
+	})
+	if limit > 0 && int64(len(outboxEvents)) > limit {
+		outboxEvents = outboxEvents[:limit]
+	}
+
+	return outboxEvents, nil
+}
+
+func (r *OutboxInfra) MarkOutboxEventDispatched(ctx context.Context, outboxReference string) (interface{}, error) {
+	r.update(outboxReference, func(outboxEvent *entity.OutboxEvent) {
+		outboxEvent.Status = shared.OutboxDispatched
+		outboxEvent.DispatchedOn = time.Now().UTC().Format(time.RFC3339)
+		outboxEvent.Attempts++
+	})
+
+	return outboxReference, nil
+}
+
+func (r *OutboxInfra) MarkOutboxEventFailed(ctx context.Context, outboxReference string, lastError string, nextAttemptOn string) (interface{}, error) {
+	r.update(outboxReference, func(outboxEvent *entity.OutboxEvent) {
+		outboxEvent.LastError = lastError
+		outboxEvent.NextAttemptOn = nextAttemptOn
+		outboxEvent.Attempts++
+	})
+
+	return outboxReference, nil
+}
+
+func (r *OutboxInfra) append(outboxEvents ...entity.OutboxEvent) {
+	r.mutex.Lock()
+	r.outboxEvents = append(r.outboxEvents, outboxEvents...)
+	r.mutex.Unlock()
+}
+
+func (r *OutboxInfra) update(outboxReference string, apply func(*entity.OutboxEvent)) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for i := range r.outboxEvents {
+		if r.outboxEvents[i].OutboxReference == outboxReference {
+			apply(&r.outboxEvents[i])
+			return
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"walls-user-service/internal/core/domain/entity"
+	logger "walls-user-service/internal/core/helper/log-helper"
+	ports "walls-user-service/internal/port"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type TierInfra struct {
+	mutex sync.RWMutex
+	tiers map[string]entity.TierConfig
+}
+
+func NewTier() *TierInfra {
+	return &TierInfra{tiers: map[string]entity.TierConfig{}}
+}
+
+// TierRepo implements the repository.TierRepository interface
+var _ ports.TierRepository = &TierInfra{}
+
+func (r *TierInfra) UpsertTier(ctx context.Context, tier entity.TierConfig) (interface{}, error) {
+	logger.LogEvent("INFO", "Persisting tier with reference: "+tier.TierReference)
+
+	stored := entity.TierConfig{}
+	err := clone(tier, &stored)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	current, ok := r.tiers[tier.TierReference]
+	if ok && current.UpdatedOn > tier.UpdatedOn {
+		logger.LogEvent("INFO", "Tier with reference: "+tier.TierReference+" was updated later")
+		return nil, ports.ErrStaleTierUpdate
+	}
+	r.tiers[tier.TierReference] = stored
+
+	return tier.TierReference, nil
+}
+
+func (r *TierInfra) GetTiers(ctx context.Context) (interface{}, error) {
+	r.mutex.RLock()
+	tiers := make([]entity.TierConfig, 0, len(r.tiers))
+	for _, stored := range r.tiers {
+		tier := entity.TierConfig{}
+		err := clone(stored, &tier)
+		if err != nil {
+			r.mutex.RUnlock()
+			return nil, err
+		}
+		tiers = append(tiers, tier)
+	}
+	r.mutex.RUnlock()
+
+	sort.SliceStable(tiers, func(i, j int) bool {
+		return tiers[i].TierName < tiers[j].TierName
+	})
+
+	logger.LogEvent("INFO", "Retrieving tiers completed successfully. ")
+	return tiers, nil
+}
+
+func (r *TierInfra) GetTierByReference(ctx context.Context, tierReference string) (interface{}, error) {
+	r.mutex.RLock()
+	stored, ok := r.tiers[tierReference]
+	r.mutex.RUnlock()
+	if !ok {
+		return nil, mongo.ErrNoDocuments
+	}
+
+	tier := entity.TierConfig{}
+	err := clone(stored, &tier)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.LogEvent("INFO", "Retrieving tier with reference: "+tierReference+" completed successfully. ")
+	return tier, nil
+}
@@ -0,0 +1,528 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"walls-user-service/internal/core/domain/entity"
+	"walls-user-service/internal/core/domain/shared"
+	logger "walls-user-service/internal/core/helper/log-helper"
+	ports "walls-user-service/internal/port"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type UserInfra struct {
+	mutex  sync.RWMutex
+	users  []entity.User
+	outbox *OutboxInfra
+}
+
+func NewUser(outbox *OutboxInfra) *UserInfra {
+	return &UserInfra{outbox: outbox}
+}
+
+// UserRepo implements the repository.UserRepository interface
+var _ ports.UserRepository = &UserInfra{}
+
+func (r *UserInfra) CreateUser(ctx context.Context, user entity.User, outboxEvents ...entity.OutboxEvent) (interface{}, error) {
+	logger.LogEvent("INFO", "Persisting user with reference: "+user.UserReference)
+
+	stored := entity.User{}
+	if err := clone(user, &stored); err != nil {
+		return nil, err
+	}
+
+	// The outbox is appended under the user lock so readers never observe
+	// the user write without its events.
+	r.mutex.Lock()
+	if r.isDuplicate(stored, "") {
+		r.mutex.Unlock()
+		return nil, ports.ErrDuplicateKey
+	}
+	r.users = append(r.users, stored)
+	r.outbox.append(outboxEvents...)
+	r.mutex.Unlock()
+
+	logger.LogEvent("INFO", "Persisting user with reference: "+user.UserReference+" completed successfully...")
+	return user.UserReference, nil
+}
+
+func (r *UserInfra) GetUserByReference(ctx context.Context, user_reference string) (interface{}, error) {
+	user, err := r.findOne(func(u entity.User) bool {
+		return u.UserReference == user_reference
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.LogEvent("INFO", "Retrieving user with user reference: "+user_reference+" completed successfully. ")
+
+	return user, nil
+}
+
+func (r *UserInfra) GetUserByPhone(ctx context.Context, phone string) (interface{}, error) {
+	user, err := r.findOne(func(u entity.User) bool {
+		return u.UserProfile.Phone == phone
+	})
+	if err != nil {
+		return nil, err
+	}
+	logger.LogEvent("INFO", "Retrieving user with user phone: "+phone+" completed successfully. ")
+	return user, nil
+}
+
+func (r *UserInfra) GetUserByWallsTag(ctx context.Context, wallsTag string) (interface{}, error) {
+	user, err := r.findOne(func(u entity.User) bool {
+		return hasWallsBadge(u, func(b entity.WallsBadge) bool {
+			return b.WallsTag == wallsTag
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.LogEvent("INFO", "Retrieving user with walls tag: "+wallsTag+" completed successfully. ")
+	return user, nil
+}
+
+func (r *UserInfra) GetUserByWallsBadgeReference(ctx context.Context, wallsBadgeReference string) (interface{}, error) {
+	user, err := r.findOne(func(u entity.User) bool {
+		return hasWallsBadge(u, func(b entity.WallsBadge) bool {
+			return b.WallsBadgeReference == wallsBadgeReference
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.LogEvent("INFO", "Retrieving user with walls tag: "+wallsBadgeReference+" completed successfully. ")
+	return user, nil
+}
+
+func (r *UserInfra) GetUserDefaultWallsBadge(ctx context.Context, userReference string) (interface{}, error) {
+	// Match the user holding at least one default walls badge, as the
+	// user_profile.walls_badge.is_default filter does in Mongo.
+	user, err := r.findOne(func(u entity.User) bool {
+		if u.UserReference != userReference {
+			return false
+		}
+		for _, badge := range u.UserProfile.WallsBadge {
+			if badge.IsDefault {
+				return true
+			}
+		}
+		return false
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	defaultBadge := user.UserProfile.WallsBadge
+
+	logger.LogEvent("INFO", fmt.Sprintf("Retrieving default walls badge for user %s completed successfully.", userReference))
+	return defaultBadge, nil
+}
+
+func (r *UserInfra) GetUserByDevice(ctx context.Context, device entity.Device) (interface{}, error) {
+	user, err := r.findOne(func(u entity.User) bool {
+		return holdsDevice(u, device, shared.DeviceTrusted)
+	})
+	if err != nil {
+		return nil, err
+	}
+	logger.LogEvent("INFO", "Retrieving user with device reference: "+device.DeviceReference+" completed successfully. ")
+	return user, nil
+}
+
+func (r *UserInfra) GetOtherUserByDevice(ctx context.Context, user_reference string, device entity.Device) (interface{}, error) {
+	user, err := r.findOne(func(u entity.User) bool {
+		return u.UserReference != user_reference && holdsDevice(u, device, shared.DeviceTrusted, shared.DevicePending)
+	})
+	if err != nil {
+		return nil, err
+	}
+	logger.LogEvent("INFO", "Retrieving other user with device reference: "+device.DeviceReference+" completed successfully. ")
+	return user, nil
+}
+
+// holdsDevice - the in-memory form of the Mongo device filter.
+func holdsDevice(user entity.User, device entity.Device, statuses ...string) bool {
+	if len(user.Devices) == 0 {
+		return user.Device.Is(device)
+	}
+	for _, registered := range user.Devices {
+		for _, status := range statuses {
+			if registered.Is(device) && registered.Status == status {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (r *UserInfra) SearchUsers(ctx context.Context, query entity.UserQuery) (interface{}, error) {
+	r.mutex.RLock()
+	matched := []entity.User{}
+	for _, stored := range r.users {
+		if !matchesUserQuery(stored, query) {
+			continue
+		}
+		user := entity.User{}
+		if err := clone(stored, &user); err != nil {
+			r.mutex.RUnlock()
+			return nil, err
+		}
+		matched = append(matched, user)
+	}
+	r.mutex.RUnlock()
+
+	// Order as the Mongo sort does: on the sort value, then the reference.
+	precedes := func(value string, reference string, user entity.User) bool {
+		userValue := entity.UserSortValue(user, query.SortBy)
+		if value != userValue {
+			return value < userValue != query.Descending
+		}
+		if reference != user.UserReference {
+			return reference < user.UserReference != query.Descending
+		}
+		return false
+	}
+	sort.Slice(matched, func(i, j int) bool {
+		return precedes(entity.UserSortValue(matched[i], query.SortBy), matched[i].UserReference, matched[j])
+	})
+
+	users := matched
+	if query.After != nil {
+		start := sort.Search(len(matched), func(i int) bool {
+			return precedes(query.After.SortValue, query.After.UserReference, matched[i])
+		})
+		users = matched[start:]
+	}
+
+	page := entity.UserPage{Users: users, Total: int64(len(matched))}
+	if int64(len(users)) > query.Limit {
+		page.Users = users[:query.Limit]
+		last := page.Users[len(page.Users)-1]
+		page.Next = &entity.UserCursor{
+			SortBy:        query.SortBy,
+			Descending:    query.Descending,
+			SortValue:     entity.UserSortValue(last, query.SortBy),
+			UserReference: last.UserReference,
+		}
+	}
+
+	logger.LogEvent("INFO", fmt.Sprintf("Searching users completed successfully, %d of %d users returned", len(page.Users), page.Total))
+	return page, nil
+}
+
+// matchesUserQuery - the in-memory form of the Mongo user search filter.
+func matchesUserQuery(user entity.User, query entity.UserQuery) bool {
+	if query.IsActive != nil && user.IsActive != *query.IsActive {
+		return false
+	}
+	if query.TierName != "" && user.Wallet.Tier.TierName != query.TierName {
+		return false
+	}
+	if query.KycVerified != nil && user.Kyc.IsVerified != *query.KycVerified {
+		return false
+	}
+	if query.EmailVerified != nil && user.UserProfile.IsVerifiedEmail != *query.EmailVerified {
+		return false
+	}
+	if query.CreatedFrom != "" && user.CreatedOn < query.CreatedFrom {
+		return false
+	}
+	if query.CreatedBefore != "" && user.CreatedOn >= query.CreatedBefore {
+		return false
+	}
+	if query.PhonePrefix != "" && !strings.HasPrefix(user.UserProfile.Phone, query.PhonePrefix) {
+		return false
+	}
+	if query.CompanyName != "" {
+		for _, company := range user.CompanyProfile {
+			if strings.Contains(strings.ToLower(company.CompanyName), strings.ToLower(query.CompanyName)) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func (r *UserInfra) UpdateUser(ctx context.Context, user_reference string, user entity.User, outboxEvents ...entity.OutboxEvent) (interface{}, error) {
+	logger.LogEvent("INFO", "Updating user with reference: "+user_reference)
+
+	update := entity.User{}
+	if err := clone(user, &update); err != nil {
+		return nil, err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	found := false
+	for i := range r.users {
+		if r.users[i].UserReference != user_reference {
+			continue
+		}
+		found = true
+		if r.users[i].Version != update.Version {
+			logger.LogEvent("ERROR", "User with reference "+user_reference+" was changed by another request")
+			return nil, ports.ErrVersionConflict
+		}
+		if r.isDuplicate(update, user_reference) {
+			return nil, ports.ErrDuplicateKey
+		}
+		// Mirror the fields set by the Mongo adapter.
+		stored := &r.users[i]
+		stored.Version++
+		stored.IsActive = update.IsActive
+		stored.UserProfile = update.UserProfile
+		tier := stored.Wallet.Tier
+		tier.TierReference = update.Wallet.Tier.TierReference
+		tier.TierName = update.Wallet.Tier.TierName
+		stored.Wallet = update.Wallet
+		stored.Wallet.Tier = tier
+		stored.BankAccounts = update.BankAccounts
+		stored.Cards = update.Cards
+		stored.Kyc.Documentations = update.Kyc.Documentations
+		stored.Kyc.IsVerified = update.Kyc.IsVerified
+		stored.Kyc.VerifiedDocumentCount = update.Kyc.VerifiedDocumentCount
+		stored.NotificationOptions = update.NotificationOptions
+		stored.Device = update.Device
+		stored.Devices = update.Devices
+		stored.Contacts = update.Contacts
+		stored.UpdatedOn = time.Now().Format(time.RFC3339)
+		stored.CompanyProfile = update.CompanyProfile
+		stored.TierRequests = update.TierRequests
+		break
+	}
+	if !found {
+		return nil, mongo.ErrNoDocuments
+	}
+	r.outbox.append(outboxEvents...)
+
+	logger.LogEvent("INFO", "User with reference "+user_reference+" updated successfully")
+	return user_reference, nil
+}
+
+func (r *UserInfra) PushBank(ctx context.Context, user_reference string, bank entity.Bank, outboxEvents ...entity.OutboxEvent) (interface{}, error) {
+	return r.updateFields(user_reference, outboxEvents, func(stored *entity.User) bool {
+		stored.BankAccounts = append(stored.BankAccounts, bank)
+		return true
+	})
+}
+
+func (r *UserInfra) PushCard(ctx context.Context, user_reference string, card entity.Card, outboxEvents ...entity.OutboxEvent) (interface{}, error) {
+	return r.updateFields(user_reference, outboxEvents, func(stored *entity.User) bool {
+		stored.Cards = append(stored.Cards, card)
+		return true
+	})
+}
+
+func (r *UserInfra) PushContact(ctx context.Context, user_reference string, contact entity.Contact, outboxEvents ...entity.OutboxEvent) (interface{}, error) {
+	return r.updateFields(user_reference, outboxEvents, func(stored *entity.User) bool {
+		stored.Contacts = append(stored.Contacts, contact)
+		return true
+	})
+}
+
+func (r *UserInfra) PushCoupon(ctx context.Context, user_reference string, coupon entity.Coupon, outboxEvents ...entity.OutboxEvent) (interface{}, error) {
+	return r.updateFields(user_reference, outboxEvents, func(stored *entity.User) bool {
+		stored.Wallet.Coupons = append(stored.Wallet.Coupons, coupon)
+		return true
+	})
+}
+
+func (r *UserInfra) SetDefaultBank(ctx context.Context, user_reference string, bankReference string, outboxEvents ...entity.OutboxEvent) (interface{}, error) {
+	return r.updateFields(user_reference, outboxEvents, func(stored *entity.User) bool {
+		found := false
+		for _, bank := range stored.BankAccounts {
+			found = found || bank.BankReference == bankReference && bank.Status == shared.VerificationVerified
+		}
+		if !found {
+			return false
+		}
+		for i := range stored.BankAccounts {
+			stored.BankAccounts[i].IsDefault = stored.BankAccounts[i].BankReference == bankReference
+		}
+		return true
+	})
+}
+
+func (r *UserInfra) SetDefaultCard(ctx context.Context, user_reference string, cardReference string, outboxEvents ...entity.OutboxEvent) (interface{}, error) {
+	return r.updateFields(user_reference, outboxEvents, func(stored *entity.User) bool {
+		found := false
+		for _, card := range stored.Cards {
+			found = found || card.CardReference == cardReference && card.Status == shared.VerificationVerified
+		}
+		if !found {
+			return false
+		}
+		for i := range stored.Cards {
+			stored.Cards[i].IsDefault = stored.Cards[i].CardReference == cardReference
+		}
+		return true
+	})
+}
+
+func (r *UserInfra) IncrementRewardPoints(ctx context.Context, user_reference string, points int, outboxEventsFor func(user entity.User) ([]entity.OutboxEvent, error)) (interface{}, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for i := range r.users {
+		if r.users[i].UserReference != user_reference {
+			continue
+		}
+		user := entity.User{}
+		if err := clone(r.users[i], &user); err != nil {
+			return nil, err
+		}
+		user.Wallet.Reward.Points += points
+		user.Version++
+		user.UpdatedOn = time.Now().Format(time.RFC3339)
+
+		// Nothing is stored unless the outbox events can be built.
+		outboxEvents, err := outboxEventsFor(user)
+		if err != nil {
+			return nil, err
+		}
+		stored := &r.users[i]
+		stored.Wallet.Reward.Points = user.Wallet.Reward.Points
+		stored.Version = user.Version
+		stored.UpdatedOn = user.UpdatedOn
+		r.outbox.append(outboxEvents...)
+
+		logger.LogEvent("INFO", "User with reference "+user_reference+" updated successfully")
+		return user, nil
+	}
+	return nil, mongo.ErrNoDocuments
+}
+
+func (r *UserInfra) SetBalance(ctx context.Context, user_reference string, balance entity.Balance, outboxEvents ...entity.OutboxEvent) (interface{}, error) {
+	return r.updateFields(user_reference, outboxEvents, func(stored *entity.User) bool {
+		if stored.Wallet.Balance.LedgerSequence > balance.LedgerSequence {
+			return false
+		}
+		stored.Wallet.Balance = balance
+		return true
+	})
+}
+
+func (r *UserInfra) SetLedgerBalance(ctx context.Context, user_reference string, balance entity.Balance, outboxEvents ...entity.OutboxEvent) (interface{}, error) {
+	return r.updateFields(user_reference, outboxEvents, func(stored *entity.User) bool {
+		if stored.Wallet.Balance.LedgerSequence >= balance.LedgerSequence {
+			return false
+		}
+		stored.Wallet.Balance = balance
+		return true
+	})
+}
+
+func (r *UserInfra) AddCoolingOffSpend(ctx context.Context, user_reference string, device entity.Device, amount float64, limit float64, outboxEvents ...entity.OutboxEvent) (interface{}, error) {
+	return r.updateFields(user_reference, outboxEvents, func(stored *entity.User) bool {
+		for i := range stored.Devices {
+			if stored.Devices[i].Is(device) && stored.Devices[i].CoolingOffSent+amount <= limit {
+				stored.Devices[i].CoolingOffSent += amount
+				return true
+			}
+		}
+		return false
+	})
+}
+
+func (r *UserInfra) SetUserActive(ctx context.Context, user_reference string, isActive bool, outboxEvents ...entity.OutboxEvent) (interface{}, error) {
+	return r.updateFields(user_reference, outboxEvents, func(stored *entity.User) bool {
+		stored.IsActive = isActive
+		return true
+	})
+}
+
+// updateFields - mirrors the Mongo adapter's targeted updates: update changes
+// the stored user in place, or reports false when the user no longer matches
+// what the update expects, which is returned as ErrVersionConflict.
+func (r *UserInfra) updateFields(user_reference string, outboxEvents []entity.OutboxEvent, update func(stored *entity.User) bool) (interface{}, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for i := range r.users {
+		if r.users[i].UserReference != user_reference {
+			continue
+		}
+		if !update(&r.users[i]) {
+			logger.LogEvent("ERROR", "User with reference "+user_reference+" was changed by another request")
+			return nil, ports.ErrVersionConflict
+		}
+		r.users[i].Version++
+		r.users[i].UpdatedOn = time.Now().Format(time.RFC3339)
+		r.outbox.append(outboxEvents...)
+
+		logger.LogEvent("INFO", "User with reference "+user_reference+" updated successfully")
+		return user_reference, nil
+	}
+	return nil, mongo.ErrNoDocuments
+}
+
+// findOne - returns a copy of the first stored user accepted by match, in
+// insertion order, or mongo.ErrNoDocuments like FindOne does.
+func (r *UserInfra) findOne(match func(entity.User) bool) (entity.User, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	for _, stored := range r.users {
+		if match(stored) {
+			user := entity.User{}
+			err := clone(stored, &user)
+			return user, err
+		}
+	}
+	return entity.User{}, mongo.ErrNoDocuments
+}
+
+// isDuplicate - whether a stored user other than self shares the user's
+// reference, phone number or a walls tag, as the unique Mongo indexes reject.
+// Callers hold the lock; self is empty for a new user.
+func (r *UserInfra) isDuplicate(user entity.User, self string) bool {
+	for _, stored := range r.users {
+		if stored.UserReference == self {
+			continue
+		}
+		if stored.UserReference == user.UserReference {
+			return true
+		}
+		if user.UserProfile.Phone != "" && stored.UserProfile.Phone == user.UserProfile.Phone {
+			return true
+		}
+		sharesTag := hasWallsBadge(user, func(badge entity.WallsBadge) bool {
+			return badge.WallsTag != "" && hasWallsBadge(stored, func(other entity.WallsBadge) bool {
+				return other.WallsTag == badge.WallsTag
+			})
+		})
+		if sharesTag {
+			return true
+		}
+	}
+	return false
+}
+
+// hasWallsBadge - checks the user profile badges and every company profile's
+// badges, the in-memory form of the $or/$elemMatch badge filters.
+func hasWallsBadge(user entity.User, match func(entity.WallsBadge) bool) bool {
+	for _, badge := range user.UserProfile.WallsBadge {
+		if match(badge) {
+			return true
+		}
+	}
+	for _, company := range user.CompanyProfile {
+		for _, badge := range company.WallsBadge {
+			if match(badge) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,376 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"walls-user-service/internal/core/domain/entity"
+	"walls-user-service/internal/core/domain/shared"
+	ports "walls-user-service/internal/port"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func newTestUser(reference string, phone string, wallsTag string) entity.User {
+	user := entity.User{
+		UserReference: reference,
+		CreatedOn:     "2024-01-01T00:00:00Z",
+		UserProfile:   entity.UserProfile{Phone: phone},
+	}
+	if wallsTag != "" {
+		user.UserProfile.WallsBadge = []entity.WallsBadge{{WallsBadgeReference: reference + "-badge", WallsTag: wallsTag}}
+	}
+	return user
+}
+
+func pendingOutboxEvents(t *testing.T, outbox *OutboxInfra) []entity.OutboxEvent {
+	t.Helper()
+	outboxEvents, err := outbox.GetPendingOutboxEvents(context.Background(), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return outboxEvents.([]entity.OutboxEvent)
+}
+
+func TestCreateAndGetUser(t *testing.T) {
+	ctx := context.Background()
+	outbox := NewOutbox()
+	users := NewUser(outbox)
+
+	user := newTestUser("user-1", "+2348000000001", "walls001")
+	user.Device = entity.Device{DeviceReference: "device-1", Imei: "123456789012345", Type: "mobile"}
+	outboxEvent := entity.OutboxEvent{OutboxReference: "outbox-1", Status: shared.OutboxPending}
+	if _, err := users.CreateUser(ctx, user, outboxEvent); err != nil {
+		t.Fatal(err)
+	}
+
+	// Mutating the caller's copy must not reach the store.
+	user.UserProfile.Phone = "+2348000000009"
+
+	for name, get := range map[string]func() (interface{}, error){
+		"reference": func() (interface{}, error) { return users.GetUserByReference(ctx, "user-1") },
+		"phone":     func() (interface{}, error) { return users.GetUserByPhone(ctx, "+2348000000001") },
+		"walls tag": func() (interface{}, error) { return users.GetUserByWallsTag(ctx, "walls001") },
+		"badge":     func() (interface{}, error) { return users.GetUserByWallsBadgeReference(ctx, "user-1-badge") },
+		"device":    func() (interface{}, error) { return users.GetUserByDevice(ctx, user.Device) },
+	} {
+		found, err := get()
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		if stored := found.(entity.User); stored.UserReference != "user-1" || stored.UserProfile.Phone != "+2348000000001" {
+			t.Errorf("%s: got %s with phone %s", name, stored.UserReference, stored.UserProfile.Phone)
+		}
+	}
+
+	if _, err := users.GetUserByReference(ctx, "user-2"); !errors.Is(err, mongo.ErrNoDocuments) {
+		t.Errorf("expected ErrNoDocuments for an unknown user, got %v", err)
+	}
+	if outboxEvents := pendingOutboxEvents(t, outbox); len(outboxEvents) != 1 || outboxEvents[0].OutboxReference != "outbox-1" {
+		t.Errorf("expected the outbox event to be stored with the user, got %v", outboxEvents)
+	}
+}
+
+func TestUpdateUserWritesTheFieldsMongoSets(t *testing.T) {
+	ctx := context.Background()
+	users := NewUser(NewOutbox())
+	if _, err := users.CreateUser(ctx, newTestUser("user-1", "+2348000000001", "")); err != nil {
+		t.Fatal(err)
+	}
+
+	found, _ := users.GetUserByReference(ctx, "user-1")
+	user := found.(entity.User)
+	user.IsActive = true
+	user.UserProfile.FirstName = "Ada"
+	user.TierRequests = []entity.TierRequest{{RequestReference: "request-1", RequestStatus: shared.TierRequestPending}}
+	// Not part of the Mongo $set, so never written by UpdateUser.
+	user.CreatedOn = "2030-01-01T00:00:00Z"
+	if _, err := users.UpdateUser(ctx, "user-1", user); err != nil {
+		t.Fatal(err)
+	}
+
+	found, _ = users.GetUserByReference(ctx, "user-1")
+	stored := found.(entity.User)
+	if !stored.IsActive || stored.UserProfile.FirstName != "Ada" || stored.Version != 1 {
+		t.Errorf("expected the update to be stored at version 1, got %+v", stored)
+	}
+	if len(stored.TierRequests) != 1 || stored.TierRequests[0].RequestReference != "request-1" {
+		t.Errorf("expected the tier request to be stored, got %+v", stored.TierRequests)
+	}
+	if stored.CreatedOn != "2024-01-01T00:00:00Z" {
+		t.Errorf("expected created_on to be left as is, got %s", stored.CreatedOn)
+	}
+
+	if _, err := users.UpdateUser(ctx, "user-2", user); !errors.Is(err, mongo.ErrNoDocuments) {
+		t.Errorf("expected ErrNoDocuments updating an unknown user, got %v", err)
+	}
+}
+
+func TestDuplicateUsersAreRejected(t *testing.T) {
+	ctx := context.Background()
+	outbox := NewOutbox()
+	users := NewUser(outbox)
+	if _, err := users.CreateUser(ctx, newTestUser("user-1", "+2348000000001", "walls001")); err != nil {
+		t.Fatal(err)
+	}
+	companyUser := newTestUser("user-2", "+2348000000002", "")
+	companyUser.CompanyProfile = []entity.CompanyProfile{{WallsBadge: []entity.WallsBadge{{WallsTag: "walls002"}}}}
+	if _, err := users.CreateUser(ctx, companyUser); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, user := range map[string]entity.User{
+		"same reference":         newTestUser("user-1", "+2348000000003", ""),
+		"same phone":             newTestUser("user-3", "+2348000000001", ""),
+		"same user walls tag":    newTestUser("user-3", "+2348000000003", "walls001"),
+		"same company walls tag": newTestUser("user-3", "+2348000000003", "walls002"),
+	} {
+		_, err := users.CreateUser(ctx, user, entity.OutboxEvent{OutboxReference: name, Status: shared.OutboxPending})
+		if !errors.Is(err, ports.ErrDuplicateKey) {
+			t.Errorf("%s: expected ErrDuplicateKey, got %v", name, err)
+		}
+	}
+	if outboxEvents := pendingOutboxEvents(t, outbox); len(outboxEvents) != 0 {
+		t.Errorf("expected no outbox events from rejected users, got %d", len(outboxEvents))
+	}
+
+	// A user may keep its own phone and tags, but not take another user's.
+	found, _ := users.GetUserByReference(ctx, "user-1")
+	user := found.(entity.User)
+	if _, err := users.UpdateUser(ctx, "user-1", user); err != nil {
+		t.Errorf("expected a user to keep its own phone and tags, got %v", err)
+	}
+	user.Version = 1
+	user.UserProfile.WallsBadge = append(user.UserProfile.WallsBadge, entity.WallsBadge{WallsTag: "walls002"})
+	if _, err := users.UpdateUser(ctx, "user-1", user); !errors.Is(err, ports.ErrDuplicateKey) {
+		t.Errorf("expected ErrDuplicateKey taking another user's walls tag, got %v", err)
+	}
+}
+
+func TestUpdateUserRejectsAStaleVersion(t *testing.T) {
+	ctx := context.Background()
+	users := NewUser(NewOutbox())
+	if _, err := users.CreateUser(ctx, newTestUser("user-1", "+2348000000001", "")); err != nil {
+		t.Fatal(err)
+	}
+
+	found, _ := users.GetUserByReference(ctx, "user-1")
+	stale := found.(entity.User)
+	if _, err := users.SetUserActive(ctx, "user-1", true); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := users.UpdateUser(ctx, "user-1", stale); !errors.Is(err, ports.ErrVersionConflict) {
+		t.Errorf("expected ErrVersionConflict writing a stale copy, got %v", err)
+	}
+}
+
+// searchTestUsers - three users differing in every field a search filters or
+// sorts on. user-1 and user-3 were last updated at the same time.
+func searchTestUsers(t *testing.T) *UserInfra {
+	t.Helper()
+	users := NewUser(NewOutbox())
+
+	user1 := newTestUser("user-1", "+2348000000001", "")
+	user1.IsActive = true
+	user1.UpdatedOn = "2024-02-01T00:00:00Z"
+	user1.UserProfile.FullName = "Chidi"
+	user1.UserProfile.IsVerifiedEmail = true
+	user1.Wallet.Tier.TierName = "basic"
+	user1.CompanyProfile = []entity.CompanyProfile{{CompanyName: "Walls Ltd"}}
+	user1.Kyc = entity.Kyc{IsVerified: true, VerifiedDocumentCount: 1}
+
+	user2 := newTestUser("user-2", "+2347000000002", "")
+	user2.CreatedOn = "2024-01-02T00:00:00Z"
+	user2.UpdatedOn = "2024-01-15T00:00:00Z"
+	user2.UserProfile.FullName = "Ada"
+	user2.Wallet.Tier.TierName = "gold"
+
+	user3 := newTestUser("user-3", "+2348000000003", "")
+	user3.IsActive = true
+	user3.CreatedOn = "2024-01-03T10:00:00Z"
+	user3.UpdatedOn = "2024-02-01T00:00:00Z"
+	user3.UserProfile.FullName = "Bola"
+	user3.Wallet.Tier.TierName = "basic"
+	user3.CompanyProfile = []entity.CompanyProfile{{CompanyName: "Acme"}}
+	// A verified documentation alone does not make the user KYC verified.
+	user3.Kyc.Documentations = []entity.Documentation{{DocumentationReference: "documentation-3", IsVerified: true}}
+
+	for _, user := range []entity.User{user1, user2, user3} {
+		if _, err := users.CreateUser(context.Background(), user); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return users
+}
+
+func referencesOf(users []entity.User) []string {
+	references := []string{}
+	for _, user := range users {
+		references = append(references, user.UserReference)
+	}
+	return references
+}
+
+func TestSearchUsersFilters(t *testing.T) {
+	users := searchTestUsers(t)
+	yes, no := true, false
+
+	for _, test := range []struct {
+		name     string
+		query    entity.UserQuery
+		expected []string
+	}{
+		{"no filters", entity.UserQuery{}, []string{"user-1", "user-2", "user-3"}},
+		{"active", entity.UserQuery{IsActive: &yes}, []string{"user-1", "user-3"}},
+		{"inactive", entity.UserQuery{IsActive: &no}, []string{"user-2"}},
+		{"tier", entity.UserQuery{TierName: "gold"}, []string{"user-2"}},
+		{"kyc verified", entity.UserQuery{KycVerified: &yes}, []string{"user-1"}},
+		{"kyc unverified", entity.UserQuery{KycVerified: &no}, []string{"user-2", "user-3"}},
+		{"email verified", entity.UserQuery{EmailVerified: &yes}, []string{"user-1"}},
+		{"created from", entity.UserQuery{CreatedFrom: "2024-01-02"}, []string{"user-2", "user-3"}},
+		{"created before", entity.UserQuery{CreatedBefore: "2024-01-03"}, []string{"user-1", "user-2"}},
+		{"phone prefix", entity.UserQuery{PhonePrefix: "+2348"}, []string{"user-1", "user-3"}},
+		{"company name", entity.UserQuery{CompanyName: "walls"}, []string{"user-1"}},
+		{"combined", entity.UserQuery{IsActive: &yes, TierName: "basic", CreatedFrom: "2024-01-02"}, []string{"user-3"}},
+		{"no match", entity.UserQuery{TierName: "platinum"}, []string{}},
+	} {
+		test.query.SortBy, test.query.Limit = "created_on", 10
+		result, err := users.SearchUsers(context.Background(), test.query)
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		page := result.(entity.UserPage)
+		if !reflect.DeepEqual(referencesOf(page.Users), test.expected) || page.Total != int64(len(test.expected)) || page.Next != nil {
+			t.Errorf("%s: expected %v, got %v of %d", test.name, test.expected, referencesOf(page.Users), page.Total)
+		}
+	}
+}
+
+func TestSearchUsersPagesInSortOrder(t *testing.T) {
+	users := searchTestUsers(t)
+
+	for _, test := range []struct {
+		sortBy     string
+		descending bool
+		expected   []string
+	}{
+		{"created_on", false, []string{"user-1", "user-2", "user-3"}},
+		{"created_on", true, []string{"user-3", "user-2", "user-1"}},
+		{"updated_on", false, []string{"user-2", "user-1", "user-3"}},
+		{"updated_on", true, []string{"user-3", "user-1", "user-2"}},
+		{"full_name", false, []string{"user-2", "user-3", "user-1"}},
+		{"full_name", true, []string{"user-1", "user-3", "user-2"}},
+	} {
+		for _, limit := range []int64{1, 2, 3} {
+			name := fmt.Sprintf("%s descending %t limit %d", test.sortBy, test.descending, limit)
+			query := entity.UserQuery{SortBy: test.sortBy, Descending: test.descending, Limit: limit}
+
+			// Follow each page's cursor until the last page.
+			found := []string{}
+			for pages := 0; pages < len(test.expected); pages++ {
+				result, err := users.SearchUsers(context.Background(), query)
+				if err != nil {
+					t.Fatalf("%s: %v", name, err)
+				}
+				page := result.(entity.UserPage)
+				if page.Total != 3 || int64(len(page.Users)) > limit {
+					t.Errorf("%s: expected at most %d of 3 users, got %d of %d", name, limit, len(page.Users), page.Total)
+				}
+				found = append(found, referencesOf(page.Users)...)
+				if page.Next == nil {
+					break
+				}
+				if page.Next.SortBy != test.sortBy || page.Next.Descending != test.descending {
+					t.Errorf("%s: expected the cursor to keep the sort, got %+v", name, page.Next)
+				}
+				query.After = page.Next
+			}
+			if !reflect.DeepEqual(found, test.expected) {
+				t.Errorf("%s: expected %v, got %v", name, test.expected, found)
+			}
+		}
+	}
+}
+
+func TestSetDefaultInstrumentsMustStillBeVerified(t *testing.T) {
+	ctx := context.Background()
+	users := NewUser(NewOutbox())
+	user := newTestUser("user-1", "+2348000000001", "")
+	user.BankAccounts = []entity.Bank{
+		{BankReference: "bank-1", Status: shared.VerificationVerified, IsDefault: true},
+		{BankReference: "bank-2", Status: shared.VerificationFailed},
+	}
+	user.Cards = []entity.Card{
+		{CardReference: "card-1", Status: shared.VerificationVerified, IsDefault: true},
+		{CardReference: "card-2", Status: shared.VerificationPending},
+	}
+	if _, err := users.CreateUser(ctx, user); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := users.SetDefaultBank(ctx, "user-1", "bank-2"); !errors.Is(err, ports.ErrVersionConflict) {
+		t.Errorf("expected ErrVersionConflict for a failed bank, got %v", err)
+	}
+	if _, err := users.SetDefaultCard(ctx, "user-1", "card-2"); !errors.Is(err, ports.ErrVersionConflict) {
+		t.Errorf("expected ErrVersionConflict for a pending card, got %v", err)
+	}
+	found, _ := users.GetUserByReference(ctx, "user-1")
+	if stored := found.(entity.User); !stored.BankAccounts[0].IsDefault || !stored.Cards[0].IsDefault || stored.Version != 0 {
+		t.Errorf("expected the verified defaults to be kept, got %+v and %+v", stored.BankAccounts, stored.Cards)
+	}
+}
+
+func TestUsersAreFoundByTheirDeviceReferenceAndImei(t *testing.T) {
+	ctx := context.Background()
+	users := NewUser(NewOutbox())
+	device := entity.Device{DeviceReference: "device-1", Imei: "123456789012345", Type: "mobile", Brand: "acme", Model: "one"}
+
+	legacy := newTestUser("user-1", "+2348000000001", "")
+	legacy.Device = device
+	registered := newTestUser("user-2", "+2348000000002", "")
+	registered.Devices = []entity.RegisteredDevice{
+		{Device: entity.Device{DeviceReference: "device-2", Imei: "222222222222222"}, Status: shared.DeviceTrusted},
+		{Device: entity.Device{DeviceReference: "device-3", Imei: "333333333333333"}, Status: shared.DevicePending},
+	}
+	for _, user := range []entity.User{legacy, registered} {
+		if _, err := users.CreateUser(ctx, user); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	updated := device
+	updated.Model = "one-2"
+	for _, test := range []struct {
+		name     string
+		device   entity.Device
+		expected string
+	}{
+		{"legacy device with a new model", updated, "user-1"},
+		{"trusted device", entity.Device{DeviceReference: "device-2", Imei: "222222222222222", Type: "tablet"}, "user-2"},
+		{"pending device", entity.Device{DeviceReference: "device-3", Imei: "333333333333333"}, ""},
+		{"another reference", entity.Device{DeviceReference: "device-4", Imei: "222222222222222"}, ""},
+	} {
+		found, err := users.GetUserByDevice(ctx, test.device)
+		if test.expected == "" {
+			if !errors.Is(err, mongo.ErrNoDocuments) {
+				t.Errorf("%s: expected ErrNoDocuments, got %v", test.name, err)
+			}
+			continue
+		}
+		if err != nil || found.(entity.User).UserReference != test.expected {
+			t.Errorf("%s: expected %s, got %v and %v", test.name, test.expected, found, err)
+		}
+	}
+
+	pending := entity.Device{DeviceReference: "device-3", Imei: "333333333333333"}
+	if found, err := users.GetOtherUserByDevice(ctx, "user-1", pending); err != nil || found.(entity.User).UserReference != "user-2" {
+		t.Errorf("expected the pending device to be held by user-2, got %v and %v", found, err)
+	}
+	if _, err := users.GetOtherUserByDevice(ctx, "user-2", pending); !errors.Is(err, mongo.ErrNoDocuments) {
+		t.Errorf("expected the user's own pending device to be left out, got %v", err)
+	}
+}
@@ -0,0 +1,265 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+	configuration "walls-user-service/internal/core/helper/configuration-helper"
+	logger "walls-user-service/internal/core/helper/log-helper"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// defaultMigrateOnStartup - whether pending migrations are applied when the
+// service connects, unless DBConnection__MigrateOnStartup says otherwise.
+var defaultMigrateOnStartup = true
+
+// ErrIrreversibleMigration - a rollback reached a migration without a Down.
+var ErrIrreversibleMigration = errors.New("migration cannot be rolled back")
+
+// Migration - an ordered change to the stored documents. Up and Down must be
+// safe to run again, as a migration interrupted before it is recorded is rerun.
+type Migration struct {
+	Version     int
+	Description string
+	// Pending - the user documents Up changes, counted on a dry run.
+	Pending bson.M
+	Up      func(ctx context.Context, db *mongo.Database) error
+	// Down - undoes Up. A migration that cannot be undone has no Down and says
+	// why in Irreversible instead.
+	Down         func(ctx context.Context, db *mongo.Database) error
+	Irreversible string
+}
+
+// AppliedMigration - a migration recorded in the schema_migrations collection.
+type AppliedMigration struct {
+	Version     int    `json:"version" bson:"_id"`
+	Description string `json:"description" bson:"description"`
+	AppliedOn   string `json:"applied_on" bson:"applied_on"`
+}
+
+// migrationRecords - where the applied migrations are recorded.
+type migrationRecords interface {
+	applied(ctx context.Context) ([]AppliedMigration, error)
+	record(ctx context.Context, migration AppliedMigration) error
+	remove(ctx context.Context, version int) error
+}
+
+type Migrator struct {
+	Database   *mongo.Database
+	Migrations []Migration
+	records    migrationRecords
+	countUsers func(ctx context.Context, filter bson.M) (int64, error)
+}
+
+func NewMigrator(db *mongo.Database) *Migrator {
+	return &Migrator{
+		Database:   db,
+		Migrations: migrations,
+		records:    mongoMigrationRecords{collection: db.Collection("schema_migrations")},
+		countUsers: func(ctx context.Context, filter bson.M) (int64, error) {
+			return db.Collection("user").CountDocuments(ctx, filter)
+		},
+	}
+}
+
+// Applied - the recorded migrations, oldest first.
+func (m *Migrator) Applied(ctx context.Context) ([]AppliedMigration, error) {
+	return m.records.applied(ctx)
+}
+
+// Pending - the migrations not applied yet, in the order they run.
+func (m *Migrator) Pending(ctx context.Context) ([]Migration, error) {
+	if err := m.validate(); err != nil {
+		return nil, err
+	}
+
+	applied, err := m.Applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+	done := map[int]bool{}
+	for _, migration := range applied {
+		done[migration.Version] = true
+	}
+
+	pending := []Migration{}
+	for _, migration := range m.Migrations {
+		if !done[migration.Version] {
+			pending = append(pending, migration)
+		}
+	}
+	return pending, nil
+}
+
+// Up - applies the pending migrations in order, recording each one as it
+// completes. A dry run only reports them. Returns the migrations applied.
+func (m *Migrator) Up(ctx context.Context, dryRun bool) ([]Migration, error) {
+	pending, err := m.Pending(ctx)
+	if err != nil {
+		logger.LogEvent("ERROR", "Unable to read schema migrations: "+err.Error())
+		return nil, err
+	}
+	if len(pending) == 0 {
+		logger.LogEvent("INFO", "Schema is up to date, no migrations to apply")
+		return pending, nil
+	}
+
+	for i, migration := range pending {
+		if dryRun {
+			m.reportPending(ctx, migration)
+			continue
+		}
+
+		logger.LogEvent("INFO", fmt.Sprintf("Applying migration %d: %s", migration.Version, migration.Description))
+		if err := migration.Up(ctx, m.Database); err != nil {
+			logger.LogEvent("ERROR", fmt.Sprintf("Migration %d failed: %s", migration.Version, err.Error()))
+			return pending[:i], err
+		}
+
+		record := AppliedMigration{
+			Version:     migration.Version,
+			Description: migration.Description,
+			AppliedOn:   time.Now().Format(time.RFC3339),
+		}
+		if err := m.records.record(ctx, record); err != nil {
+			logger.LogEvent("ERROR", fmt.Sprintf("Unable to record migration %d: %s", migration.Version, err.Error()))
+			return pending[:i], err
+		}
+	}
+
+	if !dryRun {
+		logger.LogEvent("INFO", fmt.Sprintf("%d migrations applied", len(pending)))
+	}
+	return pending, nil
+}
+
+// Down - rolls back the last steps applied migrations, newest first, and
+// removes their records. Nothing is rolled back when any of them is
+// irreversible. A dry run only reports them. Returns the migrations rolled
+// back.
+func (m *Migrator) Down(ctx context.Context, steps int, dryRun bool) ([]Migration, error) {
+	if err := m.validate(); err != nil {
+		return nil, err
+	}
+
+	applied, err := m.Applied(ctx)
+	if err != nil {
+		logger.LogEvent("ERROR", "Unable to read schema migrations: "+err.Error())
+		return nil, err
+	}
+
+	known := map[int]Migration{}
+	for _, migration := range m.Migrations {
+		known[migration.Version] = migration
+	}
+
+	planned := []Migration{}
+	for i := len(applied) - 1; i >= 0 && len(planned) < steps; i-- {
+		migration, ok := known[applied[i].Version]
+		if !ok {
+			return nil, fmt.Errorf("migration %d is not known to this build", applied[i].Version)
+		}
+		if migration.Down == nil {
+			logger.LogEvent("ERROR", fmt.Sprintf("Migration %d cannot be rolled back: %s", migration.Version, migration.Irreversible))
+			return nil, fmt.Errorf("migration %d, %s: %w", migration.Version, migration.Irreversible, ErrIrreversibleMigration)
+		}
+		planned = append(planned, migration)
+	}
+
+	rolledBack := []Migration{}
+	for _, migration := range planned {
+		if dryRun {
+			logger.LogEvent("INFO", fmt.Sprintf("Would roll back migration %d: %s", migration.Version, migration.Description))
+			rolledBack = append(rolledBack, migration)
+			continue
+		}
+
+		logger.LogEvent("INFO", fmt.Sprintf("Rolling back migration %d: %s", migration.Version, migration.Description))
+		if err := migration.Down(ctx, m.Database); err != nil {
+			logger.LogEvent("ERROR", fmt.Sprintf("Rollback of migration %d failed: %s", migration.Version, err.Error()))
+			return rolledBack, err
+		}
+		if err := m.records.remove(ctx, migration.Version); err != nil {
+			return rolledBack, err
+		}
+		rolledBack = append(rolledBack, migration)
+	}
+	return rolledBack, nil
+}
+
+// reportPending - logs what applying the migration would change.
+func (m *Migrator) reportPending(ctx context.Context, migration Migration) {
+	message := fmt.Sprintf("Would apply migration %d: %s", migration.Version, migration.Description)
+	if migration.Pending != nil {
+		count, err := m.countUsers(ctx, migration.Pending)
+		if err != nil {
+			logger.LogEvent("ERROR", fmt.Sprintf("Unable to count users for migration %d: %s", migration.Version, err.Error()))
+		} else {
+			message += fmt.Sprintf(" (%d users)", count)
+		}
+	}
+	logger.LogEvent("INFO", message)
+}
+
+// validate - the migrations must have unique versions in ascending order, and
+// each one either a Down or the reason it has none.
+func (m *Migrator) validate() error {
+	for i, migration := range m.Migrations {
+		if i > 0 && migration.Version <= m.Migrations[i-1].Version {
+			return fmt.Errorf("migration %d is out of order", migration.Version)
+		}
+		if migration.Down == nil && migration.Irreversible == "" {
+			return fmt.Errorf("migration %d has neither a Down nor the reason it is irreversible", migration.Version)
+		}
+	}
+	return nil
+}
+
+// mongoMigrationRecords - the applied migrations, one document each in the
+// schema_migrations collection keyed by version.
+type mongoMigrationRecords struct {
+	collection *mongo.Collection
+}
+
+func (r mongoMigrationRecords) applied(ctx context.Context) ([]AppliedMigration, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{}, options.Find().SetSort(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, err
+	}
+
+	applied := []AppliedMigration{}
+	err = cursor.All(ctx, &applied)
+	if err != nil {
+		return nil, err
+	}
+	return applied, nil
+}
+
+func (r mongoMigrationRecords) record(ctx context.Context, migration AppliedMigration) error {
+	// Another instance migrating at the same time may record it first.
+	_, err := r.collection.InsertOne(ctx, migration)
+	if err != nil && !mongo.IsDuplicateKeyError(err) {
+		return err
+	}
+	return nil
+}
+
+func (r mongoMigrationRecords) remove(ctx context.Context, version int) error {
+	_, err := r.collection.DeleteOne(ctx, bson.M{"_id": version})
+	return err
+}
+
+// migrateOnStartup - DBConnection__MigrateOnStartup, whether ConnectToMongo
+// applies the pending migrations.
+func migrateOnStartup() bool {
+	migrate, err := strconv.ParseBool(configuration.ServiceConfiguration.DBMigrateOnStart)
+	if err != nil {
+		return defaultMigrateOnStartup
+	}
+	return migrate
+}
@@ -0,0 +1,226 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// testMigrationRecords - applied migrations kept in memory.
+type testMigrationRecords map[int]AppliedMigration
+
+func (r testMigrationRecords) applied(ctx context.Context) ([]AppliedMigration, error) {
+	applied := []AppliedMigration{}
+	for _, migration := range r {
+		applied = append(applied, migration)
+	}
+	sort.Slice(applied, func(i, j int) bool { return applied[i].Version < applied[j].Version })
+	return applied, nil
+}
+
+func (r testMigrationRecords) record(ctx context.Context, migration AppliedMigration) error {
+	r[migration.Version] = migration
+	return nil
+}
+
+func (r testMigrationRecords) remove(ctx context.Context, version int) error {
+	delete(r, version)
+	return nil
+}
+
+// newTestMigrator - a migrator over migrations 1 to versions, logging each Up
+// and Down it runs to calls. Migrations listed as irreversible have no Down.
+func newTestMigrator(versions int, irreversible ...int) (*Migrator, testMigrationRecords, *[]string) {
+	calls := &[]string{}
+	records := testMigrationRecords{}
+	migrator := &Migrator{
+		records: records,
+		countUsers: func(ctx context.Context, filter bson.M) (int64, error) {
+			return 3, nil
+		},
+	}
+
+	for version := 1; version <= versions; version++ {
+		name := string(rune('0' + version))
+		migration := Migration{
+			Version:     version,
+			Description: "migration " + name,
+			Pending:     bson.M{"version": version},
+			Up: func(ctx context.Context, db *mongo.Database) error {
+				*calls = append(*calls, "up "+name)
+				return nil
+			},
+			Down: func(ctx context.Context, db *mongo.Database) error {
+				*calls = append(*calls, "down "+name)
+				return nil
+			},
+		}
+		for _, v := range irreversible {
+			if v == version {
+				migration.Down = nil
+				migration.Irreversible = "cannot be undone"
+			}
+		}
+		migrator.Migrations = append(migrator.Migrations, migration)
+	}
+	return migrator, records, calls
+}
+
+func versionsOf(migrations []Migration) []int {
+	versions := []int{}
+	for _, migration := range migrations {
+		versions = append(versions, migration.Version)
+	}
+	return versions
+}
+
+func TestMigratorUpAppliesPendingMigrationsInOrder(t *testing.T) {
+	ctx := context.Background()
+	migrator, records, calls := newTestMigrator(3)
+	records.record(ctx, AppliedMigration{Version: 1})
+
+	applied, err := migrator.Up(ctx, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(versionsOf(applied), []int{2, 3}) {
+		t.Errorf("expected migrations 2 and 3 to be applied, got %v", versionsOf(applied))
+	}
+	if !reflect.DeepEqual(*calls, []string{"up 2", "up 3"}) {
+		t.Errorf("expected Up of 2 then 3, got %v", *calls)
+	}
+	if len(records) != 3 || records[3].Description != "migration 3" || records[3].AppliedOn == "" {
+		t.Errorf("expected every migration to be recorded, got %v", records)
+	}
+
+	applied, err = migrator.Up(ctx, false)
+	if err != nil || len(applied) != 0 || len(*calls) != 2 {
+		t.Errorf("expected nothing left to apply, got %v and %v", versionsOf(applied), err)
+	}
+}
+
+func TestMigratorUpStopsAtAFailedMigration(t *testing.T) {
+	ctx := context.Background()
+	migrator, records, _ := newTestMigrator(3)
+	failure := errors.New("write failed")
+	migrator.Migrations[1].Up = func(ctx context.Context, db *mongo.Database) error { return failure }
+
+	applied, err := migrator.Up(ctx, false)
+	if !errors.Is(err, failure) {
+		t.Errorf("expected the migration's error, got %v", err)
+	}
+	if !reflect.DeepEqual(versionsOf(applied), []int{1}) || len(records) != 1 {
+		t.Errorf("expected only migration 1 to be applied and recorded, got %v and %v", versionsOf(applied), records)
+	}
+}
+
+func TestMigratorDryRunsChangeNothing(t *testing.T) {
+	ctx := context.Background()
+	migrator, records, calls := newTestMigrator(3)
+
+	planned, err := migrator.Up(ctx, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(versionsOf(planned), []int{1, 2, 3}) {
+		t.Errorf("expected every migration to be reported, got %v", versionsOf(planned))
+	}
+	if len(*calls) != 0 || len(records) != 0 {
+		t.Errorf("expected a dry run to neither migrate nor record, got %v and %v", *calls, records)
+	}
+
+	if _, err := migrator.Up(ctx, false); err != nil {
+		t.Fatal(err)
+	}
+	*calls = nil
+
+	planned, err = migrator.Down(ctx, 2, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(versionsOf(planned), []int{3, 2}) {
+		t.Errorf("expected migrations 3 and 2 to be reported, got %v", versionsOf(planned))
+	}
+	if len(*calls) != 0 || len(records) != 3 {
+		t.Errorf("expected a dry run to neither roll back nor remove records, got %v and %v", *calls, records)
+	}
+}
+
+func TestMigratorDownRollsBackNewestFirst(t *testing.T) {
+	ctx := context.Background()
+	migrator, records, calls := newTestMigrator(3)
+	if _, err := migrator.Up(ctx, false); err != nil {
+		t.Fatal(err)
+	}
+	*calls = nil
+
+	rolledBack, err := migrator.Down(ctx, 2, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(versionsOf(rolledBack), []int{3, 2}) {
+		t.Errorf("expected migrations 3 and 2 to be rolled back, got %v", versionsOf(rolledBack))
+	}
+	if !reflect.DeepEqual(*calls, []string{"down 3", "down 2"}) {
+		t.Errorf("expected Down of 3 then 2, got %v", *calls)
+	}
+	if _, ok := records[1]; !ok || len(records) != 1 {
+		t.Errorf("expected only migration 1 to stay recorded, got %v", records)
+	}
+
+	pending, err := migrator.Pending(ctx)
+	if err != nil || !reflect.DeepEqual(versionsOf(pending), []int{2, 3}) {
+		t.Errorf("expected migrations 2 and 3 to be pending again, got %v and %v", versionsOf(pending), err)
+	}
+}
+
+func TestMigratorRefusesToRollBackAnIrreversibleMigration(t *testing.T) {
+	ctx := context.Background()
+	migrator, records, calls := newTestMigrator(3, 2)
+	if _, err := migrator.Up(ctx, false); err != nil {
+		t.Fatal(err)
+	}
+	*calls = nil
+
+	for _, dryRun := range []bool{true, false} {
+		rolledBack, err := migrator.Down(ctx, 2, dryRun)
+		if !errors.Is(err, ErrIrreversibleMigration) {
+			t.Errorf("dry run %t: expected ErrIrreversibleMigration, got %v", dryRun, err)
+		}
+		if len(rolledBack) != 0 || len(*calls) != 0 || len(records) != 3 {
+			t.Errorf("dry run %t: expected nothing to be rolled back, got %v, %v and %v", dryRun, versionsOf(rolledBack), *calls, records)
+		}
+	}
+
+	// Migrations after the irreversible one can still be rolled back.
+	rolledBack, err := migrator.Down(ctx, 1, false)
+	if err != nil || !reflect.DeepEqual(versionsOf(rolledBack), []int{3}) {
+		t.Errorf("expected migration 3 to be rolled back, got %v and %v", versionsOf(rolledBack), err)
+	}
+}
+
+func TestMigratorValidatesTheMigrations(t *testing.T) {
+	ctx := context.Background()
+
+	outOfOrder, _, _ := newTestMigrator(3)
+	outOfOrder.Migrations[1], outOfOrder.Migrations[2] = outOfOrder.Migrations[2], outOfOrder.Migrations[1]
+	if _, err := outOfOrder.Up(ctx, false); err == nil {
+		t.Error("expected migrations out of order to be refused")
+	}
+
+	undocumented, _, _ := newTestMigrator(2)
+	undocumented.Migrations[1].Down = nil
+	if _, err := undocumented.Up(ctx, false); err == nil {
+		t.Error("expected a migration without a Down or a reason to be refused")
+	}
+
+	migrator := &Migrator{Migrations: migrations}
+	if err := migrator.validate(); err != nil {
+		t.Errorf("expected the service's migrations to be valid, got %v", err)
+	}
+}
@@ -0,0 +1,175 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"walls-user-service/internal/core/domain/entity"
+	"walls-user-service/internal/core/domain/shared"
+	logger "walls-user-service/internal/core/helper/log-helper"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// migrations - the schema migrations, in the order they are applied. Append
+// new ones with the next version; never renumber or edit an applied one.
+var migrations = []Migration{
+	{
+		// UpdateUser wrote IsActive under "enabled", so enable and disable
+		// requests never reached is_active, the field the entity decodes.
+		Version:     1,
+		Description: "move enabled to is_active",
+		Pending:     bson.M{"enabled": bson.M{"$exists": true}},
+		Up: func(ctx context.Context, db *mongo.Database) error {
+			update := mongo.Pipeline{
+				{{Key: "$set", Value: bson.M{"is_active": "$enabled"}}},
+				{{Key: "$unset", Value: "enabled"}},
+			}
+			return updateUsers(ctx, db, bson.M{"enabled": bson.M{"$exists": true}}, update)
+		},
+		Down: func(ctx context.Context, db *mongo.Database) error {
+			update := mongo.Pipeline{
+				{{Key: "$set", Value: bson.M{"enabled": "$is_active"}}},
+			}
+			return updateUsers(ctx, db, bson.M{"enabled": bson.M{"$exists": false}}, update)
+		},
+	},
+	{
+		// Users created before profile types were added are individual users.
+		Version:     2,
+		Description: "default kyc.profile_type to user",
+		Pending:     bson.M{"kyc.profile_type": bson.M{"$in": bson.A{"", nil}}},
+		Up: func(ctx context.Context, db *mongo.Database) error {
+			filter := bson.M{"kyc.profile_type": bson.M{"$in": bson.A{"", nil}}}
+			update := bson.M{"$set": bson.M{"kyc.profile_type": "user"}}
+			return updateUsers(ctx, db, filter, update)
+		},
+		Irreversible: "defaulted profile types cannot be told apart from ones users chose",
+	},
+	{
+		// Wallet tiers saved before receiving limits were added read as a limit
+		// of 0, which rejects every transfer to the user.
+		Version:     3,
+		Description: "copy wallet.tier.receiving_limit from the tier catalogue",
+		Pending:     bson.M{"wallet.tier.receiving_limit": bson.M{"$exists": false}},
+		Up: func(ctx context.Context, db *mongo.Database) error {
+			cursor, err := db.Collection("tier").Find(ctx, bson.M{})
+			if err != nil {
+				return err
+			}
+			tiers := []entity.TierConfig{}
+			err = cursor.All(ctx, &tiers)
+			if err != nil {
+				return err
+			}
+
+			for _, tier := range tiers {
+				filter := bson.M{
+					"wallet.tier.reference":       tier.TierReference,
+					"wallet.tier.receiving_limit": bson.M{"$exists": false},
+				}
+				update := bson.M{"$set": bson.M{"wallet.tier.receiving_limit": tier.ReceivingLimit}}
+				err := updateUsers(ctx, db, filter, update)
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		// Every wallet created since keeps a limit, so removing the copied ones
+		// would block transfers again rather than restore an earlier state.
+		Irreversible: "copied receiving limits cannot be told apart from ones written since",
+	},
+	{
+		// The unique walls tag index only covers users written since walls_tags
+		// was kept, so the tags of older users are filled in.
+		Version:     4,
+		Description: "fill in walls_tags from the user and company walls badges",
+		Pending:     bson.M{"walls_tags": bson.M{"$exists": false}},
+		Up: func(ctx context.Context, db *mongo.Database) error {
+			collection := db.Collection("user")
+			cursor, err := collection.Find(ctx, bson.M{"walls_tags": bson.M{"$exists": false}})
+			if err != nil {
+				return err
+			}
+			defer cursor.Close(ctx)
+
+			for cursor.Next(ctx) {
+				user := entity.User{}
+				if err := cursor.Decode(&user); err != nil {
+					return err
+				}
+				filter := bson.M{"user_reference": user.UserReference}
+				update := bson.M{"$set": bson.M{"walls_tags": wallsTags(user)}}
+				_, err := collection.UpdateOne(ctx, filter, update)
+				if err != nil {
+					return fmt.Errorf("user %s: %w", user.UserReference, writeError(err))
+				}
+			}
+			return cursor.Err()
+		},
+		Down: func(ctx context.Context, db *mongo.Database) error {
+			update := bson.M{"$unset": bson.M{"walls_tags": ""}}
+			return updateUsers(ctx, db, bson.M{"walls_tags": bson.M{"$exists": true}}, update)
+		},
+	},
+	{
+		// Users registered before the device registry only have their single
+		// device, which is trusted as the first device of their registry.
+		Version:     5,
+		Description: "seed devices from the user's device",
+		Pending:     unregisteredDevices,
+		Up: func(ctx context.Context, db *mongo.Database) error {
+			name := bson.M{"$trim": bson.M{"input": bson.M{"$concat": bson.A{
+				bson.M{"$ifNull": bson.A{"$device.brand", ""}}, " ", bson.M{"$ifNull": bson.A{"$device.model", ""}},
+			}}}}
+			update := mongo.Pipeline{
+				{{Key: "$set", Value: bson.M{"devices": bson.A{bson.M{"$mergeObjects": bson.A{"$device", bson.M{
+					"name":              name,
+					"status":            shared.DeviceTrusted,
+					"first_seen_on":     "$created_on",
+					"last_seen_on":      "$created_on",
+					"revoked_on":        "",
+					"cooling_off_until": "",
+				}}}}}}},
+			}
+			return updateUsers(ctx, db, unregisteredDevices, update)
+		},
+		Irreversible: "seeded devices cannot be told apart from ones bound since",
+	},
+	{
+		// The KYC status searches filter on is only stored when a documentation
+		// is verified, so it is computed for users verified before it was kept.
+		Version:     6,
+		Description: "store the kyc status from the verified documentations",
+		Pending:     bson.M{"kyc.is_verified": bson.M{"$exists": false}},
+		Up: func(ctx context.Context, db *mongo.Database) error {
+			verified := bson.M{"$filter": bson.M{
+				"input": bson.M{"$ifNull": bson.A{"$kyc.documentations", bson.A{}}},
+				"cond":  bson.M{"$eq": bson.A{"$$this.is_verified", true}},
+			}}
+			update := mongo.Pipeline{
+				{{Key: "$set", Value: bson.M{"kyc.verified_document_count": bson.M{"$size": verified}}}},
+				{{Key: "$set", Value: bson.M{"kyc.is_verified": bson.M{"$gt": bson.A{"$kyc.verified_document_count", 0}}}}},
+			}
+			return updateUsers(ctx, db, bson.M{"kyc.is_verified": bson.M{"$exists": false}}, update)
+		},
+		Irreversible: "computed kyc statuses cannot be told apart from ones stored by verifications since",
+	},
+}
+
+// unregisteredDevices - users with a device but no device registry.
+var unregisteredDevices = bson.M{
+	"devices":                 bson.M{"$in": bson.A{nil, bson.A{}}},
+	"device.device_reference": bson.M{"$nin": bson.A{nil, ""}},
+}
+
+// updateUsers - applies the update to every user matching the filter.
+func updateUsers(ctx context.Context, db *mongo.Database, filter bson.M, update interface{}) error {
+	result, err := db.Collection("user").UpdateMany(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	logger.LogEvent("INFO", fmt.Sprintf("%d users updated", result.ModifiedCount))
+	return nil
+}
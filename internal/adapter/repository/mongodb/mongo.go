@@ -4,20 +4,60 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"time"
 	configuration "walls-user-service/internal/core/helper/configuration-helper"
 	errorhelper "walls-user-service/internal/core/helper/error-helper"
 	logger "walls-user-service/internal/core/helper/log-helper"
 	ports "walls-user-service/internal/port"
 
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type MongoRepositories struct {
-	User ports.UserRepository
+	User   ports.UserRepository
+	Outbox ports.OutboxRepository
+	Tier   ports.TierRepository
 }
 
 func ConnectToMongo() (MongoRepositories, error) {
+	conn, err := ConnectToMongoDatabase()
+	if err != nil {
+		return MongoRepositories{}, err
+	}
+
+	logger.LogEvent("INFO", "Establishing Database collections and indexes...")
+	userCollection := conn.Collection("user")
+	outboxCollection := conn.Collection("outbox")
+	tierCollection := conn.Collection("tier")
+
+	if err := CreateIndexes(userCollection, userIndexes); err != nil {
+		return MongoRepositories{}, err
+	}
+	if err := CreateIndexes(tierCollection, tierIndexes); err != nil {
+		return MongoRepositories{}, err
+	}
+
+	if migrateOnStartup() {
+		_, err := NewMigrator(conn).Up(context.Background(), false)
+		if err != nil {
+			return MongoRepositories{}, err
+		}
+	}
+
+	repo := MongoRepositories{
+		User:   NewUser(userCollection, outboxCollection),
+		Outbox: NewOutbox(outboxCollection),
+		Tier:   NewTier(tierCollection),
+	}
+
+	return repo, nil
+}
+
+// ConnectToMongoDatabase - connects to the configured database, without setting
+// up its collections.
+func ConnectToMongoDatabase() (*mongo.Database, error) {
 	logger.LogEvent("INFO", "Establishing mongoDB connection with given credentials...")
 	//var mongoCredentials, authSource string
 	// if dbUsername != "" && dbPassword != "" {
@@ -32,7 +72,7 @@ func ConnectToMongo() (MongoRepositories, error) {
 		//log.Println(err)
 		//log.Fatal(err)
 		logger.LogEvent("ERROR", errorhelper.ErrorMessage(errorhelper.MongoDBError, err.Error()))
-		return MongoRepositories{}, err
+		return nil, err
 	}
 
 	// Check the connection
@@ -42,42 +82,76 @@ func ConnectToMongo() (MongoRepositories, error) {
 		//log.Println(err)
 		//log.Fatal(err)
 		logger.LogEvent("ERROR", errorhelper.ErrorMessage(errorhelper.MongoDBError, err.Error()))
-		return MongoRepositories{}, err
+		return nil, err
 	}
 
 	//helper.LogEvent("Info", "Connected to MongoDB!")
-	logger.LogEvent("INFO", "Establishing Database collections and indexes...")
-	conn := db.Database(configuration.ServiceConfiguration.DBName)
-
-	userCollection := conn.Collection("user")
+	return db.Database(configuration.ServiceConfiguration.DBName), nil
+}
 
-	repo := MongoRepositories{
-		User: NewUser(userCollection),
-	}
+// userIndexes - the indexes of the user collection. Phone numbers and walls
+// tags are unique among users holding one; walls_tags gathers the tags of the
+// user and company badges so one index covers both.
+var userIndexes = []mongo.IndexModel{
+	{
+		Keys:    bson.D{{Key: "user_reference", Value: 1}},
+		Options: options.Index().SetName("user_reference_unique").SetUnique(true),
+	},
+	{
+		Keys: bson.D{{Key: "user_profile.phone", Value: 1}},
+		Options: options.Index().SetName("user_profile_phone_unique").SetUnique(true).
+			SetPartialFilterExpression(bson.M{"user_profile.phone": bson.M{"$gt": ""}}),
+	},
+	{
+		Keys: bson.D{{Key: "walls_tags", Value: 1}},
+		Options: options.Index().SetName("walls_tags_unique").SetUnique(true).
+			SetPartialFilterExpression(bson.M{"walls_tags": bson.M{"$gt": ""}}),
+	},
+	{
+		Keys:    bson.D{{Key: "user_profile.walls_badge.walls_badge_reference", Value: 1}},
+		Options: options.Index().SetName("user_walls_badge_reference"),
+	},
+	{
+		Keys:    bson.D{{Key: "company_profile.walls_badge.walls_badge_reference", Value: 1}},
+		Options: options.Index().SetName("company_walls_badge_reference"),
+	},
+	{
+		Keys:    bson.D{{Key: "created_on", Value: 1}, {Key: "user_reference", Value: 1}},
+		Options: options.Index().SetName("created_on_user_reference"),
+	},
+	{
+		Keys:    bson.D{{Key: "device.imei", Value: 1}},
+		Options: options.Index().SetName("device_imei"),
+	},
+	{
+		Keys:    bson.D{{Key: "devices.imei", Value: 1}},
+		Options: options.Index().SetName("devices_imei"),
+	},
+}
 
-	return repo, nil
+// tierIndexes - the indexes of the tier catalogue. A tier reference is unique,
+// so an upsert of a stale tier fails rather than adding a second one.
+var tierIndexes = []mongo.IndexModel{
+	{
+		Keys:    bson.D{{Key: "reference", Value: 1}},
+		Options: options.Index().SetName("reference_unique").SetUnique(true),
+	},
 }
 
-// CreateIndex - creates an index for a specific field in a collection
-//func CreateIndex(collection *mongo.Collection, field string, unique bool) bool {
-//
-//	mod := mongo.IndexModel{
-//		Keys:    bson.M{field: 1}, // index in ascending order or -1 for descending order
-//		Options: options.Index().SetUnique(unique),
-//	}
-//
-//	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-//	defer cancel()
-//
-//	_, err := collection.Indexes().CreateOne(ctx, mod)
-//	if err != nil {
-//		helper.LogEvent("ERROR", err.Error())
-//		fmt.Println(err.Error())
-//
-//		return false
-//	}
-//	return true
-//}
+// CreateIndexes - creates the indexes of a collection. Existing indexes with the
+// same definition are left as they are.
+func CreateIndexes(collection *mongo.Collection, indexes []mongo.IndexModel) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	names, err := collection.Indexes().CreateMany(ctx, indexes)
+	if err != nil {
+		logger.LogEvent("ERROR", errorhelper.ErrorMessage(errorhelper.MongoDBError, "Unable to create indexes on "+collection.Name()+": "+err.Error()))
+		return err
+	}
+	logger.LogEvent("INFO", fmt.Sprintf("Indexes on %s ready: %v", collection.Name(), names))
+	return nil
+}
 
 func GetPage(page string) (*options.FindOptions, error) {
 	if page == "all" {
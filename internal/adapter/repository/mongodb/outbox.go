@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"time"
+	"walls-user-service/internal/core/domain/entity"
+	"walls-user-service/internal/core/domain/shared"
+	logger "walls-user-service/internal/core/helper/log-helper"
+	ports "walls-user-service/internal/port"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type OutboxInfra struct {
+	Collection *mongo.Collection
+}
+
+func NewOutbox(Collection *mongo.Collection) *OutboxInfra {
+	return &OutboxInfra{Collection}
+}
+
+// OutboxRepo implements the repository.OutboxRepository interface
+var _ ports.OutboxRepository = &OutboxInfra{}
+
+func (r *OutboxInfra) CreateOutboxEvent(ctx context.Context, outboxEvent entity.OutboxEvent) (interface{}, error) {
+	logger.LogEvent("INFO", "Persisting outbox event with reference: "+outboxEvent.OutboxReference)
+
+	_, err := r.Collection.InsertOne(ctx, outboxEvent)
+	if err != nil {
+		return nil, err
+	}
+
+	return outboxEvent.OutboxReference, nil
+}
+
+func (r *OutboxInfra) GetPendingOutboxEvents(ctx context.Context, limit int64) (interface{}, error) {
+	filter := bson.M{
+		"status":          shared.OutboxPending,
+		"next_attempt_on": bson.M{"$lte": time.Now().UTC().Format(time.RFC3339)},
+	}
+	findOptions := options.Find().SetSort(bson.M{"created_on": 1}).SetLimit(limit)
+
+	cursor, err := r.Collection.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	outboxEvents := []entity.OutboxEvent{}
+	err = cursor.All(ctx, &outboxEvents)
+	if err != nil {
+		return nil, err
+	}
+
+	return outboxEvents, nil
+}
+
+func (r *OutboxInfra) MarkOutboxEventDispatched(ctx context.Context, outboxReference string) (interface{}, error) {
+	filter := bson.M{"outbox_reference": outboxReference}
+	update := bson.M{"$set": bson.M{
+		"status":        shared.OutboxDispatched,
+		"dispatched_on": time.Now().UTC().Format(time.RFC3339),
+	}, "$inc": bson.M{"attempts": 1}}
+
+	_, err := r.Collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return nil, err
+	}
+
+	return outboxReference, nil
+}
+
+func (r *OutboxInfra) MarkOutboxEventFailed(ctx context.Context, outboxReference string, lastError string, nextAttemptOn string) (interface{}, error) {
+	filter := bson.M{"outbox_reference": outboxReference}
+	update := bson.M{"$set": bson.M{
+		"last_error":      lastError,
+		"next_attempt_on": nextAttemptOn,
+	}, "$inc": bson.M{"attempts": 1}}
+
+	_, err := r.Collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return nil, err
+	}
+
+	return outboxReference, nil
+}
+
+// withOutbox - runs the user write and the outbox inserts in one transaction,
+// so an event is recorded if and only if the change it describes is.
+func withOutbox(ctx context.Context, userCollection *mongo.Collection, outboxCollection *mongo.Collection, outboxEvents []entity.OutboxEvent, write func(context.Context) error) error {
+	if len(outboxEvents) == 0 {
+		return write(ctx)
+	}
+
+	return withOutboxFrom(ctx, userCollection, outboxCollection, func(ctx context.Context) ([]entity.OutboxEvent, error) {
+		return outboxEvents, write(ctx)
+	})
+}
+
+// withOutboxFrom - like withOutbox, for outbox events built from the result of
+// the user write.
+func withOutboxFrom(ctx context.Context, userCollection *mongo.Collection, outboxCollection *mongo.Collection, write func(context.Context) ([]entity.OutboxEvent, error)) error {
+	session, err := userCollection.Database().Client().StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sessionCtx mongo.SessionContext) (interface{}, error) {
+		outboxEvents, err := write(sessionCtx)
+		if err != nil || len(outboxEvents) == 0 {
+			return nil, err
+		}
+
+		documents := make([]interface{}, 0, len(outboxEvents))
+		for _, outboxEvent := range outboxEvents {
+			documents = append(documents, outboxEvent)
+		}
+		return outboxCollection.InsertMany(sessionCtx, documents)
+	})
+	return err
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"walls-user-service/internal/core/domain/entity"
+	logger "walls-user-service/internal/core/helper/log-helper"
+	ports "walls-user-service/internal/port"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type TierInfra struct {
+	Collection *mongo.Collection
+}
+
+func NewTier(Collection *mongo.Collection) *TierInfra {
+	return &TierInfra{Collection}
+}
+
+// TierRepo implements the repository.TierRepository interface
+var _ ports.TierRepository = &TierInfra{}
+
+func (r *TierInfra) UpsertTier(ctx context.Context, tier entity.TierConfig) (interface{}, error) {
+	logger.LogEvent("INFO", "Persisting tier with reference: "+tier.TierReference)
+
+	// A stale tier matches no document, so the upsert inserts it and is refused
+	// by the unique reference. A tier first added by a concurrent upsert is
+	// compared again once it exists.
+	filter := bson.M{"reference": tier.TierReference, "$or": bson.A{
+		bson.M{"updated_on": bson.M{"$lte": tier.UpdatedOn}},
+		bson.M{"updated_on": bson.M{"$exists": false}},
+	}}
+	var err error
+	for attempt := 0; attempt < 2; attempt++ {
+		_, err = r.Collection.ReplaceOne(ctx, filter, tier, options.Replace().SetUpsert(true))
+		if !mongo.IsDuplicateKeyError(err) {
+			break
+		}
+	}
+	if mongo.IsDuplicateKeyError(err) {
+		logger.LogEvent("INFO", "Tier with reference: "+tier.TierReference+" was updated later")
+		return nil, ports.ErrStaleTierUpdate.Wrap(err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return tier.TierReference, nil
+}
+
+func (r *TierInfra) GetTiers(ctx context.Context) (interface{}, error) {
+	findOptions := options.Find().SetSort(bson.M{"name": 1})
+
+	cursor, err := r.Collection.Find(ctx, bson.M{}, findOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	tiers := []entity.TierConfig{}
+	err = cursor.All(ctx, &tiers)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.LogEvent("INFO", "Retrieving tiers completed successfully. ")
+	return tiers, nil
+}
+
+func (r *TierInfra) GetTierByReference(ctx context.Context, tierReference string) (interface{}, error) {
+	tier := entity.TierConfig{}
+	filter := bson.M{"reference": tierReference}
+
+	err := r.Collection.FindOne(ctx, filter).Decode(&tier)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.LogEvent("INFO", "Retrieving tier with reference: "+tierReference+" completed successfully. ")
+	return tier, nil
+}
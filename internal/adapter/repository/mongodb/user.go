@@ -3,32 +3,40 @@ package repository
 import (
 	"context"
 	"fmt"
+	"regexp"
 	"time"
 	"walls-user-service/internal/core/domain/entity"
+	"walls-user-service/internal/core/domain/shared"
 	logger "walls-user-service/internal/core/helper/log-helper"
 	ports "walls-user-service/internal/port"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type UserInfra struct {
 	Collection *mongo.Collection
+	Outbox     *mongo.Collection
 }
 
-func NewUser(Collection *mongo.Collection) *UserInfra {
-	return &UserInfra{Collection}
+func NewUser(Collection *mongo.Collection, Outbox *mongo.Collection) *UserInfra {
+	return &UserInfra{Collection, Outbox}
 }
 
 // UserRepo implements the repository.UserRepository interface
 var _ ports.UserRepository = &UserInfra{}
 
-func (r *UserInfra) CreateUser(ctx context.Context, user entity.User) (interface{}, error) {
+func (r *UserInfra) CreateUser(ctx context.Context, user entity.User, outboxEvents ...entity.OutboxEvent) (interface{}, error) {
 	logger.LogEvent("INFO", "Persisting user with reference: "+user.UserReference)
 
-	_, err := r.Collection.InsertOne(ctx, user)
+	user.WallsTags = wallsTags(user)
+	err := withOutbox(ctx, r.Collection, r.Outbox, outboxEvents, func(ctx context.Context) error {
+		_, err := r.Collection.InsertOne(ctx, user)
+		return err
+	})
 	if err != nil {
-		return nil, err
+		return nil, writeError(err)
 	}
 
 	logger.LogEvent("INFO", "Persisting user with reference: "+user.UserReference+" completed successfully...")
@@ -135,10 +143,8 @@ func (r *UserInfra) GetUserDefaultWallsBadge(ctx context.Context, userReference
 }
 
 func (r *UserInfra) GetUserByDevice(ctx context.Context, device entity.Device) (interface{}, error) {
-	filter := bson.M{"device.type": device.Type, "device.imei": device.Imei, "device.brand": device.Brand, "device.model": device.Model}
-
 	user := entity.User{}
-	err := r.Collection.FindOne(context.TODO(), filter).Decode(&user)
+	err := r.Collection.FindOne(ctx, deviceFilter(device, shared.DeviceTrusted)).Decode(&user)
 	if err != nil {
 		return nil, err
 	}
@@ -146,25 +152,382 @@ func (r *UserInfra) GetUserByDevice(ctx context.Context, device entity.Device) (
 	return user, nil
 }
 
-func (r *UserInfra) UpdateUser(ctx context.Context, user_reference string, user entity.User) (interface{}, error) {
+func (r *UserInfra) GetOtherUserByDevice(ctx context.Context, user_reference string, device entity.Device) (interface{}, error) {
+	filter := deviceFilter(device, shared.DeviceTrusted, shared.DevicePending)
+	filter["user_reference"] = bson.M{"$ne": user_reference}
+
+	user := entity.User{}
+	err := r.Collection.FindOne(ctx, filter).Decode(&user)
+	if err != nil {
+		return nil, err
+	}
+	logger.LogEvent("INFO", "Retrieving other user with device reference: "+device.DeviceReference+" completed successfully. ")
+	return user, nil
+}
+
+// deviceFilter - the users holding the device in one of the statuses, matched
+// on its reference and IMEI. Users without a device registry yet are matched
+// on their single device.
+func deviceFilter(device entity.Device, statuses ...string) bson.M {
+	return bson.M{"$or": bson.A{
+		bson.M{"devices": bson.M{"$elemMatch": bson.M{"device_reference": device.DeviceReference, "imei": device.Imei, "status": bson.M{"$in": statuses}}}},
+		bson.M{"devices": bson.M{"$in": bson.A{nil, bson.A{}}}, "device.device_reference": device.DeviceReference, "device.imei": device.Imei},
+	}}
+}
+
+// userSummaryFields - the fields of a user a search reads, the ones the back
+// office is shown and the ones it sorts on.
+var userSummaryFields = bson.M{
+	"user_reference":                 1,
+	"created_on":                     1,
+	"updated_on":                     1,
+	"is_active":                      1,
+	"user_profile.full_name":         1,
+	"user_profile.phone":             1,
+	"user_profile.email":             1,
+	"user_profile.is_verified_email": 1,
+	"wallet.tier.reference":          1,
+	"wallet.tier.name":               1,
+	"kyc.is_verified":                1,
+	"company_profile.company_name":   1,
+}
+
+// userSortPaths - the document path of each field a user search sorts on.
+var userSortPaths = map[string]string{
+	"created_on": "created_on",
+	"updated_on": "updated_on",
+	"full_name":  "user_profile.full_name",
+}
+
+func (r *UserInfra) SearchUsers(ctx context.Context, query entity.UserQuery) (interface{}, error) {
+	filter := userSearchFilter(query)
+	total, err := r.Collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	sortPath, ok := userSortPaths[query.SortBy]
+	if !ok {
+		return nil, fmt.Errorf("users cannot be sorted by %q", query.SortBy)
+	}
+	direction, after := 1, "$gt"
+	if query.Descending {
+		direction, after = -1, "$lt"
+	}
+
+	// Continue after the last user of the previous page, the reference
+	// ordering users with the same sort value.
+	if query.After != nil {
+		filter = bson.M{"$and": bson.A{filter, bson.M{"$or": bson.A{
+			bson.M{sortPath: bson.M{after: query.After.SortValue}},
+			bson.M{sortPath: query.After.SortValue, "user_reference": bson.M{after: query.After.UserReference}},
+		}}}}
+	}
+
+	// One user more than the page shows whether another page follows.
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: sortPath, Value: direction}, {Key: "user_reference", Value: direction}}).
+		SetLimit(query.Limit + 1).
+		SetProjection(userSummaryFields)
+	cursor, err := r.Collection.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	users := []entity.User{}
+	err = cursor.All(ctx, &users)
+	if err != nil {
+		return nil, err
+	}
+
+	page := entity.UserPage{Users: users, Total: total}
+	if int64(len(users)) > query.Limit {
+		page.Users = users[:query.Limit]
+		last := page.Users[len(page.Users)-1]
+		page.Next = &entity.UserCursor{
+			SortBy:        query.SortBy,
+			Descending:    query.Descending,
+			SortValue:     entity.UserSortValue(last, query.SortBy),
+			UserReference: last.UserReference,
+		}
+	}
+
+	logger.LogEvent("INFO", fmt.Sprintf("Searching users completed successfully, %d of %d users returned", len(page.Users), total))
+	return page, nil
+}
+
+// userSearchFilter - the filter matching the users of a search.
+func userSearchFilter(query entity.UserQuery) bson.M {
+	filter := bson.M{}
+	if query.IsActive != nil {
+		filter["is_active"] = *query.IsActive
+	}
+	if query.TierName != "" {
+		filter["wallet.tier.name"] = query.TierName
+	}
+	if query.KycVerified != nil {
+		filter["kyc.is_verified"] = bson.M{"$ne": true}
+		if *query.KycVerified {
+			filter["kyc.is_verified"] = true
+		}
+	}
+	if query.EmailVerified != nil {
+		filter["user_profile.is_verified_email"] = *query.EmailVerified
+	}
+	created := bson.M{}
+	if query.CreatedFrom != "" {
+		created["$gte"] = query.CreatedFrom
+	}
+	if query.CreatedBefore != "" {
+		created["$lt"] = query.CreatedBefore
+	}
+	if len(created) > 0 {
+		filter["created_on"] = created
+	}
+	if query.PhonePrefix != "" {
+		filter["user_profile.phone"] = bson.M{"$regex": "^" + regexp.QuoteMeta(query.PhonePrefix)}
+	}
+	if query.CompanyName != "" {
+		filter["company_profile.company_name"] = bson.M{"$regex": regexp.QuoteMeta(query.CompanyName), "$options": "i"}
+	}
+	return filter
+}
+
+func (r *UserInfra) UpdateUser(ctx context.Context, user_reference string, user entity.User, outboxEvents ...entity.OutboxEvent) (interface{}, error) {
 	logger.LogEvent("INFO", "Updating user with reference: "+user_reference)
 
-	filter := bson.M{"user_reference": user_reference}
+	// Only the version that was read is written over. Users saved before
+	// versioning have none and are read as version 0.
+	filter := bson.M{"user_reference": user_reference, "version": user.Version}
+	if user.Version == 0 {
+		filter["version"] = bson.M{"$in": bson.A{0, nil}}
+	}
+	// The wallet is set field by field so that a user read with the tier
+	// catalogue's limits does not copy them back: only the tier it is on is kept.
+	update := bson.M{"$inc": bson.M{"version": 1}, "$set": bson.M{
+		"user_profile":                 user.UserProfile,
+		"wallet.wallet_reference":      user.Wallet.WalletReference,
+		"wallet.wallet_account_no":     user.Wallet.WallsAccountNo,
+		"wallet.auto_fund":             user.Wallet.AutoFund,
+		"wallet.auto_fund_level":       user.Wallet.AutoFundLevel,
+		"wallet.auto_fund_limit":       user.Wallet.AutoFundLimit,
+		"wallet.auto_withdrawal":       user.Wallet.AutoWithdrawal,
+		"wallet.auto_withdrawal_level": user.Wallet.AutoWithdrawalLevel,
+		"wallet.auto_withdrawal_limit": user.Wallet.AutoWithdrawalLimit,
+		"wallet.tier.reference":        user.Wallet.Tier.TierReference,
+		"wallet.tier.name":             user.Wallet.Tier.TierName,
+		"wallet.coupons":               user.Wallet.Coupons,
+		"wallet.reward":                user.Wallet.Reward,
+		"wallet.balance":               user.Wallet.Balance,
+		"bank_accounts":                user.BankAccounts,
+		"cards":                        user.Cards,
+		"kyc.documentations":           user.Kyc.Documentations,
+		"kyc.is_verified":              user.Kyc.IsVerified,
+		"kyc.verified_document_count":  user.Kyc.VerifiedDocumentCount,
+		"is_active":                    user.IsActive,
+		"notification_options":         user.NotificationOptions,
+		"device":                       user.Device,
+		"devices":                      user.Devices,
+		"contacts":                     user.Contacts,
+		"updated_on":                   time.Now().Format(time.RFC3339),
+		"company_profile":              user.CompanyProfile,
+		"tier_requests":                user.TierRequests,
+		"walls_tags":                   wallsTags(user),
+	}}
+
+	err := withOutbox(ctx, r.Collection, r.Outbox, outboxEvents, func(ctx context.Context) error {
+		result, err := r.Collection.UpdateOne(ctx, filter, update)
+		if err != nil {
+			return err
+		}
+		if result.MatchedCount == 0 {
+			return r.versionConflict(ctx, user_reference)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, writeError(err)
+	}
+
+	logger.LogEvent("INFO", "User with reference "+user_reference+" updated successfully")
+	return user_reference, nil
+}
+
+// versionConflict - why an update matched no user: ErrVersionConflict when the
+// user exists at another version, otherwise mongo.ErrNoDocuments.
+func (r *UserInfra) versionConflict(ctx context.Context, user_reference string) error {
+	count, err := r.Collection.CountDocuments(ctx, bson.M{"user_reference": user_reference})
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return mongo.ErrNoDocuments
+	}
+	logger.LogEvent("ERROR", "User with reference "+user_reference+" was changed by another request")
+	return ports.ErrVersionConflict
+}
+
+// writeError - a write rejected by one of the unique user indexes as
+// ErrDuplicateKey, any other error as is.
+func writeError(err error) error {
+	if mongo.IsDuplicateKeyError(err) {
+		logger.LogEvent("ERROR", "User write rejected as a duplicate: "+err.Error())
+		return ports.ErrDuplicateKey.Wrap(err)
+	}
+	return err
+}
+
+// wallsTags - the tags of the user's own and company walls badges. Blank tags
+// are left out, so they never collide in the unique index.
+func wallsTags(user entity.User) []string {
+	tags := []string{}
+	add := func(badges []entity.WallsBadge) {
+		for _, badge := range badges {
+			if badge.WallsTag != "" {
+				tags = append(tags, badge.WallsTag)
+			}
+		}
+	}
+	add(user.UserProfile.WallsBadge)
+	for _, company := range user.CompanyProfile {
+		add(company.WallsBadge)
+	}
+	return tags
+}
+
+func (r *UserInfra) PushBank(ctx context.Context, user_reference string, bank entity.Bank, outboxEvents ...entity.OutboxEvent) (interface{}, error) {
+	logger.LogEvent("INFO", "Adding bank "+bank.BankReference+" for user with reference: "+user_reference)
+	return r.updateFields(ctx, user_reference, bson.M{}, appendTo("bank_accounts", bank), outboxEvents)
+}
+
+func (r *UserInfra) PushCard(ctx context.Context, user_reference string, card entity.Card, outboxEvents ...entity.OutboxEvent) (interface{}, error) {
+	logger.LogEvent("INFO", "Adding card "+card.CardReference+" for user with reference: "+user_reference)
+	return r.updateFields(ctx, user_reference, bson.M{}, appendTo("cards", card), outboxEvents)
+}
+
+func (r *UserInfra) PushContact(ctx context.Context, user_reference string, contact entity.Contact, outboxEvents ...entity.OutboxEvent) (interface{}, error) {
+	logger.LogEvent("INFO", "Adding contact "+contact.ContactReference+" for user with reference: "+user_reference)
+	return r.updateFields(ctx, user_reference, bson.M{}, appendTo("contacts", contact), outboxEvents)
+}
+
+func (r *UserInfra) PushCoupon(ctx context.Context, user_reference string, coupon entity.Coupon, outboxEvents ...entity.OutboxEvent) (interface{}, error) {
+	logger.LogEvent("INFO", "Adding coupon "+coupon.CouponReference+" for user with reference: "+user_reference)
+	return r.updateFields(ctx, user_reference, bson.M{}, appendTo("wallet.coupons", coupon), outboxEvents)
+}
+
+// SetDefaultBank - makes the bank the user's only default bank, provided it is
+// still verified when written.
+func (r *UserInfra) SetDefaultBank(ctx context.Context, user_reference string, bankReference string, outboxEvents ...entity.OutboxEvent) (interface{}, error) {
+	logger.LogEvent("INFO", "Setting default bank "+bankReference+" for user with reference: "+user_reference)
+	filter := bson.M{"bank_accounts": bson.M{"$elemMatch": bson.M{"bank_reference": bankReference, "status": shared.VerificationVerified}}}
 	update := bson.M{"$set": bson.M{
-		"user_profile":         user.UserProfile,
-		"wallet":               user.Wallet,
-		"bank_accounts":        user.BankAccounts,
-		"cards":                user.Cards,
-		"kyc.documentations":  user.Kyc.Documentations,
-		"enabled":              user.IsActive,
-		"notification_options": user.NotificationOptions,
-		"device":               user.Device,
-		"contacts":             user.Contacts,
-		"updated_on":           time.Now().Format(time.RFC3339),
-		"company_profile":      user.CompanyProfile,
+		"bank_accounts.$[bank].is_default":  true,
+		"bank_accounts.$[other].is_default": false,
+	}}
+	arrayFilters := options.Update().SetArrayFilters(options.ArrayFilters{Filters: []interface{}{
+		bson.M{"bank.bank_reference": bankReference, "bank.status": shared.VerificationVerified},
+		bson.M{"other.bank_reference": bson.M{"$ne": bankReference}},
+	}})
+	return r.updateFields(ctx, user_reference, filter, withVersionBump(update), outboxEvents, arrayFilters)
+}
+
+// SetDefaultCard - makes the card the user's only default card, provided it is
+// still verified when written.
+func (r *UserInfra) SetDefaultCard(ctx context.Context, user_reference string, cardReference string, outboxEvents ...entity.OutboxEvent) (interface{}, error) {
+	logger.LogEvent("INFO", "Setting default card "+cardReference+" for user with reference: "+user_reference)
+	filter := bson.M{"cards": bson.M{"$elemMatch": bson.M{"card_reference": cardReference, "status": shared.VerificationVerified}}}
+	update := bson.M{"$set": bson.M{
+		"cards.$[card].is_default":  true,
+		"cards.$[other].is_default": false,
+	}}
+	arrayFilters := options.Update().SetArrayFilters(options.ArrayFilters{Filters: []interface{}{
+		bson.M{"card.card_reference": cardReference, "card.status": shared.VerificationVerified},
+		bson.M{"other.card_reference": bson.M{"$ne": cardReference}},
+	}})
+	return r.updateFields(ctx, user_reference, filter, withVersionBump(update), outboxEvents, arrayFilters)
+}
+
+func (r *UserInfra) IncrementRewardPoints(ctx context.Context, user_reference string, points int, outboxEventsFor func(user entity.User) ([]entity.OutboxEvent, error)) (interface{}, error) {
+	logger.LogEvent("INFO", fmt.Sprintf("Adding %d reward points for user with reference: %s", points, user_reference))
+	filter := bson.M{"user_reference": user_reference}
+	update := withVersionBump(bson.M{"$inc": bson.M{"wallet.reward.points": points}})
+
+	user := entity.User{}
+	err := withOutboxFrom(ctx, r.Collection, r.Outbox, func(ctx context.Context) ([]entity.OutboxEvent, error) {
+		err := r.Collection.FindOneAndUpdate(ctx, filter, update, options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&user)
+		if err != nil {
+			return nil, err
+		}
+		return outboxEventsFor(user)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.LogEvent("INFO", "User with reference "+user_reference+" updated successfully")
+	return user, nil
+}
+
+// SetBalance - replaces the user's balance unless it holds a newer ledger balance.
+func (r *UserInfra) SetBalance(ctx context.Context, user_reference string, balance entity.Balance, outboxEvents ...entity.OutboxEvent) (interface{}, error) {
+	logger.LogEvent("INFO", "Setting balance for user with reference: "+user_reference)
+	filter := bson.M{"$or": bson.A{
+		bson.M{"wallet.balance.ledger_sequence": bson.M{"$lte": balance.LedgerSequence}},
+		bson.M{"wallet.balance.ledger_sequence": nil},
 	}}
+	update := bson.M{"$set": bson.M{"wallet.balance": balance}}
+	return r.updateFields(ctx, user_reference, filter, withVersionBump(update), outboxEvents)
+}
 
-	_, err := r.Collection.UpdateOne(ctx, filter, update)
+// SetLedgerBalance - replaces the user's balance with one pushed by the ledger,
+// provided the user holds an older ledger balance.
+func (r *UserInfra) SetLedgerBalance(ctx context.Context, user_reference string, balance entity.Balance, outboxEvents ...entity.OutboxEvent) (interface{}, error) {
+	logger.LogEvent("INFO", fmt.Sprintf("Setting ledger balance %d for user with reference: %s", balance.LedgerSequence, user_reference))
+	filter := bson.M{"$or": bson.A{
+		bson.M{"wallet.balance.ledger_sequence": bson.M{"$lt": balance.LedgerSequence}},
+		bson.M{"wallet.balance.ledger_sequence": nil},
+	}}
+	update := bson.M{"$set": bson.M{"wallet.balance": balance}}
+	return r.updateFields(ctx, user_reference, filter, withVersionBump(update), outboxEvents)
+}
+
+func (r *UserInfra) SetUserActive(ctx context.Context, user_reference string, isActive bool, outboxEvents ...entity.OutboxEvent) (interface{}, error) {
+	logger.LogEvent("INFO", fmt.Sprintf("Setting user with reference: %s active: %t", user_reference, isActive))
+	update := bson.M{"$set": bson.M{"is_active": isActive}}
+	return r.updateFields(ctx, user_reference, bson.M{}, withVersionBump(update), outboxEvents)
+}
+
+func (r *UserInfra) AddCoolingOffSpend(ctx context.Context, user_reference string, device entity.Device, amount float64, limit float64, outboxEvents ...entity.OutboxEvent) (interface{}, error) {
+	logger.LogEvent("INFO", fmt.Sprintf("Adding %v to the cooling-off spend of device %s for user with reference: %s", amount, device.DeviceReference, user_reference))
+	// Devices bound before the spend was kept have none, read as 0.
+	filter := bson.M{"devices": bson.M{"$elemMatch": bson.M{
+		"device_reference": device.DeviceReference,
+		"imei":             device.Imei,
+		"cooling_off_sent": bson.M{"$not": bson.M{"$gt": limit - amount}},
+	}}}
+	update := bson.M{"$inc": bson.M{"devices.$[device].cooling_off_sent": amount}}
+	arrayFilters := options.Update().SetArrayFilters(options.ArrayFilters{Filters: []interface{}{
+		bson.M{"device.device_reference": device.DeviceReference, "device.imei": device.Imei},
+	}})
+	return r.updateFields(ctx, user_reference, filter, withVersionBump(update), outboxEvents, arrayFilters)
+}
+
+// updateFields - applies a targeted update to the user when it matches filter,
+// with its outbox events. Without a match the user is missing, or has changed in
+// a way filter guards against, reported as ErrVersionConflict.
+func (r *UserInfra) updateFields(ctx context.Context, user_reference string, filter bson.M, update interface{}, outboxEvents []entity.OutboxEvent, opts ...*options.UpdateOptions) (interface{}, error) {
+	filter["user_reference"] = user_reference
+
+	err := withOutbox(ctx, r.Collection, r.Outbox, outboxEvents, func(ctx context.Context) error {
+		result, err := r.Collection.UpdateOne(ctx, filter, update, opts...)
+		if err != nil {
+			return err
+		}
+		if result.MatchedCount == 0 {
+			return r.versionConflict(ctx, user_reference)
+		}
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -172,3 +535,35 @@ func (r *UserInfra) UpdateUser(ctx context.Context, user_reference string, user
 	logger.LogEvent("INFO", "User with reference "+user_reference+" updated successfully")
 	return user_reference, nil
 }
+
+// withVersionBump - the update, also moving the user to its next version so a
+// whole-document write of an older copy conflicts.
+func withVersionBump(update bson.M) bson.M {
+	set, ok := update["$set"].(bson.M)
+	if !ok {
+		set = bson.M{}
+		update["$set"] = set
+	}
+	set["updated_on"] = time.Now().Format(time.RFC3339)
+
+	inc, ok := update["$inc"].(bson.M)
+	if !ok {
+		inc = bson.M{}
+		update["$inc"] = inc
+	}
+	inc["version"] = 1
+	return update
+}
+
+// appendTo - a pipeline update appending item, taken literally, to the array at
+// field, which users saved with an empty list hold as null, and moving the user
+// to its next version.
+func appendTo(field string, item interface{}) mongo.Pipeline {
+	return mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{
+			field:        bson.M{"$concatArrays": bson.A{bson.M{"$ifNull": bson.A{"$" + field, bson.A{}}}, bson.A{bson.M{"$literal": item}}}},
+			"version":    bson.M{"$add": bson.A{bson.M{"$ifNull": bson.A{"$version", 0}}, 1}},
+			"updated_on": time.Now().Format(time.RFC3339),
+		}}},
+	}
+}
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"walls-user-service/internal/core/domain/entity"
+	errorhelper "walls-user-service/internal/core/helper/error-helper"
+	ports "walls-user-service/internal/port"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestUserSearchFilter(t *testing.T) {
+	yes, no := true, false
+
+	for _, test := range []struct {
+		name     string
+		query    entity.UserQuery
+		expected bson.M
+	}{
+		{"no filters", entity.UserQuery{}, bson.M{}},
+		{"active", entity.UserQuery{IsActive: &yes}, bson.M{"is_active": true}},
+		{"inactive", entity.UserQuery{IsActive: &no}, bson.M{"is_active": false}},
+		{"tier", entity.UserQuery{TierName: "gold"}, bson.M{"wallet.tier.name": "gold"}},
+		{"kyc verified", entity.UserQuery{KycVerified: &yes}, bson.M{"kyc.is_verified": true}},
+		{"kyc unverified", entity.UserQuery{KycVerified: &no}, bson.M{"kyc.is_verified": bson.M{"$ne": true}}},
+		{"email verified", entity.UserQuery{EmailVerified: &yes}, bson.M{"user_profile.is_verified_email": true}},
+		{"created from", entity.UserQuery{CreatedFrom: "2024-01-02"}, bson.M{"created_on": bson.M{"$gte": "2024-01-02"}}},
+		{
+			"created between",
+			entity.UserQuery{CreatedFrom: "2024-01-02", CreatedBefore: "2024-01-03"},
+			bson.M{"created_on": bson.M{"$gte": "2024-01-02", "$lt": "2024-01-03"}},
+		},
+		{"phone prefix", entity.UserQuery{PhonePrefix: "+234"}, bson.M{"user_profile.phone": bson.M{"$regex": `^\+234`}}},
+		{
+			"company name",
+			entity.UserQuery{CompanyName: "walls.io"},
+			bson.M{"company_profile.company_name": bson.M{"$regex": `walls\.io`, "$options": "i"}},
+		},
+	} {
+		if filter := userSearchFilter(test.query); !reflect.DeepEqual(filter, test.expected) {
+			t.Errorf("%s: expected %v, got %v", test.name, test.expected, filter)
+		}
+	}
+}
+
+func TestUserSortPathsCoverEverySortField(t *testing.T) {
+	for _, sortBy := range []string{"created_on", "updated_on", "full_name"} {
+		if _, ok := userSortPaths[sortBy]; !ok || !entity.IsUserSortField(sortBy) {
+			t.Errorf("expected users to be sortable by %s", sortBy)
+		}
+	}
+	for sortBy := range userSortPaths {
+		if !entity.IsUserSortField(sortBy) {
+			t.Errorf("expected %s to be a user sort field", sortBy)
+		}
+	}
+	if entity.IsUserSortField("password") {
+		t.Error("expected users not to be sortable by password")
+	}
+}
+
+func TestDeviceFilterMatchesReferenceAndImei(t *testing.T) {
+	device := entity.Device{DeviceReference: "device-1", Imei: "123456789012345", Type: "mobile", Brand: "acme", Model: "one"}
+	expected := bson.M{"$or": bson.A{
+		bson.M{"devices": bson.M{"$elemMatch": bson.M{"device_reference": "device-1", "imei": "123456789012345", "status": bson.M{"$in": []string{"trusted", "pending"}}}}},
+		bson.M{"devices": bson.M{"$in": bson.A{nil, bson.A{}}}, "device.device_reference": "device-1", "device.imei": "123456789012345"},
+	}}
+	if filter := deviceFilter(device, "trusted", "pending"); !reflect.DeepEqual(filter, expected) {
+		t.Errorf("expected %v, got %v", expected, filter)
+	}
+}
+
+func TestWriteErrorReportsDuplicateKeysAsConflicts(t *testing.T) {
+	duplicate := mongo.WriteException{WriteErrors: []mongo.WriteError{{Code: 11000, Message: "E11000 duplicate key error"}}}
+	err := writeError(duplicate)
+	if !errors.Is(err, ports.ErrDuplicateKey) || errorhelper.ErrorFrom(err).Code != 409 {
+		t.Errorf("expected a 409 ErrDuplicateKey, got %v", err)
+	}
+
+	other := mongo.WriteException{WriteErrors: []mongo.WriteError{{Code: 121, Message: "Document failed validation"}}}
+	if err := writeError(other); errors.Is(err, ports.ErrDuplicateKey) {
+		t.Errorf("expected any other write error to be returned as is, got %v", err)
+	}
+}
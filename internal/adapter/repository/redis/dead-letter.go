@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"walls-user-service/internal/core/domain/entity"
+	configuration "walls-user-service/internal/core/helper/configuration-helper"
+	logger "walls-user-service/internal/core/helper/log-helper"
+	ports "walls-user-service/internal/port"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// DeadLetterInfra keeps dead-lettered events on a single Redis stream, whatever
+// the event bus transport, so they survive restarts and can be replayed. The
+// stream entry ID is the dead letter reference.
+type DeadLetterInfra struct {
+	client *redis.Client
+	stream string
+}
+
+func NewDeadLetter(client *redis.Client) *DeadLetterInfra {
+	stream := configuration.ServiceConfiguration.EBDeadLetterStream
+	if stream == "" {
+		stream = "DEADLETTEREVENT:" + strings.ToUpper(configuration.ServiceConfiguration.ServiceName)
+	}
+	return &DeadLetterInfra{
+		client: client,
+		stream: stream,
+	}
+}
+
+// DeadLetterInfra implements the repository.DeadLetterRepository interface
+var _ ports.DeadLetterRepository = &DeadLetterInfra{}
+
+func (r *DeadLetterInfra) CreateDeadLetterEvent(ctx context.Context, deadLetterEvent entity.DeadLetterEvent) (interface{}, error) {
+	logger.LogEvent("INFO", "Dead-lettering event with reference: "+deadLetterEvent.EventReference)
+
+	reference, err := r.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: r.stream,
+		Values: map[string]interface{}{
+			"event_reference": deadLetterEvent.EventReference,
+			"channel":         deadLetterEvent.Channel,
+			"payload":         deadLetterEvent.Payload,
+			"error":           deadLetterEvent.Error,
+			"attempts":        deadLetterEvent.Attempts,
+			"failed_on":       deadLetterEvent.FailedOn,
+		},
+	}).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	logger.LogEvent("INFO", "Dead-lettering event with reference: "+deadLetterEvent.EventReference+" completed successfully...")
+	return reference, nil
+}
+
+func (r *DeadLetterInfra) GetDeadLetterEvents(ctx context.Context, limit int64) (interface{}, error) {
+	messages, err := r.client.XRevRangeN(ctx, r.stream, "+", "-", limit).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	deadLetterEvents := make([]entity.DeadLetterEvent, 0, len(messages))
+	for _, message := range messages {
+		deadLetterEvents = append(deadLetterEvents, toDeadLetterEvent(message))
+	}
+
+	logger.LogEvent("INFO", "Retrieving dead-lettered events completed successfully. ")
+	return deadLetterEvents, nil
+}
+
+func (r *DeadLetterInfra) GetDeadLetterEventByReference(ctx context.Context, deadLetterReference string) (interface{}, error) {
+	messages, err := r.client.XRange(ctx, r.stream, deadLetterReference, deadLetterReference).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(messages) == 0 {
+		return nil, ports.ErrDeadLetterNotFound
+	}
+
+	logger.LogEvent("INFO", "Retrieving dead-lettered event with reference: "+deadLetterReference+" completed successfully. ")
+	return toDeadLetterEvent(messages[0]), nil
+}
+
+func (r *DeadLetterInfra) DeleteDeadLetterEvent(ctx context.Context, deadLetterReference string) (interface{}, error) {
+	deleted, err := r.client.XDel(ctx, r.stream, deadLetterReference).Result()
+	if err != nil {
+		return nil, err
+	}
+	if deleted == 0 {
+		return nil, ports.ErrDeadLetterNotFound
+	}
+
+	logger.LogEvent("INFO", "Deleting dead-lettered event with reference: "+deadLetterReference+" completed successfully. ")
+	return deadLetterReference, nil
+}
+
+func toDeadLetterEvent(message redis.XMessage) entity.DeadLetterEvent {
+	field := func(name string) string {
+		value, _ := message.Values[name].(string)
+		return value
+	}
+	attempts, _ := strconv.Atoi(field("attempts"))
+
+	return entity.DeadLetterEvent{
+		DeadLetterReference: message.ID,
+		EventReference:      field("event_reference"),
+		Channel:             field("channel"),
+		Payload:             field("payload"),
+		Error:               field("error"),
+		Attempts:            attempts,
+		FailedOn:            field("failed_on"),
+	}
+}
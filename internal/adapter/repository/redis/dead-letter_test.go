@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"walls-user-service/internal/core/domain/entity"
+	errorhelper "walls-user-service/internal/core/helper/error-helper"
+	"walls-user-service/internal/core/helper/event-helper/redistest"
+	ports "walls-user-service/internal/port"
+)
+
+func TestDeadLettersAreListedNewestFirstAndUnknownOnesAreNotFound(t *testing.T) {
+	client, _ := redistest.NewClient()
+	deadLetterRepository := &DeadLetterInfra{client: client, stream: "DEADLETTEREVENT:WALLS-USER-SERVICE"}
+	ctx := context.Background()
+
+	references := []string{}
+	for _, eventReference := range []string{"event-1", "event-2"} {
+		reference, err := deadLetterRepository.CreateDeadLetterEvent(ctx, entity.DeadLetterEvent{EventReference: eventReference, Channel: "BANKVERIFIEDEVENT", Attempts: 5})
+		if err != nil {
+			t.Fatal(err)
+		}
+		references = append(references, reference.(string))
+	}
+
+	deadLetterEvents, err := deadLetterRepository.GetDeadLetterEvents(ctx, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if listed := deadLetterEvents.([]entity.DeadLetterEvent); len(listed) != 2 || listed[0].EventReference != "event-2" || listed[0].DeadLetterReference != references[1] {
+		t.Errorf("expected the latest dead letter first, got %+v", listed)
+	}
+
+	deadLetterEvent, err := deadLetterRepository.GetDeadLetterEventByReference(ctx, references[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := deadLetterEvent.(entity.DeadLetterEvent); got.EventReference != "event-1" || got.Channel != "BANKVERIFIEDEVENT" || got.Attempts != 5 {
+		t.Errorf("expected the dead letter as written, got %+v", got)
+	}
+
+	if _, err := deadLetterRepository.DeleteDeadLetterEvent(ctx, references[0]); err != nil {
+		t.Fatal(err)
+	}
+	for name, lookup := range map[string]func(context.Context, string) (interface{}, error){
+		"get":    deadLetterRepository.GetDeadLetterEventByReference,
+		"delete": deadLetterRepository.DeleteDeadLetterEvent,
+	} {
+		_, err := lookup(ctx, references[0])
+		if !errors.Is(err, ports.ErrDeadLetterNotFound) || errorhelper.ErrorFrom(err).Code != 404 {
+			t.Errorf("%s: expected a deleted dead letter to be not found, got %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+	configuration "walls-user-service/internal/core/helper/configuration-helper"
+	logger "walls-user-service/internal/core/helper/log-helper"
+	ports "walls-user-service/internal/port"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var (
+	defaultProcessedEventRetention = 7 * 24 * time.Hour
+	defaultProcessingLease         = 60 * time.Second
+
+	eventProcessing = "processing"
+	eventProcessed  = "processed"
+)
+
+// ProcessedEventInfra remembers which events have been handled as one Redis key
+// per EventReference, expiring after the retention window. A key is first set
+// as a short processing lease, so an event whose consumer dies mid-handling is
+// delivered again once the lease lapses, and is kept for the full window only
+// once its handler succeeds.
+type ProcessedEventInfra struct {
+	client     *redis.Client
+	prefix     string
+	retention  time.Duration
+	lease      time.Duration
+	duplicates string
+}
+
+func NewProcessedEvent(client *redis.Client) *ProcessedEventInfra {
+	prefix := "PROCESSEDEVENT:" + strings.ToUpper(configuration.ServiceConfiguration.ServiceName)
+	return &ProcessedEventInfra{
+		client:     client,
+		prefix:     prefix,
+		retention:  secondsOrDefault(configuration.ServiceConfiguration.EBDedupRetention, defaultProcessedEventRetention),
+		lease:      secondsOrDefault(configuration.ServiceConfiguration.EBClaimIdle, defaultProcessingLease),
+		duplicates: prefix + ":DUPLICATES",
+	}
+}
+
+// ProcessedEventInfra implements the repository.ProcessedEventRepository interface
+var _ ports.ProcessedEventRepository = &ProcessedEventInfra{}
+
+func (r *ProcessedEventInfra) ClaimEvent(ctx context.Context, eventReference string) (bool, error) {
+	claimed, err := r.client.SetNX(ctx, r.key(eventReference), eventProcessing, r.lease).Result()
+	if err != nil {
+		return false, err
+	}
+	return claimed, nil
+}
+
+func (r *ProcessedEventInfra) MarkEventProcessed(ctx context.Context, eventReference string) (interface{}, error) {
+	err := r.client.Set(ctx, r.key(eventReference), eventProcessed, r.retention).Err()
+	if err != nil {
+		return nil, err
+	}
+
+	logger.LogEvent("INFO", "Marking event with reference: "+eventReference+" as processed completed successfully...")
+	return eventReference, nil
+}
+
+func (r *ProcessedEventInfra) ReleaseEvent(ctx context.Context, eventReference string) (interface{}, error) {
+	err := r.client.Del(ctx, r.key(eventReference)).Err()
+	if err != nil {
+		return nil, err
+	}
+	return eventReference, nil
+}
+
+func (r *ProcessedEventInfra) CountDuplicateEvent(ctx context.Context, channel string) (interface{}, error) {
+	count, err := r.client.HIncrBy(ctx, r.duplicates, channel, 1).Result()
+	if err != nil {
+		return nil, err
+	}
+	return count, nil
+}
+
+func (r *ProcessedEventInfra) key(eventReference string) string {
+	return r.prefix + ":" + eventReference
+}
+
+func secondsOrDefault(value string, defaultDuration time.Duration) time.Duration {
+	seconds, err := strconv.Atoi(value)
+	if err != nil || seconds <= 0 {
+		return defaultDuration
+	}
+	return time.Duration(seconds) * time.Second
+}
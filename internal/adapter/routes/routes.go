@@ -1,8 +1,10 @@
 package routes
 
 import (
+	"log"
 	docs "walls-user-service/docs"
 	"walls-user-service/internal/adapter/api"
+	"walls-user-service/internal/core/domain/shared"
 	configuration "walls-user-service/internal/core/helper/configuration-helper"
 	errorhelper "walls-user-service/internal/core/helper/error-helper"
 	logger "walls-user-service/internal/core/helper/log-helper"
@@ -13,21 +15,56 @@ import (
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
-	"github.com/redis/go-redis/v9"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
-func SetupRouter(userRepository ports.UserRepository, redisClient *redis.Client) *gin.Engine {
+func SetupRouter(userRepository ports.UserRepository, outboxRepository ports.OutboxRepository, deadLetterRepository ports.DeadLetterRepository, tierRepository ports.TierRepository) *gin.Engine {
 	router := gin.Default()
 	router.SetTrustedProxies(nil)
 
-	userService := services.NewUserService(userRepository, redisClient)
+	userService := services.NewUserService(userRepository, outboxRepository, tierRepository)
 
-	handler := api.NewHTTPHandler(userService)
+	deadLetterService := services.NewDeadLetterService(deadLetterRepository, outboxRepository)
+
+	tierService := services.NewTierService(tierRepository)
+
+	handler := api.NewHTTPHandler(userService, deadLetterService, tierService)
+
+	// Who may call what. Routes without a policy are self-service: a user on
+	// their own account, or staff and services on any account.
+	anyRole := []string{shared.RoleUser, shared.RoleSupport, shared.RoleOps, shared.RoleSystem}
+	staff := []string{shared.RoleSupport, shared.RoleOps}
+	ledger := []string{shared.RoleOps, shared.RoleSystem}
+	operator := []string{shared.RoleOps}
+	system := []string{shared.RoleSystem}
+	// Lookups by anything but the caller's own reference return another user.
+	lookup := []string{shared.RoleSupport, shared.RoleOps, shared.RoleSystem}
+
+	defaultPolicy := middleware.Policy{Roles: anyRole}
+	policies := middleware.Policies{
+		"PUT /api/user/:user_reference/enable":                       {Roles: staff},
+		"PUT /api/user/:user_reference/disable":                      {Roles: staff},
+		"PUT /api/user/:user_reference/balance":                      {Roles: ledger},
+		"PUT /api/user/:user_reference/tier":                         {Roles: ledger},
+		"PUT /api/user/:user_reference/coupon":                       {Roles: ledger},
+		"PUT /api/user/:user_reference/reward":                       {Roles: ledger},
+		"GET /api/users":                                             {Roles: staff},
+		"GET /api/user/phone/:phone":                                 {Roles: lookup},
+		"GET /api/user/walls-tag/:wallsTag":                          {Roles: lookup},
+		"GET /api/user/walls-badge-reference/:walls_badge_reference": {Roles: lookup},
+		"POST /api/user/device":                                      {Roles: lookup},
+		// Only the OTP validation flow marks an email verified.
+		"PUT /api/user/:user_reference/email/update-status":                            {Roles: system},
+		"PUT /api/user/:user_reference/company/:company_reference/email/update-status": {Roles: system},
+		"GET /api/admin/dead-letters":                                                  {Roles: operator},
+		"GET /api/admin/dead-letters/:dead_letter_reference":                           {Roles: operator},
+		"POST /api/admin/dead-letters/:dead_letter_reference/replay":                   {Roles: operator},
+	}
 
 	logger.LogEvent("INFO", "Configuring Routes!")
 	router.Use(middleware.LogRequest)
+	router.Use(middleware.HandleErrors)
 
 	corrs_config := cors.DefaultConfig()
 	corrs_config.AllowAllOrigins = true
@@ -39,6 +76,12 @@ func SetupRouter(userRepository ports.UserRepository, redisClient *redis.Client)
 	docs.SwaggerInfo.Version = "1.0"
 	docs.SwaggerInfo.Title = configuration.ServiceConfiguration.ServiceName
 
+	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+
+	// Routes registered from here on require a bearer token
+	router.Use(middleware.Authenticate())
+	router.Use(middleware.Authorize(policies, defaultPolicy))
+
 	router.POST("/api/user", handler.CreateUser)
 	router.POST("/api/user/:user_reference/company", handler.CreateCompanyProfile)
 	router.POST("/api/user/:user_reference/company/walls-badge", handler.CreateCompanyWallsBadge)
@@ -69,9 +112,13 @@ func SetupRouter(userRepository ports.UserRepository, redisClient *redis.Client)
 	router.PUT("/api/user/:user_reference/card/:card_reference", handler.UpdateCard)
 	router.PUT("/api/user/:user_reference/notification-options", handler.UpdateNotificationOptions)
 	router.PUT("/api/user/:user_reference/device", handler.UpdateDevice)
+	router.POST("/api/user/:user_reference/devices/bind", handler.RequestDeviceBinding)
+	router.GET("/api/user/:user_reference/devices", handler.ListDevices)
+	router.PUT("/api/user/:user_reference/devices/:device_reference/revoke", handler.RevokeDevice)
 	router.POST("/api/user/:user_reference/identification", handler.AddDocumentation)
 	router.PUT("/api/user/:user_reference/identification/:identification_reference", handler.UpdateDocumentation)
 	router.POST("/api/user/:user_reference/contact", handler.AddContact)
+	router.GET("/api/users", handler.SearchUsers)
 	router.GET("/api/user/:user_reference", handler.GetUserByReference)
 	router.GET("/api/user/phone/:phone", handler.GetUserByPhone)
 	router.GET("/api/user/walls-tag/:wallsTag", handler.GetUserByWallsTag)
@@ -91,7 +138,18 @@ func SetupRouter(userRepository ports.UserRepository, redisClient *redis.Client)
 	router.POST("/api/user/upgrade-tier", handler.UpgradeTierRequest)
 	router.POST("/api/user/transaction", handler.CreateTransactionRequest)
 
-	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+	router.GET("/api/tiers", handler.GetTiers)
+	router.GET("/api/tiers/:reference", handler.GetTierByReference)
+
+	router.GET("/api/admin/dead-letters", handler.GetDeadLetterEvents)
+	router.GET("/api/admin/dead-letters/:dead_letter_reference", handler.GetDeadLetterEvent)
+	router.POST("/api/admin/dead-letters/:dead_letter_reference/replay", handler.ReplayDeadLetterEvent)
+
+	//Refuse to start with a policy for a route that does not exist
+	if err := policies.Validate(router.Routes()); err != nil {
+		logger.LogEvent("ERROR", "Route policy validation error: "+err.Error())
+		log.Fatal(err)
+	}
 
 	router.NoRoute(func(ctx *gin.Context) {
 		ctx.JSON(404,
@@ -0,0 +1,44 @@
+package routes
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	memoryRepository "walls-user-service/internal/adapter/repository/memory"
+	configuration "walls-user-service/internal/core/helper/configuration-helper"
+	"walls-user-service/internal/core/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestUsersCannotReachOtherUsersOrVerifyTheirOwnEmail(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tokenMode := configuration.ServiceConfiguration.TokenMode
+	configuration.ServiceConfiguration.TokenMode = middleware.HeaderAuthMode
+	defer func() { configuration.ServiceConfiguration.TokenMode = tokenMode }()
+
+	outbox := memoryRepository.NewOutbox()
+	router := SetupRouter(memoryRepository.NewUser(outbox), outbox, nil, memoryRepository.NewTier())
+
+	for _, route := range []struct {
+		method string
+		path   string
+	}{
+		{http.MethodPut, "/api/user/user-1/email/update-status"},
+		{http.MethodPut, "/api/user/user-1/company/company-1/email/update-status"},
+		{http.MethodGet, "/api/user/phone/+2348000000002"},
+		{http.MethodGet, "/api/user/walls-tag/walls002"},
+		{http.MethodGet, "/api/user/walls-badge-reference/badge-2"},
+		{http.MethodPost, "/api/user/device"},
+	} {
+		// A header mode caller is always a user, here user-1.
+		request := httptest.NewRequest(route.method, route.path, nil)
+		request.Header.Set("X-User-Reference", "user-1")
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+
+		if recorder.Code != http.StatusForbidden {
+			t.Errorf("%s %s: expected a user to be refused, got %d", route.method, route.path, recorder.Code)
+		}
+	}
+}
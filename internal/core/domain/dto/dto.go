@@ -94,6 +94,13 @@ type UpdateNotificationOptionsDto struct {
 
 type UpdateDeviceDto struct {
 	NewDevice entity.Device `json:"new_device" bson:"new_device" validate:"required,dive"`
+	Name      string        `json:"name" bson:"name" validate:"max=64"`
+}
+
+type BindDeviceDto struct {
+	Contact string `json:"contact" bson:"contact" validate:"required,valid_contact"`
+	Channel string `json:"channel" bson:"channel" validate:"required,eq=sms|eq=email"`
+	Name    string `json:"name" bson:"name" validate:"max=64"`
 }
 
 type AddDocumentationDto struct {
@@ -108,6 +115,7 @@ type CurrentUserDto struct {
 	UserReference string    `json:"user_reference" bson:"user_reference" validate:"required,guid,min=32,max=38"`
 	Phone         string    `json:"phone" bson:"phone" validate:"required,valid_contact"`
 	Device        DeviceDto `json:"device" bson:"device" validate:"required,dive"`
+	Roles         []string  `json:"roles" bson:"roles"`
 }
 
 type DeviceDto struct {
@@ -132,8 +140,11 @@ type UserDto struct {
 	Cards               []entity.Card              `json:"cards" bson:"cards"`
 	NotificationOptions entity.NotificationOptions `json:"notification_options" bson:"notification_options"`
 	Device              entity.Device              `json:"device" bson:"device"`
+	Devices             []entity.RegisteredDevice  `json:"devices" bson:"devices"`
 	Kyc                 KycDto                     `json:"kyc" bson:"kyc"`
 	LastSyncedOn        string                     `json:"last_synced_on" bson:"last_synced_on"`
+	TierRequests        []entity.TierRequest       `json:"tier_requests" bson:"tier_requests"`
+	Version             int64                      `json:"version" bson:"version"`
 }
 
 type WalletDto struct {
@@ -155,11 +166,11 @@ type TierDto struct {
 	TierReference         string   `json:"reference" bson:"reference" validate:"required,uuid"`
 	TierName              string   `json:"name" bson:"name" validate:"required"`
 	SendingLimit          float64  `json:"sending_limit" bson:"sending_limit" validate:"gte=0"`
+	ReceivingLimit        float64  `json:"receiving_limit" bson:"receiving_limit" validate:"gte=0"`
+	WalletLimit           float64  `json:"wallet_limit" bson:"wallet_limit" validate:"gte=0"`
 	MinimumBalance        float64  `json:"minimum_balance" bson:"minimum_balance" validate:"gte=0"`
 	DailyTransactionLimit float64  `json:"transaction_limit" bson:"transaction_limit" validate:"gt=0"`
 	UpgradeOptions        []string `json:"upgrade_options" bson:"upgrade_options"`
-	
-
 }
 
 type KycDto struct {
@@ -215,7 +226,7 @@ type IdentityDto struct {
 	Device DeviceDto `json:"device" bson:"device" validate:"required"`
 }
 type CreateOtpDto struct {
-	OtpType string    `json:"otp_type" bson:"otp_type" validate:"required,eq=create_user|eq=create_company|eq=verify_email|eq=verify_phone"`
+	OtpType string    `json:"otp_type" bson:"otp_type" validate:"required,eq=create_user|eq=create_company|eq=verify_email|eq=verify_phone|eq=bind_device"`
 	Contact string    `json:"contact" bson:"contact" validate:"required,valid_contact"`
 	Channel string    `json:"channel" bson:"channel" validate:"eq=sms|eq=email|eq=in_app"`
 	Device  DeviceDto `json:"device" bson:"device" validate:"required,dive"`
@@ -223,7 +234,7 @@ type CreateOtpDto struct {
 
 type ValidateOtpDto struct {
 	Otp     string    `json:"otp" bson:"otp" validate:"required,len=6"`
-	OtpType string    `json:"otp_type" bson:"otp_type" validate:"required,eq=create_user|eq=create_company|eq=verify_email|eq=verify_phone"`
+	OtpType string    `json:"otp_type" bson:"otp_type" validate:"required,eq=create_user|eq=create_company|eq=verify_email|eq=verify_phone|eq=bind_device"`
 	Contact string    `json:"contact" bson:"contact" validate:"valid_contact"`
 	Device  DeviceDto `json:"device" bson:"device" validate:"required,dive"`
 }
@@ -249,3 +260,85 @@ type CreateTransactionDto struct {
 type DocumentReferenceDto struct {
 	DocumentReference      []string `json:"document_references" bson:"document_references" validate:"required,dive,uuid4"`
 }
+
+type DocumentationVerificationDto struct {
+	IsVerified         bool   `json:"is_verified" bson:"is_verified"`
+	VerifiedOn         string `json:"verified_on" bson:"verified_on"`
+	VerificationMethod string `json:"verification_method" bson:"verification_method"`
+}
+
+type TierUpgradeDecisionDto struct {
+	RequestStatus string      `json:"request_status" bson:"request_status" validate:"required,eq=approved|eq=rejected"`
+	ApprovedTier  entity.Tier `json:"approved_tier" bson:"approved_tier"`
+	Reason        string      `json:"reason" bson:"reason"`
+	DecidedOn     string      `json:"decided_on" bson:"decided_on"`
+}
+
+type PaymentMethodVerificationDto struct {
+	IntegrationType      string `json:"integration_type" bson:"integration_type"`
+	IntegrationReference string `json:"integration_reference" bson:"integration_reference"`
+	Status               string `json:"status" bson:"status" validate:"required,eq=pending|eq=verified|eq=failed"`
+	StatusReason         string `json:"status_reason" bson:"status_reason"`
+	VerifiedOn           string `json:"verified_on" bson:"verified_on"`
+}
+
+type PhotoVerificationDto struct {
+	Status          string `json:"status" bson:"status" validate:"required,eq=verified|eq=rejected"`
+	RejectionReason string `json:"rejection_reason" bson:"rejection_reason"`
+	VerifiedOn      string `json:"verified_on" bson:"verified_on"`
+}
+
+type LedgerBalanceDto struct {
+	WalletReference       string  `json:"wallet_reference" bson:"wallet_reference"`
+	AvailableAmount       float64 `json:"available_amount" bson:"available_amount" validate:"number"`
+	PendingIncomingAmount float64 `json:"pending_incoming_amount" bson:"pending_incoming_amount" validate:"number"`
+	Currency              string  `json:"currency" bson:"currency"`
+	LedgerSequence        int64   `json:"ledger_sequence" bson:"ledger_sequence" validate:"required,gt=0"`
+}
+
+type TierConfigDto struct {
+	Tier      entity.Tier `json:"tier" bson:"tier" validate:"required"`
+	IsActive  bool        `json:"is_active" bson:"is_active"`
+	UpdatedOn string      `json:"updated_on" bson:"updated_on"`
+}
+
+// UserSearchDto - the query of a back-office user search. Dates are inclusive
+// and formatted as 2006-01-02.
+type UserSearchDto struct {
+	IsActive      *bool  `json:"is_active" form:"is_active"`
+	TierName      string `json:"tier" form:"tier" validate:"omitempty,max=64"`
+	KycVerified   *bool  `json:"kyc_verified" form:"kyc_verified"`
+	EmailVerified *bool  `json:"email_verified" form:"email_verified"`
+	CreatedFrom   string `json:"created_from" form:"created_from" validate:"omitempty,datetime=2006-01-02"`
+	CreatedTo     string `json:"created_to" form:"created_to" validate:"omitempty,datetime=2006-01-02"`
+	PhonePrefix   string `json:"phone_prefix" form:"phone_prefix" validate:"omitempty,max=20"`
+	CompanyName   string `json:"company_name" form:"company_name" validate:"omitempty,max=100"`
+	Sort          string `json:"sort" form:"sort" validate:"omitempty,eq=created_on|eq=updated_on|eq=full_name"`
+	Order         string `json:"order" form:"order" validate:"omitempty,eq=asc|eq=desc"`
+	Limit         int64  `json:"limit" form:"limit" validate:"omitempty,gte=1,lte=100"`
+	Cursor        string `json:"cursor" form:"cursor"`
+}
+
+// UserSummaryDto - a user as the back office lists them, without their wallet,
+// payment methods, devices or documents.
+type UserSummaryDto struct {
+	UserReference   string   `json:"user_reference"`
+	FullName        string   `json:"full_name"`
+	Phone           string   `json:"phone"`
+	Email           string   `json:"email"`
+	IsVerifiedEmail bool     `json:"is_verified_email"`
+	IsActive        bool     `json:"is_active"`
+	TierName        string   `json:"tier"`
+	KycVerified     bool     `json:"kyc_verified"`
+	CompanyNames    []string `json:"company_names"`
+	CreatedOn       string   `json:"created_on"`
+	UpdatedOn       string   `json:"updated_on"`
+}
+
+// UserPageDto - a page of a back-office user search with the number of users
+// matching it. NextCursor is empty on the last page.
+type UserPageDto struct {
+	Users      []UserSummaryDto `json:"users"`
+	Total      int64            `json:"total"`
+	NextCursor string           `json:"next_cursor"`
+}
@@ -0,0 +1,11 @@
+package entity
+
+type DeadLetterEvent struct {
+	DeadLetterReference string `json:"dead_letter_reference" bson:"dead_letter_reference"`
+	EventReference      string `json:"event_reference" bson:"event_reference"`
+	Channel             string `json:"channel" bson:"channel"`
+	Payload             string `json:"payload" bson:"payload"`
+	Error               string `json:"error" bson:"error"`
+	Attempts            int    `json:"attempts" bson:"attempts"`
+	FailedOn            string `json:"failed_on" bson:"failed_on"`
+}
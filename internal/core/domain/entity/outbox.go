@@ -0,0 +1,15 @@
+package entity
+
+type OutboxEvent struct {
+	OutboxReference string `json:"outbox_reference" bson:"outbox_reference"`
+	EventReference  string `json:"event_reference" bson:"event_reference"`
+	UserReference   string `json:"user_reference" bson:"user_reference"`
+	Channel         string `json:"channel" bson:"channel"`
+	Payload         string `json:"payload" bson:"payload"`
+	Status          string `json:"status" bson:"status"`
+	Attempts        int    `json:"attempts" bson:"attempts"`
+	LastError       string `json:"last_error" bson:"last_error"`
+	CreatedOn       string `json:"created_on" bson:"created_on"`
+	NextAttemptOn   string `json:"next_attempt_on" bson:"next_attempt_on"`
+	DispatchedOn    string `json:"dispatched_on" bson:"dispatched_on"`
+}
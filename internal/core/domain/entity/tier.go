@@ -0,0 +1,9 @@
+package entity
+
+// TierConfig - a tier in the tier catalogue. Users keep the reference of their
+// tier, and its limits are resolved from the catalogue.
+type TierConfig struct {
+	Tier      `bson:",inline"`
+	IsActive  bool   `json:"is_active" bson:"is_active"`
+	UpdatedOn string `json:"updated_on" bson:"updated_on"`
+}
@@ -0,0 +1,57 @@
+package entity
+
+// UserQuery - a back-office search over users. Unset filters match every user.
+type UserQuery struct {
+	IsActive      *bool
+	TierName      string
+	KycVerified   *bool
+	EmailVerified *bool
+	// CreatedFrom and CreatedBefore bound created_on, the first inclusively.
+	CreatedFrom   string
+	CreatedBefore string
+	PhonePrefix   string
+	CompanyName   string
+	SortBy        string
+	Descending    bool
+	// After - the last user of the previous page, nil for the first page.
+	After *UserCursor
+	Limit int64
+}
+
+// UserCursor - where a page of a user search ends: the sort value and reference
+// of its last user. The reference orders users with the same sort value.
+type UserCursor struct {
+	SortBy        string `json:"sort_by"`
+	Descending    bool   `json:"descending"`
+	SortValue     string `json:"sort_value"`
+	UserReference string `json:"user_reference"`
+}
+
+// UserPage - a page of a user search with the number of users matching it, and
+// where the next page starts when there is one.
+type UserPage struct {
+	Users []User
+	Total int64
+	Next  *UserCursor
+}
+
+// IsUserSortField - whether a user search can sort on the field.
+func IsUserSortField(sortBy string) bool {
+	switch sortBy {
+	case "created_on", "updated_on", "full_name":
+		return true
+	}
+	return false
+}
+
+// UserSortValue - the value of the user a search sorts on.
+func UserSortValue(user User, sortBy string) string {
+	switch sortBy {
+	case "updated_on":
+		return user.UpdatedOn
+	case "full_name":
+		return user.UserProfile.FullName
+	default:
+		return user.CreatedOn
+	}
+}
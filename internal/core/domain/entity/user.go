@@ -14,7 +14,13 @@ type User struct {
 	Cards               []Card              `json:"cards" bson:"cards"`
 	NotificationOptions NotificationOptions `json:"notification_options" bson:"notification_options"`
 	Device              Device              `json:"device" bson:"device"`
+	Devices             []RegisteredDevice  `json:"devices" bson:"devices"`
 	Kyc                 Kyc                 `json:"kyc" bson:"kyc"`
+	TierRequests        []TierRequest       `json:"tier_requests" bson:"tier_requests"`
+	Version             int64               `json:"version" bson:"version"`
+	// WallsTags - the tags of every user and company walls badge, kept by the
+	// repository for the unique walls tag index.
+	WallsTags []string `json:"-" bson:"walls_tags,omitempty"`
 }
 
 type UserProfile struct {
@@ -70,6 +76,9 @@ type Photo struct {
 	IsDefault         bool   `json:"is_default" bson:"is_default" validate:"boolean"`
 	IsVerified        bool   `json:"is_verified" bson:"is_verified" validate:"boolean"`
 	DocumentReference string `json:"document_reference" bson:"document_reference" validate:"required,guid"`
+	Status            string `json:"status" bson:"status"`
+	RejectionReason   string `json:"rejection_reason" bson:"rejection_reason"`
+	VerifiedOn        string `json:"verified_on" bson:"verified_on"`
 }
 
 type Contact struct {
@@ -144,6 +153,7 @@ type Balance struct {
 	PendingIncomingAmount float64 `json:"pending_incoming_amount" bson:"pending_incoming_amount"`
 	IsSynced              bool    `json:"is_synced" bson:"is_synced"`
 	LastSyncedOn          string  `json:"last_synced_on" bson:"last_synced_on"`
+	LedgerSequence        int64   `json:"ledger_sequence" bson:"ledger_sequence"`
 }
 
 type Bank struct {
@@ -154,6 +164,9 @@ type Bank struct {
 	AccountNumber        int64  `json:"account_number" bson:"account_number"`
 	AccountName          string `json:"account_name" bson:"account_name"`
 	IsDefault            bool   `json:"is_default" bson:"is_default"`
+	Status               string `json:"status" bson:"status"`
+	StatusReason         string `json:"status_reason" bson:"status_reason"`
+	VerifiedOn           string `json:"verified_on" bson:"verified_on"`
 }
 
 type Card struct {
@@ -165,6 +178,9 @@ type Card struct {
 	ExpiryMonth          int    `json:"expiry_month" bson:"expiry_month"`
 	ExpiryYear           int    `json:"expiry_year" bson:"expiry_year"`
 	IsDefault            bool   `json:"is_default" bson:"is_default"`
+	Status               string `json:"status" bson:"status"`
+	StatusReason         string `json:"status_reason" bson:"status_reason"`
+	VerifiedOn           string `json:"verified_on" bson:"verified_on"`
 }
 
 type NotificationOptions struct {
@@ -181,6 +197,27 @@ type Device struct {
 	Model           string `json:"model" bson:"model" validate:"required"`
 }
 
+// Is - whether other is the same device. Devices are identified by their
+// reference and IMEI, so one whose type, brand or model has changed still matches.
+func (device Device) Is(other Device) bool {
+	return device.DeviceReference == other.DeviceReference && device.Imei == other.Imei
+}
+
+// RegisteredDevice - a device the user may act from while it is trusted. A device
+// is pending until its binding is confirmed by OTP, and its outgoing transactions
+// are limited until CoolingOffUntil. CoolingOffSent is what it has sent since it
+// was bound.
+type RegisteredDevice struct {
+	Device          `bson:",inline"`
+	Name            string `json:"name" bson:"name"`
+	Status          string `json:"status" bson:"status"`
+	FirstSeenOn     string `json:"first_seen_on" bson:"first_seen_on"`
+	LastSeenOn      string `json:"last_seen_on" bson:"last_seen_on"`
+	RevokedOn       string `json:"revoked_on" bson:"revoked_on"`
+	CoolingOffUntil string  `json:"cooling_off_until" bson:"cooling_off_until"`
+	CoolingOffSent  float64 `json:"cooling_off_sent" bson:"cooling_off_sent"`
+}
+
 type Documentation struct {
 	DocumentationReference string `json:"documentation_reference" bson:"documentation_reference"`
 	DocumentationType      string `json:"documentation_type" bson:"documentation_type" validate:"required,eq=international_passport|eq=bvn|eq=nin|drivers_license|voters_card"`
@@ -196,6 +233,10 @@ type Documentation struct {
 type Kyc struct {
 	Documentations []Documentation `json:"documentations" bson:"documentations"`
 	ProfileType     string           `json:"profile_type" bson:"profile_type" validate:"required,eq=user|eq=company"`
+	// IsVerified and VerifiedDocumentCount - the KYC status, recomputed when a
+	// documentation is verified.
+	IsVerified            bool `json:"is_verified" bson:"is_verified"`
+	VerifiedDocumentCount int  `json:"verified_document_count" bson:"verified_document_count"`
 }
 type Location struct {
 	Longitude float64 `json:"longitude" bson:"longitude" validate:"required"`
@@ -206,6 +247,10 @@ type TierRequest struct {
 	UserReference    string `json:"user_reference" bson:"user_reference" validate:"required,uuid4"`
 	CurrentTier      Tier   `json:"current_tier" bson:"current_tier" validate:"required,dive"`
 	RequestedTier    Tier   `json:"requested_tier" bson:"requested_tier" validate:"required,dive"`
+	RequestStatus    string `json:"request_status" bson:"request_status"`
+	Reason           string `json:"reason" bson:"reason"`
+	RequestedOn      string `json:"requested_on" bson:"requested_on"`
+	DecidedOn        string `json:"decided_on" bson:"decided_on"`
 }
 type Sender struct {
 	Type          string `json:"type" bson:"type" validate:"required,eq=wallet|eq=bank_account|eq=card"`
@@ -9,5 +9,14 @@ var AcceptedChannels = map[string]string{
 	"create_user":    "create_user",
 	"create_company": "create_company",
 	"verify_email":   "verify_email",
+	"bind_device":    "bind_device",
+
+	"identificationverifiedevent": "identificationverifiedevent",
+	"tierupgradeapprovedevent":    "tierupgradeapprovedevent",
+	"bankverifiedevent":           "bankverifiedevent",
+	"cardverifiedevent":           "cardverifiedevent",
+	"photoverifiedevent":          "photoverifiedevent",
+	"balanceupdaterequestevent":   "balanceupdaterequestevent",
+	"tierconfigupdatedevent":      "tierconfigupdatedevent",
 	// "verify_phone": "verify_phone",
 }
@@ -0,0 +1,10 @@
+package event
+
+type BalanceUpdateRequestEventData struct {
+	UserReference         string  `json:"user_reference" bson:"user_reference" validate:"required"`
+	WalletReference       string  `json:"wallet_reference" bson:"wallet_reference"`
+	AvailableAmount       float64 `json:"available_amount" bson:"available_amount"`
+	PendingIncomingAmount float64 `json:"pending_incoming_amount" bson:"pending_incoming_amount"`
+	Currency              string  `json:"currency" bson:"currency"`
+	LedgerSequence        int64   `json:"ledger_sequence" bson:"ledger_sequence" validate:"required,gt=0"`
+}
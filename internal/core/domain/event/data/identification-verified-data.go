@@ -0,0 +1,9 @@
+package event
+
+type IdentificationVerifiedEventData struct {
+	UserReference          string `json:"user_reference" bson:"user_reference" validate:"required"`
+	DocumentationReference string `json:"documentation_reference" bson:"documentation_reference" validate:"required"`
+	IsVerified             bool   `json:"is_verified" bson:"is_verified"`
+	VerifiedOn             string `json:"verified_on" bson:"verified_on"`
+	VerificationMethod     string `json:"verification_method" bson:"verification_method"`
+}
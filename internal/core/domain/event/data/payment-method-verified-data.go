@@ -0,0 +1,21 @@
+package event
+
+type BankVerifiedEventData struct {
+	UserReference        string `json:"user_reference" bson:"user_reference" validate:"required"`
+	BankReference        string `json:"bank_reference" bson:"bank_reference" validate:"required"`
+	IntegrationType      string `json:"integration_type" bson:"integration_type"`
+	IntegrationReference string `json:"integration_reference" bson:"integration_reference"`
+	Status               string `json:"status" bson:"status" validate:"required,eq=pending|eq=verified|eq=failed"`
+	StatusReason         string `json:"status_reason" bson:"status_reason"`
+	VerifiedOn           string `json:"verified_on" bson:"verified_on"`
+}
+
+type CardVerifiedEventData struct {
+	UserReference        string `json:"user_reference" bson:"user_reference" validate:"required"`
+	CardReference        string `json:"card_reference" bson:"card_reference" validate:"required"`
+	IntegrationType      string `json:"integration_type" bson:"integration_type"`
+	IntegrationReference string `json:"integration_reference" bson:"integration_reference"`
+	Status               string `json:"status" bson:"status" validate:"required,eq=pending|eq=verified|eq=failed"`
+	StatusReason         string `json:"status_reason" bson:"status_reason"`
+	VerifiedOn           string `json:"verified_on" bson:"verified_on"`
+}
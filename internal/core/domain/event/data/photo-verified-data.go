@@ -0,0 +1,9 @@
+package event
+
+type PhotoVerifiedEventData struct {
+	UserReference   string `json:"user_reference" bson:"user_reference" validate:"required"`
+	PhotoReference  string `json:"photo_reference" bson:"photo_reference" validate:"required"`
+	Status          string `json:"status" bson:"status" validate:"required,eq=verified|eq=rejected"`
+	RejectionReason string `json:"rejection_reason" bson:"rejection_reason"`
+	VerifiedOn      string `json:"verified_on" bson:"verified_on"`
+}
@@ -0,0 +1,11 @@
+package event
+
+import (
+	"walls-user-service/internal/core/domain/entity"
+)
+
+type TierConfigUpdatedEventData struct {
+	Tier      entity.Tier `json:"tier" bson:"tier" validate:"required"`
+	IsActive  bool        `json:"is_active" bson:"is_active"`
+	UpdatedOn string      `json:"updated_on" bson:"updated_on"`
+}
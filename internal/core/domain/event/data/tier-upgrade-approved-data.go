@@ -0,0 +1,14 @@
+package event
+
+import (
+	"walls-user-service/internal/core/domain/entity"
+)
+
+type TierUpgradeApprovedEventData struct {
+	UserReference    string      `json:"user_reference" bson:"user_reference" validate:"required"`
+	RequestReference string      `json:"request_reference" bson:"request_reference" validate:"required"`
+	RequestStatus    string      `json:"request_status" bson:"request_status" validate:"required,eq=approved|eq=rejected"`
+	ApprovedTier     entity.Tier `json:"approved_tier" bson:"approved_tier"`
+	Reason           string      `json:"reason" bson:"reason"`
+	DecidedOn        string      `json:"decided_on" bson:"decided_on"`
+}
@@ -0,0 +1,396 @@
+package event
+
+import (
+	"walls-user-service/internal/core/domain/dto"
+	"walls-user-service/internal/core/domain/entity"
+)
+
+// SchemaVersion - the revision of the payloads below, published as
+// schema_version and pinned by the JSON Schemas under docs/events. Bump it with
+// any change to a payload; breaking changes get a new channel version instead.
+var SchemaVersion = 1
+
+// USER
+//------------------------------------------------------------------------------
+
+type UserCreatedEventData struct {
+	SchemaVersion int           `json:"schema_version"`
+	UserReference string        `json:"user_reference"`
+	Phone         string        `json:"phone"`
+	Device        entity.Device `json:"device"`
+}
+
+type DeviceRevokedEventData struct {
+	SchemaVersion   int    `json:"schema_version"`
+	UserReference   string `json:"user_reference"`
+	DeviceReference string `json:"device_reference"`
+	RevokedOn       string `json:"revoked_on"`
+}
+
+type UserUpdatedEventData struct {
+	SchemaVersion int    `json:"schema_version"`
+	UserReference string `json:"user_reference"`
+	UpdatedOn     string `json:"updated_on"`
+}
+
+type UserEnabledEventData struct {
+	SchemaVersion int    `json:"schema_version"`
+	UserReference string `json:"user_reference"`
+	IsActive      bool   `json:"is_active"`
+}
+
+type UserDisabledEventData struct {
+	SchemaVersion int    `json:"schema_version"`
+	UserReference string `json:"user_reference"`
+	IsActive      bool   `json:"is_active"`
+}
+
+// USER PROFILE
+//------------------------------------------------------------------------------
+
+type UsernameUpdatedEventData struct {
+	SchemaVersion int           `json:"schema_version"`
+	UserReference string        `json:"userReference"`
+	FirstName     string        `json:"first_name"`
+	LastName      string        `json:"last_name"`
+	Contact       string        `json:"contact"`
+	Channel       string        `json:"channel"`
+	Device        entity.Device `json:"device"`
+}
+
+type EmailUpdatedEventData struct {
+	SchemaVersion   int    `json:"schema_version"`
+	UserReference   string `json:"user_reference"`
+	Email           string `json:"email"`
+	IsVerifiedEmail bool   `json:"is_verified_email"`
+}
+
+type DOBUpdatedEventData struct {
+	SchemaVersion int    `json:"schema_version"`
+	UserReference string `json:"user_reference"`
+	DateOfBirth   string `json:"date_of_birth"`
+}
+
+type AddressUpdatedEventData struct {
+	SchemaVersion int            `json:"schema_version"`
+	UserReference string         `json:"user_reference"`
+	Address       entity.Address `json:"address"`
+}
+
+type PhotosUpdatedEventData struct {
+	SchemaVersion  int    `json:"schema_version"`
+	UserReference  string `json:"user_reference"`
+	PhotoReference string `json:"photo_reference"`
+	IsDefault      bool   `json:"is_default"`
+	IsVerified     bool   `json:"is_verified"`
+}
+
+type PhotoStatusUpdatedEventData struct {
+	SchemaVersion         int    `json:"schema_version"`
+	UserReference         string `json:"user_reference"`
+	PhotoReference        string `json:"photo_reference"`
+	Status                string `json:"status"`
+	RejectionReason       string `json:"rejection_reason"`
+	DefaultPhotoReference string `json:"default_photo_reference"`
+}
+
+type UserProfileEmailStatusUpdatedEventData struct {
+	SchemaVersion int           `json:"schema_version"`
+	UserReference string        `json:"user_reference"`
+	Contact       string        `json:"contact"`
+	Channel       string        `json:"channel"`
+	Device        entity.Device `json:"device"`
+}
+
+type UserProfilePhoneStatusUpdatedEventData struct {
+	SchemaVersion   int    `json:"schema_version"`
+	UserReference   string `json:"user_reference"`
+	IsVerifiedPhone bool   `json:"is_verified_phone"`
+}
+
+type UserWallsBadgeCreatedEventData struct {
+	SchemaVersion       int    `json:"schema_version"`
+	UserReference       string `json:"user_reference"`
+	WallsBadgeReference string `json:"walls_badge_reference"`
+	WallsTag            string `json:"walls_tag"`
+	DeviceReference     string `json:"device_reference"`
+	Contact             string `json:"contact"`
+	Channel             string `json:"channel"`
+	Message             string `json:"message"`
+}
+
+type UserWallsBadgeDisabledEventData struct {
+	SchemaVersion       int    `json:"schema_version"`
+	UserReference       string `json:"user_reference"`
+	WallsBadgeReference string `json:"walls_badge_reference"`
+}
+
+// COMPANY PROFILE
+//------------------------------------------------------------------------------
+
+type CompanyProfileCreatedEventData struct {
+	SchemaVersion           int    `json:"schema_version"`
+	UserReference           string `json:"user_reference"`
+	CompanyProfileReference string `json:"company_profile_reference"`
+	CompanyName             string `json:"company_name"`
+	Email                   string `json:"email"`
+}
+
+type CompanyProfileUpdatedEventData struct {
+	SchemaVersion           int            `json:"schema_version"`
+	UserReference           string         `json:"user_reference"`
+	CompanyProfileReference string         `json:"company_profile_reference"`
+	Email                   string         `json:"email"`
+	Phone                   string         `json:"phone"`
+	Address                 entity.Address `json:"address"`
+}
+
+type CompanyProfileDisabledEventData struct {
+	SchemaVersion           int    `json:"schema_version"`
+	UserReference           string `json:"user_reference"`
+	CompanyProfileReference string `json:"company_profile_reference"`
+	IsActive                bool   `json:"is_active"`
+}
+
+type CompanyLogoUpdatedEventData struct {
+	SchemaVersion           int          `json:"schema_version"`
+	UserReference           string       `json:"user_reference"`
+	CompanyProfileReference string       `json:"company_profile_reference"`
+	Logo                    entity.Photo `json:"logo"`
+}
+
+type CompanyProfileEmailStatusUpdatedEventData struct {
+	SchemaVersion           int    `json:"schema_version"`
+	UserReference           string `json:"user_reference"`
+	CompanyProfileReference string `json:"company_profile_reference"`
+	IsVerifiedEmail         bool   `json:"is_verified_email"`
+}
+
+type CompanyWallsBadgeCreatedEventData struct {
+	SchemaVersion           int    `json:"schema_version"`
+	UserReference           string `json:"user_reference"`
+	CompanyProfileReference string `json:"company_profile_reference"`
+	WallsBadgeReference     string `json:"walls_badge_reference"`
+	WallsTag                string `json:"walls_tag"`
+}
+
+type CompanyWallsBadgeDisabledEventData struct {
+	SchemaVersion           int    `json:"schema_version"`
+	UserReference           string `json:"user_reference"`
+	CompanyProfileReference string `json:"company_profile_reference"`
+	WallsBadgeReference     string `json:"walls_badge_reference"`
+}
+
+// KYC
+//------------------------------------------------------------------------------
+
+type KycStatusUpdatedEventData struct {
+	SchemaVersion         int    `json:"schema_version"`
+	UserReference         string `json:"user_reference"`
+	ProfileType           string `json:"profile_type"`
+	IsVerified            bool   `json:"is_verified"`
+	VerifiedDocumentCount int    `json:"verified_document_count"`
+}
+
+type KycScoreUpdatedEventData struct {
+	SchemaVersion int    `json:"schema_version"`
+	UserReference string `json:"user_reference"`
+	KycScore      int    `json:"kyc_score"`
+}
+
+type DocumentationAddedEventData struct {
+	SchemaVersion          int    `json:"schema_version"`
+	UserReference          string `json:"user_reference"`
+	DocumentationReference string `json:"documentation_reference"`
+	DocumentationType      string `json:"documentation_type"`
+	TierReference          string `json:"tier_reference"`
+}
+
+type DocumentationUpdatedEventData struct {
+	SchemaVersion          int    `json:"schema_version"`
+	UserReference          string `json:"user_reference"`
+	DocumentationReference string `json:"documentation_reference"`
+	DocumentationType      string `json:"documentation_type"`
+	TierReference          string `json:"tier_reference"`
+	IsVerified             bool   `json:"is_verified"`
+}
+
+// WALLET & PAYMENT METHODS
+//------------------------------------------------------------------------------
+
+type WalletUpdatedEventData struct {
+	SchemaVersion       int     `json:"schema_version"`
+	UserReference       string  `json:"user_reference"`
+	WalletReference     string  `json:"wallet_reference"`
+	AutoFund            bool    `json:"auto_fund"`
+	AutoFundLevel       float64 `json:"auto_fund_level"`
+	AutoFundLimit       float64 `json:"auto_fund_limit"`
+	AutoWithdrawal      bool    `json:"auto_withdrawal"`
+	AutoWithdrawalLevel float64 `json:"auto_withdrawal_level"`
+	AutoWithdrawalLimit float64 `json:"auto_withdrawal_limit"`
+}
+
+type BookBalanceUpdatedEventData struct {
+	SchemaVersion         int     `json:"schema_version"`
+	UserReference         string  `json:"user_reference"`
+	WalletReference       string  `json:"wallet_reference"`
+	PendingIncomingAmount float64 `json:"pending_incoming_amount"`
+	Currency              string  `json:"currency"`
+}
+
+type BalanceUpdatedEventData struct {
+	SchemaVersion         int     `json:"schema_version"`
+	UserReference         string  `json:"user_reference"`
+	WalletReference       string  `json:"wallet_reference"`
+	AvailableAmount       float64 `json:"available_amount"`
+	PendingIncomingAmount float64 `json:"pending_incoming_amount"`
+	Currency              string  `json:"currency"`
+	LastSyncedOn          string  `json:"last_synced_on"`
+	LedgerSequence        int64   `json:"ledger_sequence"`
+}
+
+type BookTransferredtoAvailableEventData struct {
+	SchemaVersion   int     `json:"schema_version"`
+	UserReference   string  `json:"user_reference"`
+	WalletReference string  `json:"wallet_reference"`
+	Amount          float64 `json:"amount"`
+	Currency        string  `json:"currency"`
+}
+
+type TierUpdatedEventData struct {
+	SchemaVersion   int         `json:"schema_version"`
+	UserReference   string      `json:"user_reference"`
+	WalletReference string      `json:"wallet_reference"`
+	Tier            entity.Tier `json:"tier"`
+}
+
+type CouponAddedEventData struct {
+	SchemaVersion   int    `json:"schema_version"`
+	UserReference   string `json:"user_reference"`
+	CouponReference string `json:"coupon_reference"`
+	ExpiryDate      string `json:"expiry_date"`
+}
+
+type RewardsUpdatedEventData struct {
+	SchemaVersion int    `json:"schema_version"`
+	UserReference string `json:"user_reference"`
+	Points        int    `json:"points"`
+}
+
+type BankAddedEventData struct {
+	SchemaVersion int    `json:"schema_version"`
+	UserReference string `json:"user_reference"`
+	BankReference string `json:"bank_reference"`
+	BankName      string `json:"bank_name"`
+	IsDefault     bool   `json:"is_default"`
+	Status        string `json:"status"`
+}
+
+type BankUpdatedEventData struct {
+	SchemaVersion int    `json:"schema_version"`
+	UserReference string `json:"user_reference"`
+	BankReference string `json:"bank_reference"`
+	BankName      string `json:"bank_name"`
+	IsDefault     bool   `json:"is_default"`
+	Status        string `json:"status"`
+}
+
+type DefaultBankSetEventData struct {
+	SchemaVersion int    `json:"schema_version"`
+	UserReference string `json:"user_reference"`
+	BankReference string `json:"bank_reference"`
+}
+
+type CardAddedEventData struct {
+	SchemaVersion int    `json:"schema_version"`
+	UserReference string `json:"user_reference"`
+	CardReference string `json:"card_reference"`
+	CardName      string `json:"card_name"`
+	IsDefault     bool   `json:"is_default"`
+	Status        string `json:"status"`
+}
+
+type CardUpdatedEventData struct {
+	SchemaVersion int    `json:"schema_version"`
+	UserReference string `json:"user_reference"`
+	CardReference string `json:"card_reference"`
+	CardName      string `json:"card_name"`
+	IsDefault     bool   `json:"is_default"`
+	Status        string `json:"status"`
+}
+
+type DefaultCardSetEventData struct {
+	SchemaVersion int    `json:"schema_version"`
+	UserReference string `json:"user_reference"`
+	CardReference string `json:"card_reference"`
+}
+
+// DEVICE, NOTIFICATIONS & CONTACTS
+//------------------------------------------------------------------------------
+
+type DeviceUpdatedEventData struct {
+	SchemaVersion int           `json:"schema_version"`
+	UserReference string        `json:"user_reference"`
+	Device        entity.Device `json:"device"`
+}
+
+type NotificationOptionsUpdatedEventData struct {
+	SchemaVersion       int                        `json:"schema_version"`
+	UserReference       string                     `json:"user_reference"`
+	NotificationOptions entity.NotificationOptions `json:"notification_options"`
+}
+
+type ContactAddedEventData struct {
+	SchemaVersion    int    `json:"schema_version"`
+	UserReference    string `json:"user_reference"`
+	ContactReference string `json:"contact_reference"`
+	WallsTag         string `json:"walls_tag"`
+	IsBeneficiary    bool   `json:"is_beneficiary"`
+}
+
+// REQUESTS
+//------------------------------------------------------------------------------
+
+type OtpRequestCreatedEventData struct {
+	SchemaVersion int           `json:"schema_version"`
+	UserReference string        `json:"userReference"`
+	Contact       string        `json:"contact"`
+	Channel       string        `json:"channel"`
+	Device        dto.DeviceDto `json:"device"`
+}
+
+type ValidateOtpRequestEventData struct {
+	SchemaVersion int           `json:"schema_version"`
+	UserReference string        `json:"userReference"`
+	Contact       string        `json:"contact"`
+	Otp           string        `json:"otp"`
+	Device        dto.DeviceDto `json:"device"`
+}
+
+type CreateIdentityRequestEventData struct {
+	SchemaVersion int           `json:"schema_version"`
+	UserReference string        `json:"userReference"`
+	Phone         string        `json:"phone"`
+	Device        dto.DeviceDto `json:"device"`
+}
+
+type TierUpgradeRequestEventData struct {
+	SchemaVersion    int                    `json:"schema_version"`
+	RequestReference string                 `json:"request_reference"`
+	UserReference    string                 `json:"user_reference"`
+	CurrentTier      entity.Tier            `json:"current_tier"`
+	RequestedTier    dto.TierDto            `json:"requested_tier"`
+	KycDocuments     []entity.Documentation `json:"kyc_documents"`
+	TierDocuments    []dto.DocumentationDto `json:"tier_documents"`
+	RequestStatus    string                 `json:"request_status"`
+}
+
+type TransactionCreateRequestData struct {
+	SchemaVersion    int             `json:"schema_version"`
+	RequestReference string          `json:"request_reference"`
+	TransactionType  string          `json:"transaction_type"`
+	Amount           float64         `json:"amount"`
+	Sender           entity.Sender   `json:"sender"`
+	Receiver         entity.Receiver `json:"receiver"`
+	Metadata         entity.Metadata `json:"metadata"`
+}
@@ -0,0 +1,183 @@
+package event
+
+import (
+	"embed"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"reflect"
+	"regexp"
+	"strings"
+	events "walls-user-service/internal/core/domain/event/data"
+	"walls-user-service/internal/core/helper/event-helper/eto"
+)
+
+// Registration - the wire contract of an event type: the channel it is
+// published on and the payload it carries. Channels are spelled out and
+// versioned here, so renaming a Go type never changes what consumers see.
+type Registration struct {
+	Event   interface{}
+	Channel string
+	Payload interface{}
+}
+
+var registrations = []Registration{
+	{Event: UserCreatedEvent{}, Channel: "USERCREATEDEVENT.V1", Payload: events.UserCreatedEventData{}},
+	{Event: UserUpdatedEvent{}, Channel: "USERUPDATEDEVENT.V1", Payload: events.UserUpdatedEventData{}},
+	{Event: UserWallsBadgeCreatedEvent{}, Channel: "USERWALLSBADGECREATEDEVENT.V1", Payload: events.UserWallsBadgeCreatedEventData{}},
+	{Event: CompanyWallsBadgeCreatedEvent{}, Channel: "COMPANYWALLSBADGECREATEDEVENT.V1", Payload: events.CompanyWallsBadgeCreatedEventData{}},
+	{Event: CompanyProfileCreatedEvent{}, Channel: "COMPANYPROFILECREATEDEVENT.V1", Payload: events.CompanyProfileCreatedEventData{}},
+	{Event: CompanyProfileUpdatedEvent{}, Channel: "COMPANYPROFILEUPDATEDEVENT.V1", Payload: events.CompanyProfileUpdatedEventData{}},
+	{Event: CompanyWallsBadgeDisabledEvent{}, Channel: "COMPANYWALLSBADGEDISABLEDEVENT.V1", Payload: events.CompanyWallsBadgeDisabledEventData{}},
+	{Event: UserWallsBadgeDisabledEvent{}, Channel: "USERWALLSBADGEDISABLEDEVENT.V1", Payload: events.UserWallsBadgeDisabledEventData{}},
+	{Event: CompanyProfileDisabledEvent{}, Channel: "COMPANYPROFILEDISABLEDEVENT.V1", Payload: events.CompanyProfileDisabledEventData{}},
+	{Event: CompanyLogoUpdatedEvent{}, Channel: "COMPANYLOGOUPDATEDEVENT.V1", Payload: events.CompanyLogoUpdatedEventData{}},
+	{Event: UserProfileEmailStatusUpdatedEvent{}, Channel: "USERPROFILEEMAILSTATUSUPDATEDEVENT.V1", Payload: events.UserProfileEmailStatusUpdatedEventData{}},
+	{Event: UserProfilePhoneStatusUpdatedEvent{}, Channel: "USERPROFILEPHONESTATUSUPDATEDEVENT.V1", Payload: events.UserProfilePhoneStatusUpdatedEventData{}},
+	{Event: CompanyProfileEmailStatusUpdatedEvent{}, Channel: "COMPANYPROFILEEMAILSTATUSUPDATEDEVENT.V1", Payload: events.CompanyProfileEmailStatusUpdatedEventData{}},
+	{Event: UsernameUpdatedEvent{}, Channel: "USERNAMEUPDATEDEVENT.V1", Payload: events.UsernameUpdatedEventData{}},
+	{Event: KycStatusUpdatedEvent{}, Channel: "KYCSTATUSUPDATEDEVENT.V1", Payload: events.KycStatusUpdatedEventData{}},
+	{Event: DefaultBankSetEvent{}, Channel: "DEFAULTBANKSETEVENT.V1", Payload: events.DefaultBankSetEventData{}},
+	{Event: DefaultCardSetEvent{}, Channel: "DEFAULTCARDSETEVENT.V1", Payload: events.DefaultCardSetEventData{}},
+	{Event: EmailUpdatedEvent{}, Channel: "EMAILUPDATEDEVENT.V1", Payload: events.EmailUpdatedEventData{}},
+	{Event: DOBUpdatedEvent{}, Channel: "DOBUPDATEDEVENT.V1", Payload: events.DOBUpdatedEventData{}},
+	{Event: AddressUpdatedEvent{}, Channel: "ADDRESSUPDATEDEVENT.V1", Payload: events.AddressUpdatedEventData{}},
+	{Event: PhotosUpdatedEvent{}, Channel: "PHOTOSUPDATEDEVENT.V1", Payload: events.PhotosUpdatedEventData{}},
+	{Event: PhotoStatusUpdatedEvent{}, Channel: "PHOTOSTATUSUPDATEDEVENT.V1", Payload: events.PhotoStatusUpdatedEventData{}},
+	{Event: WalletUpdatedEvent{}, Channel: "WALLETUPDATEDEVENT.V1", Payload: events.WalletUpdatedEventData{}},
+	{Event: BankAddedEvent{}, Channel: "BANKADDEDEVENT.V1", Payload: events.BankAddedEventData{}},
+	{Event: BankUpdatedEvent{}, Channel: "BANKUPDATEDEVENT.V1", Payload: events.BankUpdatedEventData{}},
+	{Event: CardAddedEvent{}, Channel: "CARDADDEDEVENT.V1", Payload: events.CardAddedEventData{}},
+	{Event: CardUpdatedEvent{}, Channel: "CARDUPDATEDEVENT.V1", Payload: events.CardUpdatedEventData{}},
+	{Event: NotificationOptionsUpdatedEvent{}, Channel: "NOTIFICATIONOPTIONSUPDATEDEVENT.V1", Payload: events.NotificationOptionsUpdatedEventData{}},
+	{Event: DeviceUpdatedEvent{}, Channel: "DEVICEUPDATEDEVENT.V1", Payload: events.DeviceUpdatedEventData{}},
+	{Event: DeviceRevokedEvent{}, Channel: "DEVICEREVOKEDEVENT.V1", Payload: events.DeviceRevokedEventData{}},
+	{Event: DocumentationAddedEvent{}, Channel: "DOCUMENTATIONADDEDEVENT.V1", Payload: events.DocumentationAddedEventData{}},
+	{Event: DocumentationUpdatedEvent{}, Channel: "DOCUMENTATIONUPDATEDEVENT.V1", Payload: events.DocumentationUpdatedEventData{}},
+	{Event: ContactAddedEvent{}, Channel: "CONTACTADDEDEVENT.V1", Payload: events.ContactAddedEventData{}},
+	{Event: BookBalanceUpdatedEvent{}, Channel: "BOOKBALANCEUPDATEDEVENT.V1", Payload: events.BookBalanceUpdatedEventData{}},
+	{Event: BalanceUpdatedEvent{}, Channel: "BALANCEUPDATEDEVENT.V1", Payload: events.BalanceUpdatedEventData{}},
+	{Event: BookTransferredtoAvailableEvent{}, Channel: "BOOKTRANSFERREDTOAVAILABLEEVENT.V1", Payload: events.BookTransferredtoAvailableEventData{}},
+	{Event: TierUpdatedEvent{}, Channel: "TIERUPDATEDEVENT.V1", Payload: events.TierUpdatedEventData{}},
+	{Event: CouponAddedEvent{}, Channel: "COUPONADDEDEVENT.V1", Payload: events.CouponAddedEventData{}},
+	{Event: RewardsUpdatedEvent{}, Channel: "REWARDSUPDATEDEVENT.V1", Payload: events.RewardsUpdatedEventData{}},
+	{Event: KycScoreUpdatedEvent{}, Channel: "KYCSCOREUPDATEDEVENT.V1", Payload: events.KycScoreUpdatedEventData{}},
+	{Event: UserEnabledEvent{}, Channel: "USERENABLEDEVENT.V1", Payload: events.UserEnabledEventData{}},
+	{Event: UserDisabledEvent{}, Channel: "USERDISABLEDEVENT.V1", Payload: events.UserDisabledEventData{}},
+	{Event: OtpRequestCreatedEvent{}, Channel: "OTPREQUESTCREATEDEVENT.V1", Payload: events.OtpRequestCreatedEventData{}},
+	{Event: ValidateOtpRequestEvent{}, Channel: "VALIDATEOTPREQUESTEVENT.V1", Payload: events.ValidateOtpRequestEventData{}},
+	{Event: CreateIdentityRequestEvent{}, Channel: "CREATEIDENTITYREQUESTEVENT.V1", Payload: events.CreateIdentityRequestEventData{}},
+	{Event: TierUpgradeRequestEvent{}, Channel: "TIERUPGRADEREQUESTEVENT.V1", Payload: events.TierUpgradeRequestEventData{}},
+	{Event: TransactionCreateRequest{}, Channel: "TRANSACTIONCREATEREQUEST.V1", Payload: events.TransactionCreateRequestData{}},
+}
+
+var (
+	registry       = indexRegistrations()
+	versionedRegex = regexp.MustCompile(`^[A-Z]+\.V[0-9]+$`)
+)
+
+// The package's own source, parsed by ValidateRegistry to find every declared event type.
+//
+//go:embed *.go
+var sources embed.FS
+
+// ChannelFor - the registered channel of an event, suffixed with the event type
+// when one is given. Unregistered events, and events whose data is not the
+// registered payload, are refused.
+func ChannelFor(domainEvent interface{ Envelope() eto.Event }, eventType ...string) (string, error) {
+	registration, ok := registry[reflect.TypeOf(domainEvent)]
+	if !ok {
+		return "", fmt.Errorf("event type %T has no registration", domainEvent)
+	}
+
+	payload := domainEvent.Envelope().EventData
+	if payload != nil && reflect.TypeOf(payload) != reflect.TypeOf(registration.Payload) {
+		return "", fmt.Errorf("event type %T carries %T, registered payload is %T", domainEvent, payload, registration.Payload)
+	}
+
+	channel := registration.Channel
+	if len(eventType) != 0 {
+		channel = fmt.Sprintf("%s:%s", channel, strings.ToUpper(eventType[0]))
+	}
+	return channel, nil
+}
+
+// ValidateRegistry - checks every event type declared in this package has a
+// registration, and every registration has a unique, versioned channel.
+func ValidateRegistry() error {
+	declared, err := declaredEvents()
+	if err != nil {
+		return err
+	}
+
+	registered := map[string]bool{}
+	channels := map[string]string{}
+	for _, registration := range registrations {
+		name := reflect.TypeOf(registration.Event).Name()
+		registered[name] = true
+
+		if !versionedRegex.MatchString(registration.Channel) {
+			return fmt.Errorf("event type %s has unversioned channel %q", name, registration.Channel)
+		}
+		if other, ok := channels[registration.Channel]; ok {
+			return fmt.Errorf("event types %s and %s share channel %q", other, name, registration.Channel)
+		}
+		channels[registration.Channel] = name
+	}
+
+	for _, name := range declared {
+		if !registered[name] {
+			return fmt.Errorf("event type %s has no registration", name)
+		}
+	}
+	return nil
+}
+
+// declaredEvents - names of the struct types in this package embedding eto.Event.
+func declaredEvents() ([]string, error) {
+	entries, err := sources.ReadDir(".")
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	fileSet := token.NewFileSet()
+	for _, entry := range entries {
+		source, err := sources.ReadFile(entry.Name())
+		if err != nil {
+			return nil, err
+		}
+		file, err := parser.ParseFile(fileSet, entry.Name(), source, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		ast.Inspect(file, func(node ast.Node) bool {
+			typeSpec, ok := node.(*ast.TypeSpec)
+			if !ok {
+				return true
+			}
+			structType, ok := typeSpec.Type.(*ast.StructType)
+			if !ok {
+				return false
+			}
+			for _, field := range structType.Fields.List {
+				selector, ok := field.Type.(*ast.SelectorExpr)
+				if ok && len(field.Names) == 0 && selector.Sel.Name == "Event" {
+					names = append(names, typeSpec.Name.Name)
+				}
+			}
+			return false
+		})
+	}
+	return names, nil
+}
+
+func indexRegistrations() map[reflect.Type]Registration {
+	index := make(map[reflect.Type]Registration, len(registrations))
+	for _, registration := range registrations {
+		index[reflect.TypeOf(registration.Event)] = registration
+	}
+	return index
+}
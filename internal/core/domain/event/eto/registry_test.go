@@ -0,0 +1,215 @@
+package event
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	events "walls-user-service/internal/core/domain/event/data"
+)
+
+var schemaDir = filepath.Join("..", "..", "..", "..", "..", "docs", "events")
+
+func TestValidateRegistry(t *testing.T) {
+	if err := ValidateRegistry(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestEventSchemas - every registered event, published with an empty and with a
+// fully populated payload, must validate against its checked-in JSON Schema.
+func TestEventSchemas(t *testing.T) {
+	for _, registration := range registrations {
+		registration := registration
+		t.Run(registration.Channel, func(t *testing.T) {
+			schema := loadSchema(t, registration.Channel)
+
+			for name, payload := range map[string]reflect.Value{
+				"empty":     reflect.New(reflect.TypeOf(registration.Payload)).Elem(),
+				"populated": populated(reflect.TypeOf(registration.Payload)),
+			} {
+				payload.FieldByName("SchemaVersion").SetInt(int64(events.SchemaVersion))
+				if err := validate(schema, publish(t, registration.Event, payload.Interface()), "$"); err != nil {
+					t.Errorf("%s payload: %v", name, err)
+				}
+			}
+		})
+	}
+}
+
+func TestEventSchemasRejectOtherSchemaVersions(t *testing.T) {
+	registration := registrations[0]
+	payload := reflect.New(reflect.TypeOf(registration.Payload)).Elem()
+	payload.FieldByName("SchemaVersion").SetInt(int64(events.SchemaVersion + 1))
+
+	err := validate(loadSchema(t, registration.Channel), publish(t, registration.Event, payload.Interface()), "$")
+	if err == nil {
+		t.Fatal("expected a schema_version mismatch to fail validation")
+	}
+}
+
+func TestNoSchemaWithoutRegistration(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join(schemaDir, "*.schema.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	channels := map[string]bool{}
+	for _, registration := range registrations {
+		channels[schemaFile(registration.Channel)] = true
+	}
+	for _, file := range files {
+		if !channels[filepath.Base(file)] {
+			t.Errorf("schema %s has no registered event", filepath.Base(file))
+		}
+	}
+}
+
+func schemaFile(channel string) string {
+	return strings.ToLower(channel) + ".schema.json"
+}
+
+func loadSchema(t *testing.T, channel string) map[string]interface{} {
+	t.Helper()
+	source, err := os.ReadFile(filepath.Join(schemaDir, schemaFile(channel)))
+	if err != nil {
+		t.Fatalf("missing schema for %s: %v", channel, err)
+	}
+	var schema map[string]interface{}
+	if err := json.Unmarshal(source, &schema); err != nil {
+		t.Fatalf("invalid schema for %s: %v", channel, err)
+	}
+	return schema
+}
+
+// publish - the event as it goes on the wire, decoded back into plain JSON values.
+func publish(t *testing.T, domainEvent interface{}, payload interface{}) interface{} {
+	t.Helper()
+	value := reflect.New(reflect.TypeOf(domainEvent)).Elem()
+	value.Field(0).FieldByName("EventData").Set(reflect.ValueOf(payload))
+
+	encoded, err := json.Marshal(value.Interface())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded interface{}
+	if err := json.Unmarshal(encoded, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	return decoded
+}
+
+// populated - a value of the given type with every field, slice and pointer set.
+func populated(valueType reflect.Type) reflect.Value {
+	value := reflect.New(valueType).Elem()
+	switch valueType.Kind() {
+	case reflect.String:
+		value.SetString("x")
+	case reflect.Bool:
+		value.SetBool(true)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		value.SetInt(1)
+	case reflect.Float32, reflect.Float64:
+		value.SetFloat(1.5)
+	case reflect.Ptr:
+		value.Set(populated(valueType.Elem()).Addr())
+	case reflect.Slice:
+		value.Set(reflect.Append(value, populated(valueType.Elem())))
+	case reflect.Struct:
+		for i := 0; i < valueType.NumField(); i++ {
+			value.Field(i).Set(populated(valueType.Field(i).Type))
+		}
+	}
+	return value
+}
+
+// validate - checks a decoded JSON value against the subset of JSON Schema the
+// event schemas use: type, const, properties, required, additionalProperties
+// and items.
+func validate(schema map[string]interface{}, value interface{}, path string) error {
+	if expected, ok := schema["type"]; ok && !matchesType(expected, value) {
+		return fmt.Errorf("%s: expected type %v, got %T", path, expected, value)
+	}
+	if expected, ok := schema["const"]; ok && !reflect.DeepEqual(expected, value) {
+		return fmt.Errorf("%s: expected %v, got %v", path, expected, value)
+	}
+
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		properties, _ := schema["properties"].(map[string]interface{})
+		required, _ := schema["required"].([]interface{})
+		for _, name := range required {
+			if _, ok := typed[name.(string)]; !ok {
+				return fmt.Errorf("%s: missing required property %q", path, name)
+			}
+		}
+
+		names := make([]string, 0, len(typed))
+		for name := range typed {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			property, ok := properties[name].(map[string]interface{})
+			if !ok {
+				if schema["additionalProperties"] == false {
+					return fmt.Errorf("%s: unexpected property %q", path, name)
+				}
+				continue
+			}
+			if err := validate(property, typed[name], path+"."+name); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		items, ok := schema["items"].(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		for i, item := range typed {
+			if err := validate(items, item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func matchesType(expected interface{}, value interface{}) bool {
+	if types, ok := expected.([]interface{}); ok {
+		for _, candidate := range types {
+			if matchesType(candidate, value) {
+				return true
+			}
+		}
+		return false
+	}
+
+	switch expected {
+	case "object":
+		_, ok := value.(map[string]interface{})
+		return ok
+	case "array":
+		_, ok := value.([]interface{})
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	case "integer":
+		number, ok := value.(float64)
+		return ok && number == float64(int64(number))
+	case "null":
+		return value == nil
+	}
+	return false
+}
@@ -83,6 +83,10 @@ type PhotosUpdatedEvent struct {
 	eto.Event
 }
 
+type PhotoStatusUpdatedEvent struct {
+	eto.Event
+}
+
 type WalletUpdatedEvent struct {
 	eto.Event
 }
@@ -111,6 +115,10 @@ type DeviceUpdatedEvent struct {
 	eto.Event
 }
 
+type DeviceRevokedEvent struct {
+	eto.Event
+}
+
 type DocumentationAddedEvent struct {
 	eto.Event
 }
@@ -0,0 +1,413 @@
+package mapper
+
+import (
+	"walls-user-service/internal/core/domain/entity"
+	events "walls-user-service/internal/core/domain/event/data"
+)
+
+// Event payloads carry only what changed and the references a consumer needs
+// to act on it; the user document itself is never published.
+
+func UserToUserEnabledEventData(user entity.User) events.UserEnabledEventData {
+	return events.UserEnabledEventData{
+		SchemaVersion: events.SchemaVersion,
+		UserReference: user.UserReference,
+		IsActive:      user.IsActive,
+	}
+}
+
+func UserToUserDisabledEventData(user entity.User) events.UserDisabledEventData {
+	return events.UserDisabledEventData{
+		SchemaVersion: events.SchemaVersion,
+		UserReference: user.UserReference,
+		IsActive:      user.IsActive,
+	}
+}
+
+func UserToDOBUpdatedEventData(user entity.User) events.DOBUpdatedEventData {
+	return events.DOBUpdatedEventData{
+		SchemaVersion: events.SchemaVersion,
+		UserReference: user.UserReference,
+		DateOfBirth:   user.UserProfile.DateOfBirth,
+	}
+}
+
+func UserToAddressUpdatedEventData(user entity.User) events.AddressUpdatedEventData {
+	return events.AddressUpdatedEventData{
+		SchemaVersion: events.SchemaVersion,
+		UserReference: user.UserReference,
+		Address:       user.UserProfile.Address,
+	}
+}
+
+func UserToPhotosUpdatedEventData(user entity.User, photoReference string) events.PhotosUpdatedEventData {
+	data := events.PhotosUpdatedEventData{
+		SchemaVersion:  events.SchemaVersion,
+		UserReference:  user.UserReference,
+		PhotoReference: photoReference,
+	}
+	for _, photo := range user.UserProfile.Photos {
+		if photo.PhotoReference == photoReference {
+			data.IsDefault = photo.IsDefault
+			data.IsVerified = photo.IsVerified
+			break
+		}
+	}
+	return data
+}
+
+// UserToPhotoStatusUpdatedEventData - the moderation outcome of one of the
+// user's photos, along with whichever photo is now their default.
+func UserToPhotoStatusUpdatedEventData(user entity.User, photoReference string) events.PhotoStatusUpdatedEventData {
+	data := events.PhotoStatusUpdatedEventData{
+		SchemaVersion:  events.SchemaVersion,
+		UserReference:  user.UserReference,
+		PhotoReference: photoReference,
+	}
+	for _, photo := range user.UserProfile.Photos {
+		if photo.PhotoReference == photoReference {
+			data.Status = photo.Status
+			data.RejectionReason = photo.RejectionReason
+		}
+		if photo.IsDefault {
+			data.DefaultPhotoReference = photo.PhotoReference
+		}
+	}
+	return data
+}
+
+func UserToUserWallsBadgeDisabledEventData(user entity.User, wallsBadgeReference string) events.UserWallsBadgeDisabledEventData {
+	return events.UserWallsBadgeDisabledEventData{
+		SchemaVersion:       events.SchemaVersion,
+		UserReference:       user.UserReference,
+		WallsBadgeReference: wallsBadgeReference,
+	}
+}
+
+// UserToCompanyProfileCreatedEventData - describes the company profile most
+// recently added to the user.
+func UserToCompanyProfileCreatedEventData(user entity.User) events.CompanyProfileCreatedEventData {
+	data := events.CompanyProfileCreatedEventData{
+		SchemaVersion: events.SchemaVersion,
+		UserReference: user.UserReference,
+	}
+	if len(user.CompanyProfile) != 0 {
+		companyProfile := user.CompanyProfile[len(user.CompanyProfile)-1]
+		data.CompanyProfileReference = companyProfile.CompanyProfileReference
+		data.CompanyName = companyProfile.CompanyName
+		data.Email = companyProfile.Email
+	}
+	return data
+}
+
+func UserToCompanyProfileUpdatedEventData(user entity.User, companyProfileReference string) events.CompanyProfileUpdatedEventData {
+	data := events.CompanyProfileUpdatedEventData{
+		SchemaVersion:           events.SchemaVersion,
+		UserReference:           user.UserReference,
+		CompanyProfileReference: companyProfileReference,
+	}
+	if companyProfile, ok := findCompanyProfile(user, companyProfileReference); ok {
+		data.Email = companyProfile.Email
+		data.Phone = companyProfile.Phone
+		data.Address = companyProfile.Address
+	}
+	return data
+}
+
+func UserToCompanyProfileDisabledEventData(user entity.User, companyProfileReference string) events.CompanyProfileDisabledEventData {
+	data := events.CompanyProfileDisabledEventData{
+		SchemaVersion:           events.SchemaVersion,
+		UserReference:           user.UserReference,
+		CompanyProfileReference: companyProfileReference,
+	}
+	if companyProfile, ok := findCompanyProfile(user, companyProfileReference); ok {
+		data.IsActive = companyProfile.IsActive
+	}
+	return data
+}
+
+func UserToCompanyLogoUpdatedEventData(user entity.User, companyProfileReference string) events.CompanyLogoUpdatedEventData {
+	data := events.CompanyLogoUpdatedEventData{
+		SchemaVersion:           events.SchemaVersion,
+		UserReference:           user.UserReference,
+		CompanyProfileReference: companyProfileReference,
+	}
+	if companyProfile, ok := findCompanyProfile(user, companyProfileReference); ok {
+		data.Logo = companyProfile.Logo
+	}
+	return data
+}
+
+func UserToCompanyProfileEmailStatusUpdatedEventData(user entity.User, companyProfileReference string) events.CompanyProfileEmailStatusUpdatedEventData {
+	data := events.CompanyProfileEmailStatusUpdatedEventData{
+		SchemaVersion:           events.SchemaVersion,
+		UserReference:           user.UserReference,
+		CompanyProfileReference: companyProfileReference,
+	}
+	if companyProfile, ok := findCompanyProfile(user, companyProfileReference); ok {
+		data.IsVerifiedEmail = companyProfile.IsVerifiedEmail
+	}
+	return data
+}
+
+// UserToCompanyWallsBadgeCreatedEventData - describes the walls badge most
+// recently added to the given company profile.
+func UserToCompanyWallsBadgeCreatedEventData(user entity.User, companyProfileReference string) events.CompanyWallsBadgeCreatedEventData {
+	data := events.CompanyWallsBadgeCreatedEventData{
+		SchemaVersion:           events.SchemaVersion,
+		UserReference:           user.UserReference,
+		CompanyProfileReference: companyProfileReference,
+	}
+	if companyProfile, ok := findCompanyProfile(user, companyProfileReference); ok && len(companyProfile.WallsBadge) != 0 {
+		wallsBadge := companyProfile.WallsBadge[len(companyProfile.WallsBadge)-1]
+		data.WallsBadgeReference = wallsBadge.WallsBadgeReference
+		data.WallsTag = wallsBadge.WallsTag
+	}
+	return data
+}
+
+func UserToCompanyWallsBadgeDisabledEventData(user entity.User, companyProfileReference string, wallsBadgeReference string) events.CompanyWallsBadgeDisabledEventData {
+	return events.CompanyWallsBadgeDisabledEventData{
+		SchemaVersion:           events.SchemaVersion,
+		UserReference:           user.UserReference,
+		CompanyProfileReference: companyProfileReference,
+		WallsBadgeReference:     wallsBadgeReference,
+	}
+}
+
+// UserToKycStatusUpdatedEventData - the user's KYC status as stored.
+func UserToKycStatusUpdatedEventData(user entity.User) events.KycStatusUpdatedEventData {
+	return events.KycStatusUpdatedEventData{
+		SchemaVersion:         events.SchemaVersion,
+		UserReference:         user.UserReference,
+		ProfileType:           user.Kyc.ProfileType,
+		IsVerified:            user.Kyc.IsVerified,
+		VerifiedDocumentCount: user.Kyc.VerifiedDocumentCount,
+	}
+}
+
+// UserToDocumentationAddedEventData - describes the documentation most
+// recently added to the user.
+func UserToDocumentationAddedEventData(user entity.User) events.DocumentationAddedEventData {
+	data := events.DocumentationAddedEventData{
+		SchemaVersion: events.SchemaVersion,
+		UserReference: user.UserReference,
+	}
+	if len(user.Kyc.Documentations) != 0 {
+		documentation := user.Kyc.Documentations[len(user.Kyc.Documentations)-1]
+		data.DocumentationReference = documentation.DocumentationReference
+		data.DocumentationType = documentation.DocumentationType
+		data.TierReference = documentation.TierReference
+	}
+	return data
+}
+
+func UserToDocumentationUpdatedEventData(user entity.User, documentationReference string) events.DocumentationUpdatedEventData {
+	data := events.DocumentationUpdatedEventData{
+		SchemaVersion:          events.SchemaVersion,
+		UserReference:          user.UserReference,
+		DocumentationReference: documentationReference,
+	}
+	for _, documentation := range user.Kyc.Documentations {
+		if documentation.DocumentationReference == documentationReference {
+			data.DocumentationType = documentation.DocumentationType
+			data.TierReference = documentation.TierReference
+			data.IsVerified = documentation.IsVerified
+			break
+		}
+	}
+	return data
+}
+
+func UserToWalletUpdatedEventData(user entity.User) events.WalletUpdatedEventData {
+	return events.WalletUpdatedEventData{
+		SchemaVersion:       events.SchemaVersion,
+		UserReference:       user.UserReference,
+		WalletReference:     user.Wallet.WalletReference,
+		AutoFund:            user.Wallet.AutoFund,
+		AutoFundLevel:       user.Wallet.AutoFundLevel,
+		AutoFundLimit:       user.Wallet.AutoFundLimit,
+		AutoWithdrawal:      user.Wallet.AutoWithdrawal,
+		AutoWithdrawalLevel: user.Wallet.AutoWithdrawalLevel,
+		AutoWithdrawalLimit: user.Wallet.AutoWithdrawalLimit,
+	}
+}
+
+func UserToBalanceUpdatedEventData(user entity.User) events.BalanceUpdatedEventData {
+	return events.BalanceUpdatedEventData{
+		SchemaVersion:         events.SchemaVersion,
+		UserReference:         user.UserReference,
+		WalletReference:       user.Wallet.WalletReference,
+		AvailableAmount:       user.Wallet.Balance.AvailableAmount,
+		PendingIncomingAmount: user.Wallet.Balance.PendingIncomingAmount,
+		Currency:              user.Wallet.Balance.Currency,
+		LastSyncedOn:          user.Wallet.Balance.LastSyncedOn,
+		LedgerSequence:        user.Wallet.Balance.LedgerSequence,
+	}
+}
+
+func UserToTierUpdatedEventData(user entity.User) events.TierUpdatedEventData {
+	return events.TierUpdatedEventData{
+		SchemaVersion:   events.SchemaVersion,
+		UserReference:   user.UserReference,
+		WalletReference: user.Wallet.WalletReference,
+		Tier:            user.Wallet.Tier,
+	}
+}
+
+// UserToCouponAddedEventData - describes the coupon most recently added to the
+// user's wallet.
+func UserToCouponAddedEventData(user entity.User) events.CouponAddedEventData {
+	data := events.CouponAddedEventData{
+		SchemaVersion: events.SchemaVersion,
+		UserReference: user.UserReference,
+	}
+	if len(user.Wallet.Coupons) != 0 {
+		coupon := user.Wallet.Coupons[len(user.Wallet.Coupons)-1]
+		data.CouponReference = coupon.CouponReference
+		data.ExpiryDate = coupon.ExpiryDate
+	}
+	return data
+}
+
+func UserToRewardsUpdatedEventData(user entity.User) events.RewardsUpdatedEventData {
+	return events.RewardsUpdatedEventData{
+		SchemaVersion: events.SchemaVersion,
+		UserReference: user.UserReference,
+		Points:        user.Wallet.Reward.Points,
+	}
+}
+
+// UserToBankAddedEventData - describes the bank most recently added to the
+// user, without its account number.
+func UserToBankAddedEventData(user entity.User) events.BankAddedEventData {
+	data := events.BankAddedEventData{
+		SchemaVersion: events.SchemaVersion,
+		UserReference: user.UserReference,
+	}
+	if len(user.BankAccounts) != 0 {
+		bank := user.BankAccounts[len(user.BankAccounts)-1]
+		data.BankReference = bank.BankReference
+		data.BankName = bank.BankName
+		data.IsDefault = bank.IsDefault
+		data.Status = bank.Status
+	}
+	return data
+}
+
+func UserToBankUpdatedEventData(user entity.User, bankReference string) events.BankUpdatedEventData {
+	data := events.BankUpdatedEventData{
+		SchemaVersion: events.SchemaVersion,
+		UserReference: user.UserReference,
+		BankReference: bankReference,
+	}
+	for _, bank := range user.BankAccounts {
+		if bank.BankReference == bankReference {
+			data.BankName = bank.BankName
+			data.IsDefault = bank.IsDefault
+			data.Status = bank.Status
+			break
+		}
+	}
+	return data
+}
+
+func UserToDefaultBankSetEventData(user entity.User, bankReference string) events.DefaultBankSetEventData {
+	return events.DefaultBankSetEventData{
+		SchemaVersion: events.SchemaVersion,
+		UserReference: user.UserReference,
+		BankReference: bankReference,
+	}
+}
+
+// UserToCardAddedEventData - describes the card most recently added to the
+// user, without its PAN or expiry.
+func UserToCardAddedEventData(user entity.User) events.CardAddedEventData {
+	data := events.CardAddedEventData{
+		SchemaVersion: events.SchemaVersion,
+		UserReference: user.UserReference,
+	}
+	if len(user.Cards) != 0 {
+		card := user.Cards[len(user.Cards)-1]
+		data.CardReference = card.CardReference
+		data.CardName = card.CardName
+		data.IsDefault = card.IsDefault
+		data.Status = card.Status
+	}
+	return data
+}
+
+func UserToCardUpdatedEventData(user entity.User, cardReference string) events.CardUpdatedEventData {
+	data := events.CardUpdatedEventData{
+		SchemaVersion: events.SchemaVersion,
+		UserReference: user.UserReference,
+		CardReference: cardReference,
+	}
+	for _, card := range user.Cards {
+		if card.CardReference == cardReference {
+			data.CardName = card.CardName
+			data.IsDefault = card.IsDefault
+			data.Status = card.Status
+			break
+		}
+	}
+	return data
+}
+
+func UserToDefaultCardSetEventData(user entity.User, cardReference string) events.DefaultCardSetEventData {
+	return events.DefaultCardSetEventData{
+		SchemaVersion: events.SchemaVersion,
+		UserReference: user.UserReference,
+		CardReference: cardReference,
+	}
+}
+
+func UserToDeviceUpdatedEventData(user entity.User) events.DeviceUpdatedEventData {
+	return events.DeviceUpdatedEventData{
+		SchemaVersion: events.SchemaVersion,
+		UserReference: user.UserReference,
+		Device:        user.Device,
+	}
+}
+
+func UserToDeviceRevokedEventData(user entity.User, device entity.RegisteredDevice) events.DeviceRevokedEventData {
+	return events.DeviceRevokedEventData{
+		SchemaVersion:   events.SchemaVersion,
+		UserReference:   user.UserReference,
+		DeviceReference: device.DeviceReference,
+		RevokedOn:       device.RevokedOn,
+	}
+}
+
+func UserToNotificationOptionsUpdatedEventData(user entity.User) events.NotificationOptionsUpdatedEventData {
+	return events.NotificationOptionsUpdatedEventData{
+		SchemaVersion:       events.SchemaVersion,
+		UserReference:       user.UserReference,
+		NotificationOptions: user.NotificationOptions,
+	}
+}
+
+// UserToContactAddedEventData - describes the contact most recently added to
+// the user, without their phone number or name.
+func UserToContactAddedEventData(user entity.User) events.ContactAddedEventData {
+	data := events.ContactAddedEventData{
+		SchemaVersion: events.SchemaVersion,
+		UserReference: user.UserReference,
+	}
+	if len(user.Contacts) != 0 {
+		contact := user.Contacts[len(user.Contacts)-1]
+		data.ContactReference = contact.ContactReference
+		data.WallsTag = contact.WallsTag
+		data.IsBeneficiary = contact.IsBeneficiary
+	}
+	return data
+}
+
+func findCompanyProfile(user entity.User, companyProfileReference string) (entity.CompanyProfile, bool) {
+	for _, companyProfile := range user.CompanyProfile {
+		if companyProfile.CompanyProfileReference == companyProfileReference {
+			return companyProfile, true
+		}
+	}
+	return entity.CompanyProfile{}, false
+}
@@ -2,9 +2,11 @@ package mapper
 
 import (
 	"fmt"
+	"strings"
 	"time"
 	"walls-user-service/internal/core/domain/dto"
 	"walls-user-service/internal/core/domain/entity"
+	"walls-user-service/internal/core/domain/shared"
 
 	"github.com/google/uuid"
 )
@@ -25,6 +27,7 @@ func CurrentUserDtoToUser(createUserDto dto.CreateUserDto, currentUser dto.Curre
 		},
 		Device: entity.Device(currentUser.Device),
 	}
+	user = AddDevice(user, entity.Device(currentUser.Device), "")
 	return user
 }
 
@@ -90,18 +93,26 @@ func UpdateAddressDtoToUser(user entity.User, dto dto.AddressDto) entity.User {
 	return user
 }
 
+// UpdatePhotoDtoToUser - an uploaded photo awaits moderation; it is verified,
+// and made the default, only once the photo verification result arrives.
 func UpdatePhotoDtoToUser(user entity.User, dto dto.PhotoDto) entity.User {
+	uploaded := dto.Photo
+	uploaded.IsVerified = false
+	uploaded.IsDefault = false
+	uploaded.Status = shared.PhotoPending
+	uploaded.RejectionReason = ""
+	uploaded.VerifiedOn = ""
 
-	// update photo if there already is one with the specified reference
+	// replace photo if there already is one with the specified reference
 	for index, photo := range user.UserProfile.Photos {
-		if photo.PhotoReference == dto.Photo.PhotoReference {
-			user.UserProfile.Photos[index] = dto.Photo
-			return user
+		if photo.PhotoReference == uploaded.PhotoReference {
+			user.UserProfile.Photos = append(user.UserProfile.Photos[:index], user.UserProfile.Photos[index+1:]...)
+			break
 		}
 	}
 
-	// add photo if it is a new photo
-	user.UserProfile.Photos = append(user.UserProfile.Photos, dto.Photo)
+	// the photo is the newest either way
+	user.UserProfile.Photos = append(user.UserProfile.Photos, uploaded)
 
 	return user
 }
@@ -117,13 +128,16 @@ func UpdateWalletDtoToWallet(user entity.User, dto dto.UpdateWalletDto) entity.U
 	user.Wallet = wallet
 	return user
 }
+// AddBankDtoToBank - a new bank account awaits verification by the payment
+// integration service, and cannot be the default until it is verified.
 func AddBankDtoToBank(user entity.User, dto dto.BankDto) entity.User {
 	bank := entity.Bank{
 		BankReference: uuid.New().String(),
 		BankName:      dto.BankName,
 		AccountNumber: dto.AccountNumber,
 		AccountName:   dto.AccountName,
-		IsDefault:     dto.IsDefault,
+		IsDefault:     false,
+		Status:        shared.VerificationPending,
 	}
 	user.BankAccounts = append(user.BankAccounts, bank)
 	return user
@@ -138,6 +152,8 @@ func UpdateBankDtoToBank(dto dto.BankDto, bank_reference string) entity.Bank {
 	}
 	return bank
 }
+// AddCardDtoToCard - a new card awaits verification by the payment
+// integration service, and cannot be the default until it is verified.
 func AddCardDtoToCard(user entity.User, dto dto.CardDto) entity.User {
 	card := entity.Card{
 		CardReference: uuid.New().String(),
@@ -145,7 +161,8 @@ func AddCardDtoToCard(user entity.User, dto dto.CardDto) entity.User {
 		Pan:           dto.Pan,
 		ExpiryMonth:   dto.ExpiryMonth,
 		ExpiryYear:    dto.ExpiryYear,
-		IsDefault:     dto.IsDefault,
+		IsDefault:     false,
+		Status:        shared.VerificationPending,
 	}
 	user.Cards = append(user.Cards, card)
 	return user
@@ -170,8 +187,46 @@ func UpdateNotificationOptionsDtoToNotificationOptions(user entity.User, dto dto
 	user.NotificationOptions = notificationOptions
 	return user
 }
-func UpdateDeviceDtoToDevice(user entity.User, dto dto.UpdateDeviceDto) entity.User {
-	user.Device = dto.NewDevice
+// AddDevice - trusts the device for the user, named after its brand and model
+// unless a name is given. A device registered before is trusted again.
+func AddDevice(user entity.User, device entity.Device, name string) entity.User {
+	return registerDevice(user, device, name, shared.DeviceTrusted)
+}
+
+// AddPendingDevice - registers the device for the user until its binding is
+// confirmed. A device registered before is pending again.
+func AddPendingDevice(user entity.User, device entity.Device, name string) entity.User {
+	// the device of a user registered before the device list stays trusted
+	if len(user.Devices) == 0 && user.Device.DeviceReference != "" {
+		user = AddDevice(user, user.Device, "")
+	}
+	return registerDevice(user, device, name, shared.DevicePending)
+}
+
+func registerDevice(user entity.User, device entity.Device, name string, status string) entity.User {
+	now := time.Now().Format(time.RFC3339)
+	if name == "" {
+		name = strings.TrimSpace(device.Brand + " " + device.Model)
+	}
+
+	for i, registered := range user.Devices {
+		if registered.Is(device) {
+			user.Devices[i].Device = device
+			user.Devices[i].Name = name
+			user.Devices[i].Status = status
+			user.Devices[i].LastSeenOn = now
+			user.Devices[i].RevokedOn = ""
+			return user
+		}
+	}
+
+	user.Devices = append(user.Devices, entity.RegisteredDevice{
+		Device:      device,
+		Name:        name,
+		Status:      status,
+		FirstSeenOn: now,
+		LastSeenOn:  now,
+	})
 	return user
 }
 func AddDocumentationDtoToDocumentation(user entity.User, dto dto.AddDocumentationDto) entity.User {
@@ -232,6 +287,7 @@ func UserDtoToUser(userDto dto.UserDto) entity.User {
 		CreatedOn:      userDto.CreatedOn,
 		UpdatedOn:      userDto.UpdatedOn,
 		IsActive:       userDto.IsActive,
+		Version:        userDto.Version,
 		UserProfile:    userDto.UserProfile,
 		CompanyProfile: userDto.CompanyProfile,
 		Contacts:       userDto.Contacts,
@@ -249,6 +305,8 @@ func UserDtoToUser(userDto dto.UserDto) entity.User {
 				TierReference:         userDto.Wallet.Tier.TierReference,
 				TierName:              userDto.Wallet.Tier.TierName,
 				SendingLimit:          userDto.Wallet.Tier.SendingLimit,
+				ReceivingLimit:        userDto.Wallet.Tier.ReceivingLimit,
+				WalletLimit:           userDto.Wallet.Tier.WalletLimit,
 				DailyTransactionLimit: userDto.Wallet.Tier.DailyTransactionLimit,
 				MinimumBalance:        userDto.Wallet.Tier.MinimumBalance,
 				UpgradeOptions:        userDto.Wallet.Tier.UpgradeOptions,
@@ -261,9 +319,11 @@ func UserDtoToUser(userDto dto.UserDto) entity.User {
 		Cards:               userDto.Cards,
 		NotificationOptions: userDto.NotificationOptions,
 		Device:              userDto.Device,
+		Devices:             userDto.Devices,
 		Kyc: entity.Kyc{
 			Documentations: userDocumentations,
 		},
+		TierRequests: userDto.TierRequests,
 	}
 
 	return result
@@ -296,3 +356,53 @@ func UpdateCompanyProfileDtoToCompanyProfile(dto dto.UpdateCompanyProfileDto) en
 
 	return companyProfile
 }
+
+// UserPageToUserPageDto - the page with each user summarised.
+func UserPageToUserPageDto(page entity.UserPage, nextCursor string) dto.UserPageDto {
+	pageDto := dto.UserPageDto{Users: []dto.UserSummaryDto{}, Total: page.Total, NextCursor: nextCursor}
+	for _, user := range page.Users {
+		summary := dto.UserSummaryDto{
+			UserReference:   user.UserReference,
+			FullName:        user.UserProfile.FullName,
+			Phone:           user.UserProfile.Phone,
+			Email:           user.UserProfile.Email,
+			IsVerifiedEmail: user.UserProfile.IsVerifiedEmail,
+			IsActive:        user.IsActive,
+			TierName:        user.Wallet.Tier.TierName,
+			KycVerified:     user.Kyc.IsVerified,
+			CompanyNames:    []string{},
+			CreatedOn:       user.CreatedOn,
+			UpdatedOn:       user.UpdatedOn,
+		}
+		for _, company := range user.CompanyProfile {
+			summary.CompanyNames = append(summary.CompanyNames, company.CompanyName)
+		}
+		pageDto.Users = append(pageDto.Users, summary)
+	}
+	return pageDto
+}
+
+// UserSearchDtoToUserQuery - the search, newest users first unless asked
+// otherwise. The inclusive created_to date becomes the day after it.
+func UserSearchDtoToUserQuery(dto dto.UserSearchDto) entity.UserQuery {
+	query := entity.UserQuery{
+		IsActive:      dto.IsActive,
+		TierName:      dto.TierName,
+		KycVerified:   dto.KycVerified,
+		EmailVerified: dto.EmailVerified,
+		CreatedFrom:   dto.CreatedFrom,
+		PhonePrefix:   dto.PhonePrefix,
+		CompanyName:   dto.CompanyName,
+		SortBy:        dto.Sort,
+		Descending:    dto.Order != "asc",
+		Limit:         dto.Limit,
+	}
+	if query.SortBy == "" {
+		query.SortBy = "created_on"
+	}
+	if createdTo, err := time.Parse("2006-01-02", dto.CreatedTo); err == nil {
+		query.CreatedBefore = createdTo.AddDate(0, 0, 1).Format("2006-01-02")
+	}
+
+	return query
+}
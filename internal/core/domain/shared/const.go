@@ -1,5 +1,31 @@
 package shared
 
+var (
+	OutboxPending    = "pending"
+	OutboxDispatched = "dispatched"
+
+	TierRequestPending  = "pending"
+	TierRequestApproved = "approved"
+	TierRequestRejected = "rejected"
+
+	VerificationPending  = "pending"
+	VerificationVerified = "verified"
+	VerificationFailed   = "failed"
+
+	PhotoPending  = "pending"
+	PhotoVerified = "verified"
+	PhotoRejected = "rejected"
+
+	DevicePending = "pending"
+	DeviceTrusted = "trusted"
+	DeviceRevoked = "revoked"
+
+	RoleUser    = "user"
+	RoleSupport = "support"
+	RoleOps     = "ops"
+	RoleSystem  = "system"
+)
+
 // import "errors"
 
 // type DeviceType int
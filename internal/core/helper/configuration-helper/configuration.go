@@ -6,6 +6,7 @@ type Configuration struct {
 	ServiceMode        string `mapstructure:"Service__Mode"`
 	ServiceName        string `mapstructure:"Service__Name"`
 	LogFile            string `mapstructure:"Service__LogFileName"`
+	AuditLogFile       string `mapstructure:"Service__AuditLogFileName"`
 	LogDir             string `mapstructure:"Service__LogDirectory"`
 	LaunchUrl          string `mapstructure:"Service__LaunchUrl"`
 	AppName            string `mapstructure:"Service__AppName"`
@@ -15,8 +16,29 @@ type Configuration struct {
 	DBName             string `mapstructure:"DBConnection__DatabaseName"`
 	PageLimit          string `mapstructure:"DBConnection__PageLimit"`
 	DBConnectionType   string `mapstructure:"DBConnection__Type"`
+	DBMigrateOnStart   string `mapstructure:"DBConnection__MigrateOnStartup"`
 	EBConnectionString string `mapstructure:"EBConnection__ConnectionString"`
 	EBConnectionTTL    string `mapstructure:"EBConnection__TTl"`
+	EBTransport        string `mapstructure:"EBConnection__Transport"`
+	EBConsumerGroup    string `mapstructure:"EBConnection__ConsumerGroup"`
+	EBConsumerName     string `mapstructure:"EBConnection__ConsumerName"`
+	EBClaimIdle        string `mapstructure:"EBConnection__ClaimIdle"`
+	EBStreamMaxLen     string `mapstructure:"EBConnection__StreamMaxLen"`
+	EBMaxAttempts      string `mapstructure:"EBConnection__MaxAttempts"`
+	EBRetryBackoff     string `mapstructure:"EBConnection__RetryBackoff"`
+	EBDeadLetterStream string `mapstructure:"EBConnection__DeadLetterStream"`
+	EBEventFormat      string `mapstructure:"EBConnection__EventFormat"`
+	EBDedupRetention   string `mapstructure:"EBConnection__DedupRetention"`
+	OutboxPollInterval string `mapstructure:"Outbox__PollInterval"`
+	OutboxBatchSize    string `mapstructure:"Outbox__BatchSize"`
+	TokenMode          string `mapstructure:"Token__Mode"`
+	TokenKey           string `mapstructure:"Token__Key"`
+	TokenJwksFile      string `mapstructure:"Token__JwksFile"`
+	TokenAudience      string `mapstructure:"Token__Audience"`
+	TokenIssuer        string `mapstructure:"Token__Issuer"`
+	TokenExpiry        string `mapstructure:"Token__Expiry"`
+	DeviceCoolingOff   string `mapstructure:"Device__CoolingOffHours"`
+	DeviceCoolingLimit string `mapstructure:"Device__CoolingOffLimit"`
 	ExternalConfigPath string `mapstructure:"external_config_path"`
 	UserExpiry         string `mapstructure:"Service__UserExpiry"`
 }
@@ -2,6 +2,7 @@ package helper
 
 import (
 	"log"
+	"reflect"
 
 	"github.com/spf13/viper"
 )
@@ -20,7 +21,10 @@ func loadConfig(path string) Configuration {
 	viper.AutomaticEnv()
 
 	err := viper.ReadInConfig()
-	if err != nil {
+	if _, missing := err.(viper.ConfigFileNotFoundError); missing {
+		// Without the file every setting is read from the environment
+		bindEnv()
+	} else if err != nil {
 		log.Fatal(err)
 	}
 
@@ -37,3 +41,12 @@ func loadConfig(path string) Configuration {
 
 	return config
 }
+
+// bindEnv - binds every setting to the environment variable of its key, as
+// AutomaticEnv only covers the keys read from a file.
+func bindEnv() {
+	fields := reflect.TypeOf(Configuration{})
+	for i := 0; i < fields.NumField(); i++ {
+		_ = viper.BindEnv(fields.Field(i).Tag.Get("mapstructure"))
+	}
+}
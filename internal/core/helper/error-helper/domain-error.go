@@ -0,0 +1,101 @@
+package helper
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// DomainError - a known failure returned by the service layer. ErrorType decides
+// the HTTP status, ErrorCode tells clients which failure it was.
+type DomainError struct {
+	ErrorType string
+	ErrorCode string
+	Message   string
+	Err       error
+}
+
+func (err DomainError) Error() string {
+	return err.Message
+}
+
+func (err DomainError) Unwrap() error {
+	return err.Err
+}
+
+// Is - domain errors match on their code, so a wrapped or re-messaged error still
+// matches the error it was built from.
+func (err DomainError) Is(target error) bool {
+	domainError, ok := target.(DomainError)
+	return ok && domainError.ErrorCode == err.ErrorCode
+}
+
+// Wrap - the same domain error, caused by err.
+func (err DomainError) Wrap(cause error) DomainError {
+	err.Err = cause
+	return err
+}
+
+// WithMessage - the same domain error, told with a more specific message.
+func (err DomainError) WithMessage(message string) DomainError {
+	err.Message = message
+	return err
+}
+
+func NotFound(code string, message string) DomainError {
+	return DomainError{ErrorType: NoResourceError, ErrorCode: code, Message: message}
+}
+
+func Conflict(code string, message string) DomainError {
+	return DomainError{ErrorType: ConflictError, ErrorCode: code, Message: message}
+}
+
+func Forbidden(code string, message string) DomainError {
+	return DomainError{ErrorType: ForbiddenError, ErrorCode: code, Message: message}
+}
+
+func Validation(code string, message string) DomainError {
+	return DomainError{ErrorType: ValidationError, ErrorCode: code, Message: message}
+}
+
+func LimitExceeded(code string, message string) DomainError {
+	return DomainError{ErrorType: LimitExceededError, ErrorCode: code, Message: message}
+}
+
+// IsNotFound - whether err is a missing document or a not found domain error.
+func IsNotFound(err error) bool {
+	var domainError DomainError
+	if errors.As(err, &domainError) {
+		return domainError.ErrorType == NoResourceError
+	}
+	return errors.Is(err, mongo.ErrNoDocuments)
+}
+
+// ErrorFrom - the response for an error returned by the service layer. Domain
+// errors keep their type and code, a missing document is a 404 and anything
+// else is a 500.
+func ErrorFrom(err error) ErrorResponse {
+	var errorResponse ErrorResponse
+	if errors.As(err, &errorResponse) {
+		return errorResponse
+	}
+
+	errorType, errorCode := MongoDBError, ""
+	var domainError DomainError
+	switch {
+	case errors.As(err, &domainError):
+		errorType, errorCode = domainError.ErrorType, domainError.ErrorCode
+	case errors.Is(err, mongo.ErrNoDocuments):
+		errorType, errorCode = NoResourceError, "NOT_FOUND"
+	}
+
+	errorResponse.TimeStamp = time.Now().Format(time.RFC3339)
+	errorResponse.ErrorReference = uuid.New()
+	errorResponse.ErrorType = errorType
+	errorResponse.ErrorCode = errorCode
+	errorResponse.Code = CustomError[errorType]
+	errorResponse.Errors = append(errorResponse.Errors, err.Error())
+	return errorResponse
+}
@@ -0,0 +1,50 @@
+package helper
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestErrorFrom(t *testing.T) {
+	for name, test := range map[string]struct {
+		err       error
+		status    int
+		errorType string
+		errorCode string
+	}{
+		"not found":      {NotFound("USER_NOT_FOUND", "user not found"), 404, NoResourceError, "USER_NOT_FOUND"},
+		"conflict":       {Conflict("USER_ALREADY_EXISTS", "user already exists"), 409, ConflictError, "USER_ALREADY_EXISTS"},
+		"forbidden":      {Forbidden("DEVICE_NOT_REGISTERED", "device not registered"), 403, ForbiddenError, "DEVICE_NOT_REGISTERED"},
+		"validation":     {Validation("INVALID_CARD_EXPIRY", "invalid expiry"), 400, ValidationError, "INVALID_CARD_EXPIRY"},
+		"limit exceeded": {LimitExceeded("INSUFFICIENT_FUNDS", "insufficient funds"), 422, LimitExceededError, "INSUFFICIENT_FUNDS"},
+		"wrapped":        {fmt.Errorf("updating user: %w", Conflict("STALE", "stale")), 409, ConflictError, "STALE"},
+		"no documents":   {fmt.Errorf("finding user: %w", mongo.ErrNoDocuments), 404, NoResourceError, "NOT_FOUND"},
+		"unknown":        {errors.New("connection refused"), 500, MongoDBError, ""},
+	} {
+		errorResponse := ErrorFrom(test.err)
+		if errorResponse.Code != test.status || errorResponse.ErrorType != test.errorType || errorResponse.ErrorCode != test.errorCode {
+			t.Errorf("%s: expected %d %s %q, got %d %s %q", name, test.status, test.errorType, test.errorCode,
+				errorResponse.Code, errorResponse.ErrorType, errorResponse.ErrorCode)
+		}
+		if len(errorResponse.Errors) != 1 || errorResponse.Errors[0] != test.err.Error() {
+			t.Errorf("%s: expected message %q, got %v", name, test.err.Error(), errorResponse.Errors)
+		}
+	}
+}
+
+func TestDomainErrorIsMatchesOnCode(t *testing.T) {
+	notFound := NotFound("USER_NOT_FOUND", "user not found")
+
+	if !errors.Is(notFound.WithMessage("no user with that phone").Wrap(mongo.ErrNoDocuments), notFound) {
+		t.Fatal("expected a re-messaged, wrapped error to match the error it was built from")
+	}
+	if errors.Is(NotFound("CARD_NOT_FOUND", "user not found"), notFound) {
+		t.Fatal("expected errors with different codes not to match")
+	}
+	if !IsNotFound(mongo.ErrNoDocuments) || !IsNotFound(notFound) || IsNotFound(Conflict("X", "x")) {
+		t.Fatal("IsNotFound misclassified an error")
+	}
+}
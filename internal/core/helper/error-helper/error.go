@@ -22,6 +22,11 @@ var (
 	InvalidUser     = "INVALID_User_ERROR"
 	RedisError      = "REDIS_ERROR"
 	BadRequestError = "BAD_REQUEST_ERROR"
+
+	ConflictError      = "CONFLICT_ERROR"
+	ForbiddenError     = "FORBIDDEN_ERROR"
+	UnauthorizedError  = "UNAUTHORIZED_ERROR"
+	LimitExceededError = "LIMIT_EXCEEDED_ERROR"
 )
 
 var CustomError = map[string]int{
@@ -38,6 +43,11 @@ var CustomError = map[string]int{
 	InvalidUser:     400,
 	RedisError:      500,
 	BadRequestError: 400,
+
+	ConflictError:      409,
+	ForbiddenError:     403,
+	UnauthorizedError:  401,
+	LimitExceededError: 422,
 }
 
 func (err ErrorResponse) Error() string {
@@ -52,8 +62,10 @@ func ErrorArrayToError(errorBody []validator.FieldError) error {
 	errorResponse.ErrorType = ValidationError
 	errorResponse.Code = CustomError[ValidationError]
 	for _, value := range errorBody {
-		body := ErrorBody{value.Error()}
+		fieldError := NewFieldError(value)
+		body := ErrorBody{fieldError.Field + " " + fieldError.Message}
 		errorResponse.Errors = append(errorResponse.Errors, body.Message)
+		errorResponse.Body = append(errorResponse.Body, fieldError)
 	}
 	return errorResponse
 }
@@ -73,9 +85,12 @@ type ErrorBody struct {
 	//Source  string      `json:"source"`
 }
 type ErrorResponse struct {
-	ErrorReference uuid.UUID `json:"error_reference"`
-	ErrorType      string    `json:"error_type"`
-	TimeStamp      string    `json:"timestamp"`
-	Code           int       `json:"code"`
-	Errors         []string  `json:"errors"`
+	ErrorReference uuid.UUID    `json:"error_reference"`
+	ErrorType      string       `json:"error_type"`
+	ErrorCode      string       `json:"error_code,omitempty"`
+	TimeStamp      string       `json:"timestamp"`
+	Code           int          `json:"code"`
+	Errors         []string     `json:"errors"`
+	Body           []FieldError `json:"body,omitempty"`
+	Headers        []FieldError `json:"headers,omitempty"`
 }
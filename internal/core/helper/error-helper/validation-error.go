@@ -0,0 +1,129 @@
+package helper
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+// FieldError - a field that failed validation: its JSON path, or header name for
+// a header, the rule it failed and a message for the user.
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+var ruleMessages = map[string]string{
+	"required":      "is required",
+	"guid":          "must be a valid reference",
+	"imei":          "must be a valid IMEI",
+	"valid_phone":   "must be a valid phone number",
+	"valid_email":   "must be a valid email address",
+	"email":         "must be a valid email address",
+	"valid_contact": "must be a valid phone number or email address",
+	"alpha":         "must contain letters only",
+	"alphanum":      "must contain letters and digits only",
+	"numeric":       "must be a number",
+	"boolean":       "must be true or false",
+	"url":           "must be a valid URL",
+}
+
+// NewFieldError - the field error for a failed validation. The path is the
+// namespace of the field without the name of the validated struct.
+func NewFieldError(fieldError validator.FieldError) FieldError {
+	field := fieldError.Namespace()
+	if index := strings.Index(field, "."); index != -1 {
+		field = field[index+1:]
+	}
+
+	return FieldError{
+		Field:   field,
+		Rule:    fieldError.Tag(),
+		Message: ruleMessage(fieldError),
+	}
+}
+
+func ruleMessage(fieldError validator.FieldError) string {
+	if message, ok := ruleMessages[fieldError.Tag()]; ok {
+		return message
+	}
+
+	unit := ""
+	switch fieldError.Kind().String() {
+	case "string":
+		unit = " characters"
+	case "slice", "array", "map":
+		unit = " items"
+	}
+
+	param := fieldError.Param()
+	switch fieldError.Tag() {
+	case "len":
+		return "must be " + param + unit + " long"
+	case "min", "gte":
+		return "must be at least " + param + unit
+	case "max", "lte":
+		return "must be at most " + param + unit
+	case "gt":
+		return "must be greater than " + param
+	case "lt":
+		return "must be less than " + param
+	case "eq":
+		return "must be " + param
+	case "oneof":
+		return "must be one of " + strings.Join(strings.Fields(param), ", ")
+	case "datetime":
+		return "must be a date formatted as " + param
+	}
+
+	if strings.Contains(fieldError.Tag(), "|") {
+		return "must satisfy one of " + strings.ReplaceAll(fieldError.Tag(), "|", ", ")
+	}
+	return fmt.Sprintf("failed the %s rule", fieldError.Tag())
+}
+
+// HeaderErrorArrayToError - ErrorArrayToError for a struct read from request
+// headers. Each field is reported under Headers by the header it was read from,
+// as named in headerNames, or by its path when it has no header.
+func HeaderErrorArrayToError(errorBody []validator.FieldError, headerNames map[string]string) error {
+	errorResponse := ErrorArrayToError(errorBody).(ErrorResponse)
+	errorResponse.Errors = nil
+	for _, fieldError := range errorResponse.Body {
+		if header, ok := headerNames[fieldError.Field]; ok {
+			fieldError.Field = header
+		}
+		errorResponse.Errors = append(errorResponse.Errors, fieldError.Field+" "+fieldError.Message)
+		errorResponse.Headers = append(errorResponse.Headers, fieldError)
+	}
+	errorResponse.Body = nil
+	return errorResponse
+}
+
+// JoinValidationErrors - a single validation error reporting the failing fields
+// of every given validation error, or nil when none failed.
+func JoinValidationErrors(errs ...error) error {
+	var joined ErrorResponse
+	for _, err := range errs {
+		var errorResponse ErrorResponse
+		if !errors.As(err, &errorResponse) {
+			continue
+		}
+		joined.Errors = append(joined.Errors, errorResponse.Errors...)
+		joined.Body = append(joined.Body, errorResponse.Body...)
+		joined.Headers = append(joined.Headers, errorResponse.Headers...)
+	}
+	if len(joined.Errors) == 0 {
+		return nil
+	}
+
+	joined.TimeStamp = time.Now().Format(time.RFC3339)
+	joined.ErrorReference = uuid.New()
+	joined.ErrorType = ValidationError
+	joined.Code = CustomError[ValidationError]
+	return joined
+}
@@ -0,0 +1,81 @@
+package helper
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+)
+
+type testCountry struct {
+	DialCode string `json:"dial_code" validate:"required,numeric"`
+}
+
+type testAddress struct {
+	Country testCountry `json:"country" validate:"required"`
+}
+
+type testBody struct {
+	Address  testAddress `json:"address" validate:"required"`
+	WallsTag string      `json:"walls_tag" validate:"required,len=8"`
+}
+
+type testHeaders struct {
+	Phone string `json:"phone" validate:"required"`
+}
+
+func validationErrors(t *testing.T, data interface{}) []validator.FieldError {
+	t.Helper()
+	validate := validator.New()
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		return strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+	})
+
+	var validationErrors validator.ValidationErrors
+	if !errors.As(validate.Struct(data), &validationErrors) {
+		t.Fatal("expected validation errors")
+	}
+	fieldErrors := []validator.FieldError{}
+	for _, fieldError := range validationErrors {
+		fieldErrors = append(fieldErrors, fieldError)
+	}
+	return fieldErrors
+}
+
+func TestErrorArrayToErrorReportsFieldPaths(t *testing.T) {
+	err := ErrorArrayToError(validationErrors(t, testBody{Address: testAddress{Country: testCountry{DialCode: "23a"}}, WallsTag: "abc"}))
+
+	errorResponse := err.(ErrorResponse)
+	expected := []FieldError{
+		{Field: "address.country.dial_code", Rule: "numeric", Message: "must be a number"},
+		{Field: "walls_tag", Rule: "len", Message: "must be 8 characters long"},
+	}
+	if errorResponse.Code != 400 || !reflect.DeepEqual(errorResponse.Body, expected) || errorResponse.Headers != nil {
+		t.Fatalf("expected body errors %+v, got %+v", expected, errorResponse)
+	}
+}
+
+func TestJoinValidationErrorsReportsHeadersApart(t *testing.T) {
+	err := JoinValidationErrors(
+		nil,
+		ErrorArrayToError(validationErrors(t, testBody{Address: testAddress{Country: testCountry{DialCode: "234"}}})),
+		HeaderErrorArrayToError(validationErrors(t, testHeaders{}), map[string]string{"phone": "X-Phone"}),
+	)
+
+	errorResponse := err.(ErrorResponse)
+	if !reflect.DeepEqual(errorResponse.Body, []FieldError{{Field: "walls_tag", Rule: "required", Message: "is required"}}) {
+		t.Fatalf("unexpected body errors %+v", errorResponse.Body)
+	}
+	if !reflect.DeepEqual(errorResponse.Headers, []FieldError{{Field: "X-Phone", Rule: "required", Message: "is required"}}) {
+		t.Fatalf("unexpected header errors %+v", errorResponse.Headers)
+	}
+	if !reflect.DeepEqual(errorResponse.Errors, []string{"walls_tag is required", "X-Phone is required"}) {
+		t.Fatalf("unexpected messages %v", errorResponse.Errors)
+	}
+
+	if JoinValidationErrors(nil, nil) != nil {
+		t.Fatal("expected no error when nothing failed")
+	}
+}
@@ -0,0 +1,87 @@
+package eto
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+var (
+	CloudEventsSpecVersion = "1.0"
+	CloudEventsContentType = "application/json"
+)
+
+// CloudEvent - an Event in CloudEvents 1.0 structured-mode JSON. EventName and
+// EventType have no CloudEvents attribute of their own, so they travel as the
+// eventname and eventtype extension attributes.
+type CloudEvent struct {
+	SpecVersion     string      `json:"specversion"`
+	ID              string      `json:"id"`
+	Source          string      `json:"source"`
+	Type            string      `json:"type"`
+	Time            string      `json:"time,omitempty"`
+	Subject         string      `json:"subject,omitempty"`
+	DataContentType string      `json:"datacontenttype,omitempty"`
+	Data            interface{} `json:"data,omitempty"`
+	EventName       string      `json:"eventname,omitempty"`
+	EventType       string      `json:"eventtype,omitempty"`
+}
+
+// NewCloudEvent - the CloudEvents form of an event published as the given type.
+func NewCloudEvent(event Event, cloudEventType string) CloudEvent {
+	return CloudEvent{
+		SpecVersion:     CloudEventsSpecVersion,
+		ID:              event.EventReference,
+		Source:          event.EventSource,
+		Type:            cloudEventType,
+		Time:            event.EventDate,
+		Subject:         event.EventUserReference,
+		DataContentType: CloudEventsContentType,
+		Data:            event.EventData,
+		EventName:       event.EventName,
+		EventType:       event.EventType,
+	}
+}
+
+// Event - the legacy envelope of a CloudEvent. Events from producers that do
+// not set the eventname extension are named after their CloudEvents type.
+func (c CloudEvent) Event() Event {
+	eventName := c.EventName
+	if eventName == "" {
+		eventName = c.Type
+	}
+
+	return Event{
+		EventReference:     c.ID,
+		EventName:          eventName,
+		EventDate:          c.Time,
+		EventType:          c.EventType,
+		EventSource:        c.Source,
+		EventUserReference: c.Subject,
+		EventData:          c.Data,
+	}
+}
+
+// DecodeEvent - decodes an event payload in either the legacy or the
+// CloudEvents encoding, told apart by the specversion attribute.
+func DecodeEvent(payload []byte) (Event, error) {
+	var probe map[string]json.RawMessage
+	if err := json.Unmarshal(payload, &probe); err != nil {
+		return Event{}, err
+	}
+
+	if _, ok := probe["specversion"]; !ok {
+		var event Event
+		err := json.Unmarshal(payload, &event)
+		return event, err
+	}
+
+	var cloudEvent CloudEvent
+	if err := json.Unmarshal(payload, &cloudEvent); err != nil {
+		return Event{}, err
+	}
+	if !strings.HasPrefix(cloudEvent.SpecVersion, "1.") {
+		return Event{}, fmt.Errorf("unsupported cloudevents specversion: %q", cloudEvent.SpecVersion)
+	}
+	return cloudEvent.Event(), nil
+}
@@ -0,0 +1,75 @@
+package eto
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+var testEvent = Event{
+	EventReference:     "8f9a1c2e-1111-4c3b-9d5e-000000000001",
+	EventName:          "OTPVALIDATEDEVENT",
+	EventDate:          "2024-01-02T03:04:05Z",
+	EventType:          "create_user",
+	EventSource:        "WALLS-OTP-SERVICE",
+	EventUserReference: "user-reference",
+	EventData:          map[string]interface{}{"channel": "sms"},
+}
+
+func TestDecodeEventLegacy(t *testing.T) {
+	payload, err := json.Marshal(testEvent)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoded, err := DecodeEvent(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, testEvent) {
+		t.Fatalf("expected %+v, got %+v", testEvent, decoded)
+	}
+}
+
+func TestDecodeEventCloudEvents(t *testing.T) {
+	payload, err := json.Marshal(NewCloudEvent(testEvent, "OTPVALIDATEDEVENT.V1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var attributes map[string]interface{}
+	if err := json.Unmarshal(payload, &attributes); err != nil {
+		t.Fatal(err)
+	}
+	for _, attribute := range []string{"specversion", "id", "source", "type", "time", "subject", "datacontenttype", "data"} {
+		if _, ok := attributes[attribute]; !ok {
+			t.Errorf("missing cloudevents attribute %q", attribute)
+		}
+	}
+
+	decoded, err := DecodeEvent(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, testEvent) {
+		t.Fatalf("expected %+v, got %+v", testEvent, decoded)
+	}
+}
+
+func TestDecodeEventCloudEventsWithoutExtensions(t *testing.T) {
+	payload := []byte(`{"specversion":"1.0","id":"ref","source":"walls-otp-service","type":"OTPVALIDATEDEVENT.V1","data":{}}`)
+
+	decoded, err := DecodeEvent(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decoded.EventReference != "ref" || decoded.EventName != "OTPVALIDATEDEVENT.V1" {
+		t.Fatalf("unexpected envelope %+v", decoded)
+	}
+}
+
+func TestDecodeEventUnsupportedSpecVersion(t *testing.T) {
+	if _, err := DecodeEvent([]byte(`{"specversion":"0.3","id":"ref"}`)); err == nil {
+		t.Fatal("expected specversion 0.3 to be refused")
+	}
+}
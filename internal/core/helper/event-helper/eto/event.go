@@ -9,3 +9,8 @@ type Event struct {
 	EventUserReference string
 	EventData          interface{}
 }
+
+// Envelope - the event's envelope, promoted to every event type embedding Event.
+func (e Event) Envelope() Event {
+	return e
+}
@@ -5,13 +5,29 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
-	"reflect"
 	"strings"
+	configuration "walls-user-service/internal/core/helper/configuration-helper"
+	eto "walls-user-service/internal/core/helper/event-helper/eto"
 	logger "walls-user-service/internal/core/helper/log-helper"
 
 	"github.com/redis/go-redis/v9"
 )
 
+var (
+	PubSubTransport  = "pubsub"
+	StreamsTransport = "streams"
+
+	LegacyEventFormat      = "legacy"
+	CloudEventsEventFormat = "cloudevents"
+)
+
+// EventHandler processes a decoded event. A nil error acknowledges the event.
+type EventHandler func(context.Context, interface{}) error
+
+type eventChannelKey struct{}
+
+type deliveryAttemptKey struct{}
+
 type RedisClient struct {
 	client *redis.Client
 }
@@ -22,7 +38,11 @@ func NewRedisClient(client *redis.Client) *RedisClient {
 	}
 }
 
-func (r *RedisClient) SubscribeToEvent(ctx context.Context, event interface{}, eventHandler func(context.Context, interface{})) error {
+func (r *RedisClient) SubscribeToEvent(ctx context.Context, event interface{}, eventHandler EventHandler) error {
+	if transport() == StreamsTransport {
+		return r.subscribeToStream(ctx, event.(string), eventHandler)
+	}
+
 	// Get the channel name from the event object's type
 
 	pubSub := r.client.PSubscribe(ctx, event.(string))
@@ -43,29 +63,78 @@ func (r *RedisClient) SubscribeToEvent(ctx context.Context, event interface{}, e
 				continue
 			}
 
-			eventHandler(ctx, eventData) // Pass the appropriate UserRepository instance here
+			err = eventHandler(withEventChannel(ctx, msg.Channel), eventData) // Pass the appropriate UserRepository instance here
+			if err != nil {
+				logger.LogEvent("ERROR", "Error handling event from "+msg.Channel+": "+err.Error())
+			}
 		}
 	}
 
 }
 
-func (r *RedisClient) PublishEvent(ctx context.Context, event interface{}, eventType ...string) error {
-	eventBytes, err := json.Marshal(event)
-	if err != nil {
-		return err
-	}
-
-	// Determine the channel name
-	var channel string = strings.ToUpper(reflect.TypeOf(event).Name())
+// PublishToChannel - publishes an already encoded event on the given channel.
+func (r *RedisClient) PublishToChannel(ctx context.Context, channel string, eventBytes []byte) error {
 	fmt.Println("publishing to channel:", channel)
-	if len(eventType) != 0 {
-		channel = fmt.Sprintf("%s:%s", channel, strings.ToUpper(eventType[0]))
+
+	if transport() == StreamsTransport {
+		return r.publishToStream(ctx, channel, eventBytes)
 	}
 
-	err = r.client.Publish(ctx, channel, string(eventBytes)).Err()
+	err := r.client.Publish(ctx, channel, string(eventBytes)).Err()
 	if err != nil {
 		return err
 	}
 
 	return nil
 }
+
+// EncodeEvent - encodes an event for publishing on the given channel, in the
+// configured event format. As a CloudEvent its type is the channel without the
+// event type suffix, which is carried in the eventtype extension instead.
+func EncodeEvent(domainEvent interface{ Envelope() eto.Event }, channel string) ([]byte, error) {
+	if eventFormat() != CloudEventsEventFormat {
+		return json.Marshal(domainEvent)
+	}
+
+	cloudEventType := strings.SplitN(channel, ":", 2)[0]
+	return json.Marshal(eto.NewCloudEvent(domainEvent.Envelope(), cloudEventType))
+}
+
+// EventChannel - the channel or stream the event being handled was received
+// on, empty outside of an event handler.
+func EventChannel(ctx context.Context) string {
+	channel, _ := ctx.Value(eventChannelKey{}).(string)
+	return channel
+}
+
+func withEventChannel(ctx context.Context, channel string) context.Context {
+	return context.WithValue(ctx, eventChannelKey{}, channel)
+}
+
+// DeliveryAttempt - how many times the transport has delivered the event being
+// handled, counting this delivery. 0 when the transport never redelivers an
+// event, as with pub/sub.
+func DeliveryAttempt(ctx context.Context) int {
+	attempt, _ := ctx.Value(deliveryAttemptKey{}).(int)
+	return attempt
+}
+
+func withDeliveryAttempt(ctx context.Context, attempt int) context.Context {
+	return context.WithValue(ctx, deliveryAttemptKey{}, attempt)
+}
+
+// transport - the configured event bus transport, streams unless pub/sub is selected.
+func transport() string {
+	if strings.ToLower(configuration.ServiceConfiguration.EBTransport) == PubSubTransport {
+		return PubSubTransport
+	}
+	return StreamsTransport
+}
+
+// eventFormat - the configured encoding of published events, legacy unless cloudevents is selected.
+func eventFormat() string {
+	if strings.ToLower(configuration.ServiceConfiguration.EBEventFormat) == CloudEventsEventFormat {
+		return CloudEventsEventFormat
+	}
+	return LegacyEventFormat
+}
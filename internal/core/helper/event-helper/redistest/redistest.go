@@ -0,0 +1,592 @@
+// Package redistest provides a Redis client backed by an in-memory store, for
+// testing the event bus and the Redis repositories without a Redis server. Only
+// the commands the service sends are understood; any other fails the command.
+package redistest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Store - the keys, hashes, streams and published messages of the fake Redis.
+type Store struct {
+	mutex     sync.Mutex
+	now       time.Time
+	values    map[string]value
+	hashes    map[string]map[string]int64
+	streams   map[string]*stream
+	published []Message
+	failures  map[string]error
+}
+
+// Message - a message published on a pub/sub channel.
+type Message struct {
+	Channel string
+	Payload string
+}
+
+type value struct {
+	data    string
+	expires time.Time
+}
+
+type stream struct {
+	lastID  int64
+	entries []redis.XMessage
+	groups  map[string]*group
+}
+
+type group struct {
+	lastDelivered int64
+	pending       map[string]*pendingEntry
+}
+
+type pendingEntry struct {
+	consumer    string
+	deliveredOn time.Time
+	deliveries  int64
+}
+
+// NewClient - a client whose commands are served by the returned store. It
+// never connects anywhere.
+func NewClient() (*redis.Client, *Store) {
+	store := &Store{
+		now:      time.Now(),
+		values:   map[string]value{},
+		hashes:   map[string]map[string]int64{},
+		streams:  map[string]*stream{},
+		failures: map[string]error{},
+	}
+	client := redis.NewClient(&redis.Options{Addr: "redistest:0"})
+	client.AddHook(store)
+	return client, store
+}
+
+// Advance - moves the store's clock, expiring keys and idling pending entries.
+func (s *Store) Advance(duration time.Duration) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.now = s.now.Add(duration)
+}
+
+// Fail - makes every later command with the name fail with err, until Fail is
+// called again with a nil error.
+func (s *Store) Fail(command string, err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if err == nil {
+		delete(s.failures, command)
+		return
+	}
+	s.failures[command] = err
+}
+
+// Get - the unexpired value of a key, and its time to live, 0 when it has none.
+func (s *Store) Get(key string) (string, time.Duration, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	stored, ok := s.value(key)
+	if !ok {
+		return "", 0, false
+	}
+	if stored.expires.IsZero() {
+		return stored.data, 0, true
+	}
+	return stored.data, stored.expires.Sub(s.now), true
+}
+
+// HashField - a field of a hash of counters.
+func (s *Store) HashField(key string, field string) int64 {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.hashes[key][field]
+}
+
+// Published - the messages published on pub/sub channels, oldest first.
+func (s *Store) Published() []Message {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]Message{}, s.published...)
+}
+
+// Entries - the entries of a stream, oldest first.
+func (s *Store) Entries(name string) []redis.XMessage {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.streams[name] == nil {
+		return nil
+	}
+	return append([]redis.XMessage{}, s.streams[name].entries...)
+}
+
+// Pending - the entries of a stream delivered to the group and not yet
+// acknowledged, oldest first.
+func (s *Store) Pending(name string, groupName string) []redis.XPendingExt {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.pending(name, groupName, "-", "+")
+}
+
+func (s *Store) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return nil, errors.New("redistest: the fake client never dials")
+	}
+}
+
+func (s *Store) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return s.process
+}
+
+func (s *Store) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		for _, cmd := range cmds {
+			if err := s.process(ctx, cmd); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func (s *Store) process(ctx context.Context, cmd redis.Cmder) error {
+	s.mutex.Lock()
+	err := s.failures[cmd.Name()]
+	if err == nil {
+		err = s.run(cmd, stringArgs(cmd.Args()))
+	}
+	s.mutex.Unlock()
+
+	if errors.Is(err, redis.Nil) && cmd.Name() == "xreadgroup" {
+		// Stand in for a blocking read, so a consumer polling an empty stream
+		// does not spin.
+		time.Sleep(time.Millisecond)
+	}
+	if err != nil {
+		cmd.SetErr(err)
+	}
+	return err
+}
+
+func (s *Store) run(cmd redis.Cmder, args []string) error {
+	switch cmd.Name() {
+	case "set":
+		return s.set(cmd, args)
+	case "setnx":
+		return s.set(cmd, append(args, "nx"))
+	case "get":
+		stored, ok := s.value(args[1])
+		if !ok {
+			return redis.Nil
+		}
+		cmd.(*redis.StringCmd).SetVal(stored.data)
+	case "del":
+		deleted := int64(0)
+		for _, key := range args[1:] {
+			if _, ok := s.value(key); ok {
+				deleted++
+			}
+			delete(s.values, key)
+		}
+		cmd.(*redis.IntCmd).SetVal(deleted)
+	case "hincrby":
+		increment, _ := strconv.ParseInt(args[3], 10, 64)
+		if s.hashes[args[1]] == nil {
+			s.hashes[args[1]] = map[string]int64{}
+		}
+		s.hashes[args[1]][args[2]] += increment
+		cmd.(*redis.IntCmd).SetVal(s.hashes[args[1]][args[2]])
+	case "publish":
+		s.published = append(s.published, Message{Channel: args[1], Payload: args[2]})
+		cmd.(*redis.IntCmd).SetVal(0)
+	case "scan":
+		return s.scan(cmd, args)
+	case "xadd":
+		return s.xadd(cmd, args)
+	case "xgroup":
+		return s.xgroup(cmd, args)
+	case "xreadgroup":
+		return s.xreadgroup(cmd, args)
+	case "xack":
+		return s.xack(cmd, args)
+	case "xautoclaim":
+		return s.xautoclaim(cmd, args)
+	case "xpending":
+		return s.xpending(cmd, args)
+	case "xrange", "xrevrange":
+		return s.xrange(cmd, args)
+	case "xdel":
+		return s.xdel(cmd, args)
+	default:
+		return fmt.Errorf("redistest: unsupported command %q", cmd.Name())
+	}
+	return nil
+}
+
+func (s *Store) value(key string) (value, bool) {
+	stored, ok := s.values[key]
+	if !ok {
+		return value{}, false
+	}
+	if !stored.expires.IsZero() && !s.now.Before(stored.expires) {
+		delete(s.values, key)
+		return value{}, false
+	}
+	return stored, true
+}
+
+// set - SET key value [EX seconds | PX milliseconds] [NX].
+func (s *Store) set(cmd redis.Cmder, args []string) error {
+	stored := value{data: args[2]}
+	onlyIfAbsent := false
+	for i := 3; i < len(args); i++ {
+		switch args[i] {
+		case "ex", "px":
+			amount, _ := strconv.ParseInt(args[i+1], 10, 64)
+			unit := time.Second
+			if args[i] == "px" {
+				unit = time.Millisecond
+			}
+			stored.expires = s.now.Add(time.Duration(amount) * unit)
+			i++
+		case "nx":
+			onlyIfAbsent = true
+		}
+	}
+
+	_, exists := s.value(args[1])
+	if onlyIfAbsent && exists {
+		if boolCmd, ok := cmd.(*redis.BoolCmd); ok {
+			boolCmd.SetVal(false)
+			return nil
+		}
+		return redis.Nil
+	}
+	s.values[args[1]] = stored
+
+	switch typed := cmd.(type) {
+	case *redis.BoolCmd:
+		typed.SetVal(true)
+	case *redis.StatusCmd:
+		typed.SetVal("OK")
+	}
+	return nil
+}
+
+// scan - SCAN cursor [MATCH pattern] [COUNT count] [TYPE type], in one page.
+func (s *Store) scan(cmd redis.Cmder, args []string) error {
+	pattern, keyType := "*", ""
+	for i := 2; i+1 < len(args); i += 2 {
+		switch args[i] {
+		case "match":
+			pattern = args[i+1]
+		case "type":
+			keyType = args[i+1]
+		}
+	}
+
+	keys := []string{}
+	if keyType == "" || keyType == "string" {
+		for key := range s.values {
+			keys = append(keys, key)
+		}
+	}
+	if keyType == "" || keyType == "hash" {
+		for key := range s.hashes {
+			keys = append(keys, key)
+		}
+	}
+	if keyType == "" || keyType == "stream" {
+		for key := range s.streams {
+			keys = append(keys, key)
+		}
+	}
+
+	matched := []string{}
+	for _, key := range keys {
+		if ok, _ := path.Match(pattern, key); ok {
+			matched = append(matched, key)
+		}
+	}
+	sort.Strings(matched)
+	cmd.(*redis.ScanCmd).SetVal(matched, 0)
+	return nil
+}
+
+// xadd - XADD key [NOMKSTREAM] [MAXLEN [~] count] * field value [field value ...].
+func (s *Store) xadd(cmd redis.Cmder, args []string) error {
+	maxLen := 0
+	i := 2
+	for ; i < len(args); i++ {
+		switch args[i] {
+		case "nomkstream":
+			continue
+		case "maxlen":
+			i++
+			if args[i] == "~" || args[i] == "=" {
+				i++
+			}
+			maxLen, _ = strconv.Atoi(args[i])
+			continue
+		}
+		break
+	}
+	if args[i] != "*" {
+		return fmt.Errorf("redistest: xadd only generates ids, got %q", args[i])
+	}
+
+	fields := map[string]interface{}{}
+	for j := i + 1; j+1 < len(args); j += 2 {
+		fields[args[j]] = args[j+1]
+	}
+
+	entries := s.stream(args[1])
+	entries.lastID++
+	id := formatID(entries.lastID)
+	entries.entries = append(entries.entries, redis.XMessage{ID: id, Values: fields})
+	if maxLen > 0 && len(entries.entries) > maxLen {
+		entries.entries = entries.entries[len(entries.entries)-maxLen:]
+	}
+	cmd.(*redis.StringCmd).SetVal(id)
+	return nil
+}
+
+func (s *Store) stream(name string) *stream {
+	if s.streams[name] == nil {
+		s.streams[name] = &stream{groups: map[string]*group{}}
+	}
+	return s.streams[name]
+}
+
+// xgroup - XGROUP CREATE key group id [MKSTREAM].
+func (s *Store) xgroup(cmd redis.Cmder, args []string) error {
+	if args[1] != "create" {
+		return fmt.Errorf("redistest: unsupported xgroup subcommand %q", args[1])
+	}
+	if s.streams[args[2]] == nil && (len(args) < 6 || args[5] != "mkstream") {
+		return errors.New("ERR The XGROUP subcommand requires the key to exist")
+	}
+
+	entries := s.stream(args[2])
+	if entries.groups[args[3]] != nil {
+		return errors.New("BUSYGROUP Consumer Group name already exists")
+	}
+	lastDelivered := entries.lastID
+	if args[4] != "$" {
+		lastDelivered = parseID(args[4])
+	}
+	entries.groups[args[3]] = &group{lastDelivered: lastDelivered, pending: map[string]*pendingEntry{}}
+	cmd.(*redis.StatusCmd).SetVal("OK")
+	return nil
+}
+
+// xreadgroup - XREADGROUP GROUP group consumer [COUNT count] [BLOCK ms] STREAMS
+// key [key ...] > [> ...]. Only new entries are read.
+func (s *Store) xreadgroup(cmd redis.Cmder, args []string) error {
+	groupName, consumer := args[2], args[3]
+	count := 0
+	i := 4
+	for ; args[i] != "streams"; i++ {
+		if args[i] == "count" {
+			count, _ = strconv.Atoi(args[i+1])
+			i++
+		} else if args[i] == "block" {
+			i++
+		}
+	}
+	keys := args[i+1:]
+	names := keys[:len(keys)/2]
+
+	result := []redis.XStream{}
+	for _, name := range names {
+		entries := s.streams[name]
+		if entries == nil || entries.groups[groupName] == nil {
+			return fmt.Errorf("NOGROUP No such key '%s' or consumer group '%s' in XREADGROUP with GROUP option", name, groupName)
+		}
+		consumerGroup := entries.groups[groupName]
+
+		messages := []redis.XMessage{}
+		for _, entry := range entries.entries {
+			if count > 0 && len(messages) == count {
+				break
+			}
+			if parseID(entry.ID) <= consumerGroup.lastDelivered {
+				continue
+			}
+			consumerGroup.lastDelivered = parseID(entry.ID)
+			consumerGroup.pending[entry.ID] = &pendingEntry{consumer: consumer, deliveredOn: s.now, deliveries: 1}
+			messages = append(messages, entry)
+		}
+		if len(messages) > 0 {
+			result = append(result, redis.XStream{Stream: name, Messages: messages})
+		}
+	}
+	if len(result) == 0 {
+		return redis.Nil
+	}
+	cmd.(*redis.XStreamSliceCmd).SetVal(result)
+	return nil
+}
+
+// xack - XACK key group id [id ...].
+func (s *Store) xack(cmd redis.Cmder, args []string) error {
+	acknowledged := int64(0)
+	if entries := s.streams[args[1]]; entries != nil && entries.groups[args[2]] != nil {
+		for _, id := range args[3:] {
+			if _, ok := entries.groups[args[2]].pending[id]; ok {
+				delete(entries.groups[args[2]].pending, id)
+				acknowledged++
+			}
+		}
+	}
+	cmd.(*redis.IntCmd).SetVal(acknowledged)
+	return nil
+}
+
+// xautoclaim - XAUTOCLAIM key group consumer min-idle-time start [COUNT count].
+// Every idle entry is claimed in one call.
+func (s *Store) xautoclaim(cmd redis.Cmder, args []string) error {
+	entries := s.streams[args[1]]
+	if entries == nil || entries.groups[args[2]] == nil {
+		return fmt.Errorf("NOGROUP No such key '%s' or consumer group '%s'", args[1], args[2])
+	}
+	consumerGroup := entries.groups[args[2]]
+	minIdle, _ := strconv.ParseInt(args[4], 10, 64)
+	start := parseID(args[5])
+
+	claimed := []redis.XMessage{}
+	for _, entry := range entries.entries {
+		pending := consumerGroup.pending[entry.ID]
+		if pending == nil || parseID(entry.ID) < start || s.now.Sub(pending.deliveredOn) < time.Duration(minIdle)*time.Millisecond {
+			continue
+		}
+		pending.consumer = args[3]
+		pending.deliveredOn = s.now
+		pending.deliveries++
+		claimed = append(claimed, entry)
+	}
+	cmd.(*redis.XAutoClaimCmd).SetVal(claimed, "0-0")
+	return nil
+}
+
+// xpending - XPENDING key group start end count, the extended form.
+func (s *Store) xpending(cmd redis.Cmder, args []string) error {
+	if len(args) < 6 {
+		return errors.New("redistest: only the extended xpending form is supported")
+	}
+	cmd.(*redis.XPendingExtCmd).SetVal(s.pending(args[1], args[2], args[3], args[4]))
+	return nil
+}
+
+func (s *Store) pending(name string, groupName string, start string, end string) []redis.XPendingExt {
+	entries := s.streams[name]
+	if entries == nil || entries.groups[groupName] == nil {
+		return nil
+	}
+
+	pending := []redis.XPendingExt{}
+	for _, entry := range entries.entries {
+		held := entries.groups[groupName].pending[entry.ID]
+		if held == nil || !inRange(entry.ID, start, end) {
+			continue
+		}
+		pending = append(pending, redis.XPendingExt{
+			ID:         entry.ID,
+			Consumer:   held.consumer,
+			Idle:       s.now.Sub(held.deliveredOn),
+			RetryCount: held.deliveries,
+		})
+	}
+	return pending
+}
+
+// xrange - XRANGE key start end [COUNT count], or XREVRANGE key end start [COUNT count].
+func (s *Store) xrange(cmd redis.Cmder, args []string) error {
+	start, end := args[2], args[3]
+	if cmd.Name() == "xrevrange" {
+		start, end = end, start
+	}
+	count := 0
+	if len(args) > 5 && args[4] == "count" {
+		count, _ = strconv.Atoi(args[5])
+	}
+
+	messages := []redis.XMessage{}
+	if entries := s.streams[args[1]]; entries != nil {
+		for _, entry := range entries.entries {
+			if inRange(entry.ID, start, end) {
+				messages = append(messages, entry)
+			}
+		}
+	}
+	if cmd.Name() == "xrevrange" {
+		for i, j := 0, len(messages)-1; i < j; i, j = i+1, j-1 {
+			messages[i], messages[j] = messages[j], messages[i]
+		}
+	}
+	if count > 0 && len(messages) > count {
+		messages = messages[:count]
+	}
+	cmd.(*redis.XMessageSliceCmd).SetVal(messages)
+	return nil
+}
+
+// xdel - XDEL key id [id ...].
+func (s *Store) xdel(cmd redis.Cmder, args []string) error {
+	deleted := int64(0)
+	if entries := s.streams[args[1]]; entries != nil {
+		kept := entries.entries[:0]
+		for _, entry := range entries.entries {
+			if contains(args[2:], entry.ID) {
+				deleted++
+				continue
+			}
+			kept = append(kept, entry)
+		}
+		entries.entries = kept
+	}
+	cmd.(*redis.IntCmd).SetVal(deleted)
+	return nil
+}
+
+func stringArgs(args []interface{}) []string {
+	strs := make([]string, len(args))
+	for i, arg := range args {
+		strs[i] = fmt.Sprint(arg)
+	}
+	strs[0] = strings.ToLower(strs[0])
+	return strs
+}
+
+func formatID(sequence int64) string {
+	return strconv.FormatInt(sequence, 10) + "-0"
+}
+
+// parseID - the sequence of an id this store generated.
+func parseID(id string) int64 {
+	sequence, _ := strconv.ParseInt(strings.SplitN(id, "-", 2)[0], 10, 64)
+	return sequence
+}
+
+func inRange(id string, start string, end string) bool {
+	sequence := parseID(id)
+	return (start == "-" || sequence >= parseID(start)) && (end == "+" || sequence <= parseID(end))
+}
+
+func contains(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
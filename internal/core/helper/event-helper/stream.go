@@ -0,0 +1,250 @@
+package helper
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+	configuration "walls-user-service/internal/core/helper/configuration-helper"
+	logger "walls-user-service/internal/core/helper/log-helper"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var (
+	streamPayloadField  = "payload"
+	streamReadCount     = int64(10)
+	streamBlock         = 5 * time.Second
+	streamDiscoverEvery = 30 * time.Second
+	defaultClaimIdle    = 60 * time.Second
+)
+
+// publishToStream - appends the event to the stream named after the channel,
+// so events outlive a consumer restart.
+func (r *RedisClient) publishToStream(ctx context.Context, stream string, eventBytes []byte) error {
+	args := &redis.XAddArgs{
+		Stream: stream,
+		Values: map[string]interface{}{streamPayloadField: string(eventBytes)},
+	}
+	if maxLen, err := strconv.ParseInt(configuration.ServiceConfiguration.EBStreamMaxLen, 10, 64); err == nil && maxLen > 0 {
+		args.MaxLen = maxLen
+		args.Approx = true
+	}
+
+	return r.client.XAdd(ctx, args).Err()
+}
+
+// subscribeToStream - consumes every stream matching the channel pattern as a
+// member of the service's consumer group. Entries are acknowledged only once
+// the handler succeeds; entries left pending by dead consumers are reclaimed.
+func (r *RedisClient) subscribeToStream(ctx context.Context, pattern string, eventHandler EventHandler) error {
+	group := consumerGroup()
+	consumer := consumerName()
+	claimIdle := claimIdle()
+
+	streams := map[string]bool{}
+	var lastDiscovery, lastClaim time.Time
+
+	for {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		if time.Since(lastDiscovery) >= streamDiscoverEvery {
+			if err := r.discoverStreams(ctx, pattern, group, streams); err != nil {
+				logger.LogEvent("ERROR", "Error discovering streams for "+pattern+": "+err.Error())
+			}
+			lastDiscovery = time.Now()
+		}
+
+		if len(streams) == 0 {
+			time.Sleep(streamBlock)
+			continue
+		}
+
+		if time.Since(lastClaim) >= claimIdle {
+			for stream := range streams {
+				r.reclaimPending(ctx, stream, group, consumer, claimIdle, eventHandler)
+			}
+			lastClaim = time.Now()
+		}
+
+		keys := make([]string, 0, len(streams)*2)
+		for stream := range streams {
+			keys = append(keys, stream)
+		}
+		for range streams {
+			keys = append(keys, ">")
+		}
+
+		result, err := r.client.XReadGroup(ctx, &redis.XReadGroupArgs{
+			Group:    group,
+			Consumer: consumer,
+			Streams:  keys,
+			Count:    streamReadCount,
+			Block:    streamBlock,
+		}).Result()
+		if err != nil {
+			if errors.Is(err, redis.Nil) {
+				continue
+			}
+			if strings.HasPrefix(err.Error(), "NOGROUP") {
+				lastDiscovery = time.Time{}
+				continue
+			}
+			logger.LogEvent("ERROR", "Error reading from streams: "+err.Error())
+			time.Sleep(streamBlock)
+			continue
+		}
+
+		for _, stream := range result {
+			for _, message := range stream.Messages {
+				r.handleStreamMessage(ctx, stream.Stream, group, message, 1, eventHandler)
+			}
+		}
+	}
+}
+
+// discoverStreams - registers the consumer group on each stream matching the
+// pattern. Groups start from the beginning of the stream so entries written
+// before the first subscription are still delivered.
+func (r *RedisClient) discoverStreams(ctx context.Context, pattern string, group string, streams map[string]bool) error {
+	var names []string
+	if strings.ContainsAny(pattern, "*?[") {
+		var cursor uint64
+		for {
+			keys, next, err := r.client.ScanType(ctx, cursor, pattern, 100, "stream").Result()
+			if err != nil {
+				return err
+			}
+			names = append(names, keys...)
+			cursor = next
+			if cursor == 0 {
+				break
+			}
+		}
+	} else {
+		names = append(names, pattern)
+	}
+
+	for _, name := range names {
+		if streams[name] {
+			continue
+		}
+		err := r.client.XGroupCreateMkStream(ctx, name, group, "0").Err()
+		if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+			return err
+		}
+		logger.LogEvent("INFO", "Consuming stream "+name+" as group "+group)
+		streams[name] = true
+	}
+	return nil
+}
+
+// reclaimPending - takes over entries left unacknowledged for the idle window,
+// whether their consumer died or their handler failed, and processes them
+// again. A failed entry is retried this way rather than while it blocks the
+// consumer.
+func (r *RedisClient) reclaimPending(ctx context.Context, stream string, group string, consumer string, minIdle time.Duration, eventHandler EventHandler) {
+	start := "0-0"
+	for {
+		messages, next, err := r.client.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+			Stream:   stream,
+			Group:    group,
+			Consumer: consumer,
+			MinIdle:  minIdle,
+			Start:    start,
+			Count:    streamReadCount,
+		}).Result()
+		if err != nil {
+			logger.LogEvent("ERROR", "Error reclaiming pending entries on "+stream+": "+err.Error())
+			return
+		}
+
+		for _, message := range messages {
+			logger.LogEvent("INFO", "Reclaimed pending entry "+message.ID+" on "+stream)
+			r.handleStreamMessage(ctx, stream, group, message, r.deliveryCount(ctx, stream, group, message.ID), eventHandler)
+		}
+
+		if next == "0-0" || len(messages) == 0 {
+			return
+		}
+		start = next
+	}
+}
+
+// deliveryCount - how many times the entry has been delivered, including the
+// claim that just delivered it again.
+func (r *RedisClient) deliveryCount(ctx context.Context, stream string, group string, id string) int {
+	pending, err := r.client.XPendingExt(ctx, &redis.XPendingExtArgs{
+		Stream: stream,
+		Group:  group,
+		Start:  id,
+		End:    id,
+		Count:  1,
+	}).Result()
+	if err != nil || len(pending) == 0 {
+		// The entry was reclaimed, so it has been delivered at least twice.
+		return 2
+	}
+	return int(pending[0].RetryCount)
+}
+
+func (r *RedisClient) handleStreamMessage(ctx context.Context, stream string, group string, message redis.XMessage, attempt int, eventHandler EventHandler) {
+	payload, _ := message.Values[streamPayloadField].(string)
+
+	var eventData interface{}
+	err := json.Unmarshal([]byte(payload), &eventData)
+	if err != nil {
+		// An undecodable entry will never succeed, so it is acknowledged
+		// rather than left to be reclaimed forever.
+		logger.LogEvent("ERROR", "Error decoding event "+message.ID+" on "+stream+": "+err.Error())
+		r.acknowledge(ctx, stream, group, message.ID)
+		return
+	}
+
+	err = eventHandler(withDeliveryAttempt(withEventChannel(ctx, stream), attempt), eventData)
+	if err != nil {
+		// Left pending, to be delivered again once reclaimPending finds it idle.
+		logger.LogEvent("ERROR", "Error handling event "+message.ID+" on "+stream+": "+err.Error())
+		return
+	}
+
+	r.acknowledge(ctx, stream, group, message.ID)
+}
+
+func (r *RedisClient) acknowledge(ctx context.Context, stream string, group string, id string) {
+	err := r.client.XAck(ctx, stream, group, id).Err()
+	if err != nil {
+		logger.LogEvent("ERROR", "Error acknowledging event "+id+" on "+stream+": "+err.Error())
+	}
+}
+
+func consumerGroup() string {
+	if configuration.ServiceConfiguration.EBConsumerGroup != "" {
+		return configuration.ServiceConfiguration.EBConsumerGroup
+	}
+	return configuration.ServiceConfiguration.ServiceName
+}
+
+func consumerName() string {
+	if configuration.ServiceConfiguration.EBConsumerName != "" {
+		return configuration.ServiceConfiguration.EBConsumerName
+	}
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		return configuration.ServiceConfiguration.ServiceName
+	}
+	return hostname
+}
+
+func claimIdle() time.Duration {
+	seconds, err := strconv.Atoi(configuration.ServiceConfiguration.EBClaimIdle)
+	if err != nil || seconds <= 0 {
+		return defaultClaimIdle
+	}
+	return time.Duration(seconds) * time.Second
+}
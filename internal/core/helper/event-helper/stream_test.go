@@ -0,0 +1,158 @@
+package helper
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	configuration "walls-user-service/internal/core/helper/configuration-helper"
+	"walls-user-service/internal/core/helper/event-helper/redistest"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// withEventBus - configures the event bus for a test, restoring it afterwards.
+func withEventBus(t *testing.T, transport string) {
+	t.Helper()
+	saved := configuration.ServiceConfiguration
+	configuration.ServiceConfiguration.EBTransport = transport
+	configuration.ServiceConfiguration.EBConsumerGroup = "walls-user-service"
+	configuration.ServiceConfiguration.EBConsumerName = "consumer-1"
+	configuration.ServiceConfiguration.EBClaimIdle = "60"
+	t.Cleanup(func() { configuration.ServiceConfiguration = saved })
+}
+
+func TestEventsArePublishedOnStreamsUnlessPubSubIsSelected(t *testing.T) {
+	for transport, onStream := range map[string]bool{"": true, "streams": true, "STREAMS": true, "pubsub": false} {
+		withEventBus(t, transport)
+		client, store := redistest.NewClient()
+
+		if err := NewRedisClient(client).PublishToChannel(context.Background(), "USERCREATEDEVENT", []byte(`{"EventReference":"event-1"}`)); err != nil {
+			t.Fatal(err)
+		}
+		if published := len(store.Entries("USERCREATEDEVENT")) == 1; published != onStream {
+			t.Errorf("transport %q: expected the event on a stream to be %v", transport, onStream)
+		}
+		if published := len(store.Published()) == 1; published == onStream {
+			t.Errorf("transport %q: expected the event on pub/sub to be %v", transport, !onStream)
+		}
+	}
+}
+
+func TestStreamEntriesAreAcknowledgedOnceHandled(t *testing.T) {
+	withEventBus(t, StreamsTransport)
+	client, store := redistest.NewClient()
+	redisClient := NewRedisClient(client)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stream := "BANKVERIFIEDEVENT"
+	if err := client.XAdd(ctx, &redis.XAddArgs{Stream: stream, Values: map[string]interface{}{streamPayloadField: "not json"}}).Err(); err != nil {
+		t.Fatal(err)
+	}
+	for _, payload := range []string{`{"EventReference":"event-1"}`, `{"EventReference":"event-2"}`} {
+		if err := redisClient.PublishToChannel(ctx, stream, []byte(payload)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	type delivery struct {
+		eventReference string
+		channel        string
+		attempt        int
+	}
+	deliveries := make(chan delivery, 10)
+	eventHandler := func(ctx context.Context, event interface{}) error {
+		eventReference := event.(map[string]interface{})["EventReference"].(string)
+		deliveries <- delivery{eventReference, EventChannel(ctx), DeliveryAttempt(ctx)}
+		if eventReference == "event-2" && DeliveryAttempt(ctx) == 1 {
+			return errors.New("handler failed")
+		}
+		return nil
+	}
+
+	consumed := make(chan error, 1)
+	go func() { consumed <- redisClient.subscribeToStream(ctx, "BANKVERIFIED*", eventHandler) }()
+	for _, expected := range []delivery{{"event-1", stream, 1}, {"event-2", stream, 1}} {
+		select {
+		case got := <-deliveries:
+			if got != expected {
+				t.Errorf("expected delivery %+v, got %+v", expected, got)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for %s", expected.eventReference)
+		}
+	}
+	cancel()
+	if err := <-consumed; !errors.Is(err, context.Canceled) {
+		t.Errorf("expected the consumer to stop with its context, got %v", err)
+	}
+
+	// The undecodable entry and the handled one are acknowledged; the failed one
+	// is left pending to be delivered again.
+	pending := store.Pending(stream, "walls-user-service")
+	if len(pending) != 1 || pending[0].ID != store.Entries(stream)[2].ID || pending[0].Consumer != "consumer-1" {
+		t.Fatalf("expected only the failed entry to be pending, got %+v", pending)
+	}
+
+	// Not yet idle for the claim window, so not reclaimed.
+	redisClient.reclaimPending(context.Background(), stream, "walls-user-service", "consumer-2", claimIdle(), eventHandler)
+	if len(deliveries) != 0 {
+		t.Fatalf("expected an entry idle for less than the claim window to stay with its consumer")
+	}
+
+	store.Advance(claimIdle())
+	redisClient.reclaimPending(context.Background(), stream, "walls-user-service", "consumer-2", claimIdle(), eventHandler)
+	select {
+	case got := <-deliveries:
+		if expected := (delivery{"event-2", stream, 2}); got != expected {
+			t.Errorf("expected the failed entry to be redelivered as %+v, got %+v", expected, got)
+		}
+	default:
+		t.Fatal("expected the idle entry to be reclaimed")
+	}
+	if pending := store.Pending(stream, "walls-user-service"); len(pending) != 0 {
+		t.Errorf("expected the reclaimed entry to be acknowledged once handled, got %+v", pending)
+	}
+}
+
+func TestStreamsAreDiscoveredOnceWithAGroupFromTheirStart(t *testing.T) {
+	withEventBus(t, StreamsTransport)
+	client, store := redistest.NewClient()
+	redisClient := NewRedisClient(client)
+	ctx := context.Background()
+
+	for _, stream := range []string{"OTPVALIDATEDEVENT:create_user", "OTPVALIDATEDEVENT:bind_device", "PHOTOVERIFIEDEVENT"} {
+		if err := redisClient.PublishToChannel(ctx, stream, []byte(`{}`)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	streams := map[string]bool{}
+	for i := 0; i < 2; i++ {
+		if err := redisClient.discoverStreams(ctx, "OTPVALIDATEDEVENT*", "walls-user-service", streams); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(streams) != 2 || !streams["OTPVALIDATEDEVENT:create_user"] || !streams["OTPVALIDATEDEVENT:bind_device"] {
+		t.Errorf("expected both otp streams to be consumed, got %v", streams)
+	}
+
+	// Entries published before the group was created are still delivered.
+	read, err := client.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group:    "walls-user-service",
+		Consumer: "consumer-1",
+		Streams:  []string{"OTPVALIDATEDEVENT:create_user", ">"},
+	}).Result()
+	if err != nil || len(read) != 1 || len(read[0].Messages) != 1 {
+		t.Errorf("expected the entry published before discovery to be read, got %+v, %v", read, err)
+	}
+
+	// An exact name is consumed even before anything is published on it.
+	if err := redisClient.discoverStreams(ctx, "TIERCONFIGUPDATEDEVENT", "walls-user-service", streams); err != nil {
+		t.Fatal(err)
+	}
+	if len(streams) != 3 || !streams["TIERCONFIGUPDATEDEVENT"] || store.Entries("TIERCONFIGUPDATEDEVENT") == nil {
+		t.Errorf("expected the named stream to be created and consumed, got %v", streams)
+	}
+}
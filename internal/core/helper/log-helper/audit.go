@@ -0,0 +1,58 @@
+package helper
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+	"time"
+	configuration "walls-user-service/internal/core/helper/configuration-helper"
+)
+
+var auditLog *log.Logger
+
+// AuditEntry - a security decision worth keeping apart from the request log.
+type AuditEntry struct {
+	TimeStamp     string   `json:"@timestamp"`
+	AppName       string   `json:"app_name"`
+	Action        string   `json:"action"`
+	Subject       string   `json:"subject"`
+	Roles         []string `json:"roles"`
+	Method        string   `json:"method"`
+	Route         string   `json:"route"`
+	Path          string   `json:"path"`
+	Reason        string   `json:"reason"`
+	CorrelationId string   `json:"X-Correlation-Id"`
+	ForwardedFor  string   `json:"X-Forwarded-For"`
+}
+
+// InitializeAuditLog - opens the audit log file. Until it is opened, or when no
+// file is configured, audit entries go to the service log.
+func InitializeAuditLog() {
+	config := configuration.ServiceConfiguration
+	if config.AuditLogFile == "" {
+		return
+	}
+	_ = os.Mkdir(config.LogDir, os.ModePerm)
+
+	f, err := os.OpenFile(config.LogDir+config.AuditLogFile, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0666)
+	if err != nil {
+		log.Fatalf("error opening audit file: %v", err)
+	}
+	auditLog = log.New(f, "", 0)
+}
+
+func LogAudit(entry AuditEntry) {
+	entry.TimeStamp = time.Now().Format(time.RFC3339)
+	entry.AppName = configuration.ServiceConfiguration.ServiceName
+	if auditLog == nil {
+		LogEvent("AUDIT", entry)
+		return
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		LogEvent("ERROR", "Failed to write audit entry: "+err.Error())
+		return
+	}
+	auditLog.Printf("%s\n", data)
+}
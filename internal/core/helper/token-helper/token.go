@@ -0,0 +1,247 @@
+package helper
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidToken = errors.New("invalid token")
+	ErrExpiredToken = errors.New("token has expired")
+)
+
+// Options - how tokens are verified. Key verifies HS256 tokens and the JSON Web
+// Key Set in JWKSFile verifies RS256 tokens; either may be left empty. A token
+// older than MaxAge, counted from its iat claim, is refused even before it
+// expires; zero leaves expiry to the exp claim alone.
+type Options struct {
+	Key      string
+	JWKSFile string
+	Audience string
+	Issuer   string
+	MaxAge   time.Duration
+	Leeway   time.Duration
+}
+
+// Verifier - checks the signature and registered claims of a JWT.
+type Verifier struct {
+	options Options
+	rsaKeys map[string]*rsa.PublicKey
+	now     func() time.Time
+}
+
+type header struct {
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid"`
+}
+
+type registeredClaims struct {
+	Issuer    string   `json:"iss"`
+	Subject   string   `json:"sub"`
+	Audience  audience `json:"aud"`
+	ExpiresAt *int64   `json:"exp"`
+	NotBefore *int64   `json:"nbf"`
+	IssuedAt  *int64   `json:"iat"`
+}
+
+// audience - the aud claim, a single string or a list of strings.
+type audience []string
+
+func (a *audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = audience{single}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*a = list
+	return nil
+}
+
+type jsonWebKeySet struct {
+	Keys []struct {
+		KeyType   string `json:"kty"`
+		KeyID     string `json:"kid"`
+		Use       string `json:"use"`
+		Algorithm string `json:"alg"`
+		Modulus   string `json:"n"`
+		Exponent  string `json:"e"`
+	} `json:"keys"`
+}
+
+// NewVerifier - a verifier for the given options. The JWKS file, when given, is
+// read once here.
+func NewVerifier(options Options) (*Verifier, error) {
+	verifier := &Verifier{options: options, rsaKeys: map[string]*rsa.PublicKey{}, now: time.Now}
+	if options.JWKSFile != "" {
+		source, err := os.ReadFile(options.JWKSFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading jwks file: %w", err)
+		}
+		verifier.rsaKeys, err = parseJWKS(source)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if options.Key == "" && len(verifier.rsaKeys) == 0 {
+		return nil, errors.New("no token key or jwks file configured")
+	}
+	return verifier, nil
+}
+
+func parseJWKS(source []byte) (map[string]*rsa.PublicKey, error) {
+	var keySet jsonWebKeySet
+	if err := json.Unmarshal(source, &keySet); err != nil {
+		return nil, fmt.Errorf("parsing jwks file: %w", err)
+	}
+
+	keys := map[string]*rsa.PublicKey{}
+	for _, key := range keySet.Keys {
+		if key.KeyType != "RSA" || (key.Use != "" && key.Use != "sig") {
+			continue
+		}
+		modulus, err := base64.RawURLEncoding.DecodeString(key.Modulus)
+		if err != nil {
+			return nil, fmt.Errorf("jwks key %q: invalid modulus: %w", key.KeyID, err)
+		}
+		exponent, err := base64.RawURLEncoding.DecodeString(key.Exponent)
+		if err != nil {
+			return nil, fmt.Errorf("jwks key %q: invalid exponent: %w", key.KeyID, err)
+		}
+		keys[key.KeyID] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(modulus),
+			E: int(new(big.Int).SetBytes(exponent).Int64()),
+		}
+	}
+	return keys, nil
+}
+
+// Verify - checks the token's signature, expiry, issuer and audience, then
+// decodes its claims into claims. The subject is returned.
+func (v *Verifier) Verify(token string, claims interface{}) (string, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return "", ErrInvalidToken
+	}
+
+	var tokenHeader header
+	if err := decodeSegment(parts[0], &tokenHeader); err != nil {
+		return "", ErrInvalidToken
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", ErrInvalidToken
+	}
+	if err := v.verifySignature(tokenHeader, parts[0]+"."+parts[1], signature); err != nil {
+		return "", err
+	}
+
+	var registered registeredClaims
+	if err := decodeSegment(parts[1], &registered); err != nil {
+		return "", ErrInvalidToken
+	}
+	if err := v.verifyClaims(registered); err != nil {
+		return "", err
+	}
+
+	if claims != nil {
+		if err := decodeSegment(parts[1], claims); err != nil {
+			return "", ErrInvalidToken
+		}
+	}
+	return registered.Subject, nil
+}
+
+func (v *Verifier) verifySignature(tokenHeader header, signed string, signature []byte) error {
+	switch tokenHeader.Algorithm {
+	case "HS256":
+		if v.options.Key == "" {
+			return fmt.Errorf("%w: HS256 is not accepted", ErrInvalidToken)
+		}
+		mac := hmac.New(sha256.New, []byte(v.options.Key))
+		mac.Write([]byte(signed))
+		if !hmac.Equal(signature, mac.Sum(nil)) {
+			return fmt.Errorf("%w: signature mismatch", ErrInvalidToken)
+		}
+		return nil
+	case "RS256":
+		key, ok := v.rsaKeys[tokenHeader.KeyID]
+		if !ok && tokenHeader.KeyID == "" && len(v.rsaKeys) == 1 {
+			for _, onlyKey := range v.rsaKeys {
+				key, ok = onlyKey, true
+			}
+		}
+		if !ok {
+			return fmt.Errorf("%w: unknown key %q", ErrInvalidToken, tokenHeader.KeyID)
+		}
+		digest := sha256.Sum256([]byte(signed))
+		if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature); err != nil {
+			return fmt.Errorf("%w: signature mismatch", ErrInvalidToken)
+		}
+		return nil
+	}
+	return fmt.Errorf("%w: algorithm %q is not accepted", ErrInvalidToken, tokenHeader.Algorithm)
+}
+
+func (v *Verifier) verifyClaims(claims registeredClaims) error {
+	now := v.now()
+	leeway := v.options.Leeway
+
+	if claims.Subject == "" {
+		return fmt.Errorf("%w: no subject", ErrInvalidToken)
+	}
+	if claims.ExpiresAt == nil {
+		return fmt.Errorf("%w: no expiry", ErrInvalidToken)
+	}
+	if now.After(time.Unix(*claims.ExpiresAt, 0).Add(leeway)) {
+		return ErrExpiredToken
+	}
+	if claims.NotBefore != nil && now.Add(leeway).Before(time.Unix(*claims.NotBefore, 0)) {
+		return fmt.Errorf("%w: not valid yet", ErrInvalidToken)
+	}
+	if v.options.MaxAge > 0 {
+		if claims.IssuedAt == nil {
+			return fmt.Errorf("%w: no issue time", ErrInvalidToken)
+		}
+		if now.After(time.Unix(*claims.IssuedAt, 0).Add(v.options.MaxAge + leeway)) {
+			return ErrExpiredToken
+		}
+	}
+	if v.options.Issuer != "" && claims.Issuer != v.options.Issuer {
+		return fmt.Errorf("%w: unexpected issuer %q", ErrInvalidToken, claims.Issuer)
+	}
+	if v.options.Audience != "" && !contains(claims.Audience, v.options.Audience) {
+		return fmt.Errorf("%w: not issued for %q", ErrInvalidToken, v.options.Audience)
+	}
+	return nil
+}
+
+func decodeSegment(segment string, value interface{}) error {
+	decoded, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(decoded, value)
+}
+
+func contains(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,148 @@
+package helper
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+var testNow = time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+func testClaims(overrides map[string]interface{}) map[string]interface{} {
+	claims := map[string]interface{}{
+		"sub":          "d5c1b7a2-7a4e-4c1e-9a51-0f2b8e6d4c3a",
+		"iss":          "https://identity.walls",
+		"aud":          []string{"walls"},
+		"iat":          testNow.Add(-time.Minute).Unix(),
+		"exp":          testNow.Add(time.Hour).Unix(),
+		"phone_number": "+2348012345678",
+	}
+	for name, value := range overrides {
+		if value == nil {
+			delete(claims, name)
+			continue
+		}
+		claims[name] = value
+	}
+	return claims
+}
+
+func encodeSegment(t *testing.T, value interface{}) string {
+	t.Helper()
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(encoded)
+}
+
+func signHS256(t *testing.T, key string, claims map[string]interface{}) string {
+	t.Helper()
+	signed := encodeSegment(t, map[string]string{"alg": "HS256", "typ": "JWT"}) + "." + encodeSegment(t, claims)
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(signed))
+	return signed + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func signRS256(t *testing.T, key *rsa.PrivateKey, keyID string, claims map[string]interface{}) string {
+	t.Helper()
+	signed := encodeSegment(t, map[string]string{"alg": "RS256", "typ": "JWT", "kid": keyID}) + "." + encodeSegment(t, claims)
+	digest := sha256.Sum256([]byte(signed))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func testVerifier(t *testing.T, options Options) *Verifier {
+	t.Helper()
+	verifier, err := NewVerifier(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	verifier.now = func() time.Time { return testNow }
+	return verifier
+}
+
+func TestVerifyHS256(t *testing.T) {
+	verifier := testVerifier(t, Options{Key: "secret", Audience: "walls", Issuer: "https://identity.walls"})
+
+	var claims struct {
+		Phone string `json:"phone_number"`
+	}
+	subject, err := verifier.Verify(signHS256(t, "secret", testClaims(nil)), &claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if subject != "d5c1b7a2-7a4e-4c1e-9a51-0f2b8e6d4c3a" || claims.Phone != "+2348012345678" {
+		t.Fatalf("unexpected subject %q and claims %+v", subject, claims)
+	}
+}
+
+func TestVerifyRS256FromJWKS(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keySet := map[string]interface{}{"keys": []map[string]string{{
+		"kty": "RSA",
+		"kid": "key-1",
+		"use": "sig",
+		"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}}}
+	source, err := json.Marshal(keySet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jwksFile := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(jwksFile, source, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	verifier := testVerifier(t, Options{JWKSFile: jwksFile, Audience: "walls"})
+	if _, err := verifier.Verify(signRS256(t, key, "key-1", testClaims(nil)), nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := verifier.Verify(signRS256(t, key, "key-2", testClaims(nil)), nil); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("expected an unknown key to be refused, got %v", err)
+	}
+	if _, err := verifier.Verify(signHS256(t, "secret", testClaims(nil)), nil); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("expected HS256 to be refused without a key, got %v", err)
+	}
+}
+
+func TestVerifyRefusesInvalidTokens(t *testing.T) {
+	verifier := testVerifier(t, Options{Key: "secret", Audience: "walls", Issuer: "https://identity.walls", MaxAge: 30 * time.Minute})
+
+	for name, test := range map[string]struct {
+		token    string
+		expected error
+	}{
+		"wrong key":       {signHS256(t, "other", testClaims(nil)), ErrInvalidToken},
+		"expired":         {signHS256(t, "secret", testClaims(map[string]interface{}{"exp": testNow.Add(-time.Second).Unix()})), ErrExpiredToken},
+		"too old":         {signHS256(t, "secret", testClaims(map[string]interface{}{"iat": testNow.Add(-time.Hour).Unix()})), ErrExpiredToken},
+		"no expiry":       {signHS256(t, "secret", testClaims(map[string]interface{}{"exp": nil})), ErrInvalidToken},
+		"no subject":      {signHS256(t, "secret", testClaims(map[string]interface{}{"sub": nil})), ErrInvalidToken},
+		"not yet valid":   {signHS256(t, "secret", testClaims(map[string]interface{}{"nbf": testNow.Add(time.Minute).Unix()})), ErrInvalidToken},
+		"wrong issuer":    {signHS256(t, "secret", testClaims(map[string]interface{}{"iss": "https://elsewhere"})), ErrInvalidToken},
+		"wrong audience":  {signHS256(t, "secret", testClaims(map[string]interface{}{"aud": "other"})), ErrInvalidToken},
+		"unsigned":        {encodeSegment(t, map[string]string{"alg": "none"}) + "." + encodeSegment(t, testClaims(nil)) + ".", ErrInvalidToken},
+		"malformed token": {"not-a-token", ErrInvalidToken},
+	} {
+		if _, err := verifier.Verify(test.token, nil); !errors.Is(err, test.expected) {
+			t.Errorf("%s: expected %v, got %v", name, test.expected, err)
+		}
+	}
+}
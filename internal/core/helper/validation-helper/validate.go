@@ -2,6 +2,7 @@ package helper
 
 import (
 	"reflect"
+	"strings"
 	errorhelper "walls-user-service/internal/core/helper/error-helper"
 	logger "walls-user-service/internal/core/helper/log-helper"
 
@@ -19,6 +20,20 @@ func init() {
 	validate.RegisterValidation("guid", ValidateGUID)
 	validate.RegisterValidation("imei", ValidateIMEI)
 	validate.RegisterValidation("valid_phone", ValidateValidPhone)
+	validate.RegisterTagNameFunc(jsonFieldName)
+}
+
+// jsonFieldName - names fields by their JSON key, so validation errors carry the
+// path a client sent.
+func jsonFieldName(field reflect.StructField) string {
+	name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+	if name == "-" {
+		return ""
+	}
+	if name == "" {
+		return field.Name
+	}
+	return name
 }
 
 func Validate(data interface{}) error {
@@ -36,3 +51,21 @@ func Validate(data interface{}) error {
 	logger.LogEvent("INFO", reflect.TypeOf(data).String()+" Data Validated Successfully...")
 	return nil
 }
+
+// ValidateHeaders - Validate for a struct read from request headers, reporting
+// each failing field by the header named for it in headerNames.
+func ValidateHeaders(data interface{}, headerNames map[string]string) error {
+	logger.LogEvent("INFO", "Validating "+reflect.TypeOf(data).String()+" Headers...")
+	err := validate.Struct(data)
+	if err != nil {
+		var fieldErrors []validator.FieldError
+		logger.LogEvent("ERROR", "Error validating headers: "+err.Error())
+
+		for _, errs := range err.(validator.ValidationErrors) {
+			fieldErrors = append(fieldErrors, errs)
+		}
+		return errorhelper.HeaderErrorArrayToError(fieldErrors, headerNames)
+	}
+	logger.LogEvent("INFO", reflect.TypeOf(data).String()+" Headers Validated Successfully...")
+	return nil
+}
@@ -0,0 +1,140 @@
+package middleware
+
+import (
+	"log"
+	"strconv"
+	"strings"
+	"time"
+	"walls-user-service/internal/core/domain/dto"
+	"walls-user-service/internal/core/domain/shared"
+	configuration "walls-user-service/internal/core/helper/configuration-helper"
+	errorhelper "walls-user-service/internal/core/helper/error-helper"
+	logger "walls-user-service/internal/core/helper/log-helper"
+	tokenhelper "walls-user-service/internal/core/helper/token-helper"
+
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	JWTAuthMode    = "jwt"
+	HeaderAuthMode = "header"
+
+	// CurrentUserKey - the context key the authenticated user is stored under.
+	CurrentUserKey = "current_user"
+
+	tokenLeeway = 30 * time.Second
+)
+
+// tokenClaims - the claims a user's token carries besides its subject. The device
+// is optional; without it the device is read from the request headers. A token
+// without roles is a user's.
+type tokenClaims struct {
+	Phone  string         `json:"phone_number"`
+	Device *dto.DeviceDto `json:"device"`
+	Roles  []string       `json:"roles"`
+}
+
+// Authenticate - verifies the bearer token of every request and stores the user
+// it was issued to under CurrentUserKey. In header mode, meant for local
+// development, the current user is trusted from the X- headers. Roles never
+// come from a header, so a header mode caller is always a user.
+func Authenticate() gin.HandlerFunc {
+	if strings.EqualFold(configuration.ServiceConfiguration.TokenMode, HeaderAuthMode) {
+		logger.LogEvent("INFO", "Authentication is in header mode: the current user is trusted from request headers")
+		return func(c *gin.Context) {
+			currentUser := dto.CurrentUserDto{
+				UserReference: c.GetHeader("X-User-Reference"),
+				Phone:         c.GetHeader("X-Phone"),
+				Device:        headerDevice(c),
+				Roles:         []string{shared.RoleUser},
+			}
+			c.Set(CurrentUserKey, currentUser)
+			c.Next()
+		}
+	}
+
+	verifier, err := tokenhelper.NewVerifier(tokenhelper.Options{
+		Key:      configuration.ServiceConfiguration.TokenKey,
+		JWKSFile: configuration.ServiceConfiguration.TokenJwksFile,
+		Audience: configuration.ServiceConfiguration.TokenAudience,
+		Issuer:   configuration.ServiceConfiguration.TokenIssuer,
+		MaxAge:   tokenMaxAge(),
+		Leeway:   tokenLeeway,
+	})
+	if err != nil {
+		logger.LogEvent("ERROR", "Token verification setup error: "+err.Error())
+		log.Fatal(err)
+	}
+
+	return func(c *gin.Context) {
+		token, ok := bearerToken(c)
+		if !ok {
+			c.AbortWithStatusJSON(401, errorhelper.ErrorMessage(errorhelper.UnauthorizedError, "a bearer token is required"))
+			return
+		}
+
+		var claims tokenClaims
+		subject, err := verifier.Verify(token, &claims)
+		if err != nil {
+			logger.LogEvent("ERROR", "Refused token: "+err.Error())
+			c.AbortWithStatusJSON(401, errorhelper.ErrorMessage(errorhelper.UnauthorizedError, err.Error()))
+			return
+		}
+
+		currentUser := dto.CurrentUserDto{
+			UserReference: subject,
+			Phone:         claims.Phone,
+			Device:        headerDevice(c),
+			Roles:         rolesOrDefault(claims.Roles),
+		}
+		if claims.Device != nil {
+			currentUser.Device = *claims.Device
+		}
+
+		c.Set(CurrentUserKey, currentUser)
+		c.Next()
+	}
+}
+
+func headerDevice(c *gin.Context) dto.DeviceDto {
+	return dto.DeviceDto{
+		Imei:            c.GetHeader("X-Imei"),
+		Type:            c.GetHeader("X-Device-Type"),
+		Brand:           c.GetHeader("X-Device-Brand"),
+		Model:           c.GetHeader("X-Device-Model"),
+		DeviceReference: c.GetHeader("X-Device-Reference"),
+	}
+}
+
+// rolesOrDefault - the given roles, trimmed and lowercased, or the user role
+// when there are none.
+func rolesOrDefault(roles []string) []string {
+	var cleaned []string
+	for _, role := range roles {
+		if role = strings.ToLower(strings.TrimSpace(role)); role != "" {
+			cleaned = append(cleaned, role)
+		}
+	}
+	if len(cleaned) == 0 {
+		return []string{shared.RoleUser}
+	}
+	return cleaned
+}
+
+func bearerToken(c *gin.Context) (string, bool) {
+	scheme, token, found := strings.Cut(c.GetHeader("Authorization"), " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(token) == "" {
+		return "", false
+	}
+	return strings.TrimSpace(token), true
+}
+
+// tokenMaxAge - Token__Expiry in minutes; tokens are refused that long after
+// they were issued. Unset, only the token's own expiry applies.
+func tokenMaxAge() time.Duration {
+	minutes, err := strconv.Atoi(configuration.ServiceConfiguration.TokenExpiry)
+	if err != nil || minutes <= 0 {
+		return 0
+	}
+	return time.Duration(minutes) * time.Minute
+}
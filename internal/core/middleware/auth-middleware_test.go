@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"walls-user-service/internal/core/domain/dto"
+	"walls-user-service/internal/core/domain/shared"
+	configuration "walls-user-service/internal/core/helper/configuration-helper"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestHeaderModeNeverTrustsRolesFromHeaders(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tokenMode := configuration.ServiceConfiguration.TokenMode
+	configuration.ServiceConfiguration.TokenMode = HeaderAuthMode
+	defer func() { configuration.ServiceConfiguration.TokenMode = tokenMode }()
+
+	var currentUser dto.CurrentUserDto
+	router := gin.New()
+	router.Use(Authenticate())
+	router.GET("/", func(c *gin.Context) {
+		currentUser = c.MustGet(CurrentUserKey).(dto.CurrentUserDto)
+	})
+
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+	request.Header.Set("X-User-Reference", "user-1")
+	request.Header.Set("X-Roles", "ops,system")
+	router.ServeHTTP(httptest.NewRecorder(), request)
+
+	if currentUser.UserReference != "user-1" {
+		t.Errorf("expected the user reference from the header, got %q", currentUser.UserReference)
+	}
+	if !reflect.DeepEqual(currentUser.Roles, []string{shared.RoleUser}) {
+		t.Errorf("expected a header mode caller to be a user, got %v", currentUser.Roles)
+	}
+}
@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	errorhelper "walls-user-service/internal/core/helper/error-helper"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HandleErrors - renders the last error a handler attached to the context as an
+// ErrorResponse, with the status its error type maps to.
+func HandleErrors(c *gin.Context) {
+	c.Next()
+
+	if len(c.Errors) == 0 || c.Writer.Written() {
+		return
+	}
+
+	errorResponse := errorhelper.ErrorFrom(c.Errors.Last().Err)
+	if errorResponse.Code == 0 {
+		errorResponse.Code = 500
+	}
+	c.AbortWithStatusJSON(errorResponse.Code, errorResponse)
+}
@@ -0,0 +1,96 @@
+package middleware
+
+import (
+	"fmt"
+	"strings"
+	"walls-user-service/internal/core/domain/dto"
+	"walls-user-service/internal/core/domain/shared"
+	errorhelper "walls-user-service/internal/core/helper/error-helper"
+	logger "walls-user-service/internal/core/helper/log-helper"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Policy - the roles that may call a route. A caller allowed in by the user
+// role alone may only act on their own :user_reference.
+type Policy struct {
+	Roles []string
+}
+
+// Policies - route policies keyed by method and route, "PUT /api/user/:user_reference/enable".
+type Policies map[string]Policy
+
+// Validate - checks every policy names a registered route.
+func (policies Policies) Validate(routes gin.RoutesInfo) error {
+	registered := map[string]bool{}
+	for _, route := range routes {
+		registered[route.Method+" "+route.Path] = true
+	}
+	for route := range policies {
+		if !registered[route] {
+			return fmt.Errorf("policy for unregistered route %q", route)
+		}
+	}
+	return nil
+}
+
+// Authorize - refuses callers whose roles the route's policy does not allow,
+// falling back to defaultPolicy for routes without one. Every refusal is written
+// to the audit log.
+func Authorize(policies Policies, defaultPolicy Policy) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		policy, ok := policies[c.Request.Method+" "+c.FullPath()]
+		if !ok {
+			policy = defaultPolicy
+		}
+
+		var currentUser dto.CurrentUserDto
+		if value, ok := c.Get(CurrentUserKey); ok {
+			currentUser = value.(dto.CurrentUserDto)
+		}
+
+		granted := grantedRoles(currentUser.Roles, policy.Roles)
+		if len(granted) == 0 {
+			deny(c, currentUser, "role not allowed for this route")
+			return
+		}
+
+		userReference := c.Param("user_reference")
+		if len(granted) == 1 && granted[0] == shared.RoleUser && userReference != "" && userReference != currentUser.UserReference {
+			deny(c, currentUser, "user role may only act on its own user reference")
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// grantedRoles - the caller's roles the policy allows.
+func grantedRoles(roles []string, allowed []string) []string {
+	var granted []string
+	for _, role := range roles {
+		for _, allowedRole := range allowed {
+			if role == allowedRole {
+				granted = append(granted, role)
+				break
+			}
+		}
+	}
+	return granted
+}
+
+func deny(c *gin.Context, currentUser dto.CurrentUserDto, reason string) {
+	logger.LogAudit(logger.AuditEntry{
+		Action:        "access_denied",
+		Subject:       currentUser.UserReference,
+		Roles:         currentUser.Roles,
+		Method:        c.Request.Method,
+		Route:         c.FullPath(),
+		Path:          c.Request.URL.Path,
+		Reason:        reason,
+		CorrelationId: c.GetHeader("X-Correlation-ID"),
+		ForwardedFor:  c.GetHeader("X-Forwarded-For"),
+	})
+	logger.LogEvent("ERROR", "Access denied to "+c.Request.Method+" "+c.FullPath()+" for "+currentUser.UserReference+" ("+strings.Join(currentUser.Roles, ",")+"): "+reason)
+	c.AbortWithStatusJSON(403, errorhelper.ErrorMessage(errorhelper.ForbiddenError, reason))
+}
@@ -0,0 +1,97 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"walls-user-service/internal/core/domain/entity"
+	"walls-user-service/internal/core/domain/shared"
+	eto "walls-user-service/internal/core/helper/event-helper/eto"
+	logger "walls-user-service/internal/core/helper/log-helper"
+	ports "walls-user-service/internal/port"
+
+	"github.com/google/uuid"
+)
+
+var DeadLetterService = &deadLetterService{}
+
+type deadLetterService struct {
+	deadLetterRepository ports.DeadLetterRepository
+	outboxRepository     ports.OutboxRepository
+}
+
+func NewDeadLetterService(deadLetterRepository ports.DeadLetterRepository, outboxRepository ports.OutboxRepository) *deadLetterService {
+	DeadLetterService = &deadLetterService{
+		deadLetterRepository: deadLetterRepository,
+		outboxRepository:     outboxRepository,
+	}
+
+	return DeadLetterService
+}
+
+func (service *deadLetterService) GetDeadLetterEvents(ctx context.Context, limit int64) (interface{}, error) {
+	logger.LogEvent("INFO", "Getting dead-lettered events")
+	deadLetterEvents, err := service.deadLetterRepository.GetDeadLetterEvents(ctx, limit)
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to get dead-lettered events: "+err.Error())
+		return nil, err
+	}
+	return deadLetterEvents, nil
+}
+
+func (service *deadLetterService) GetDeadLetterEvent(ctx context.Context, deadLetterReference string) (interface{}, error) {
+	logger.LogEvent("INFO", "Getting dead-lettered event with reference: "+deadLetterReference)
+	deadLetterEvent, err := service.deadLetterRepository.GetDeadLetterEventByReference(ctx, deadLetterReference)
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to get dead-lettered event with reference: "+deadLetterReference)
+		return nil, err
+	}
+	return deadLetterEvent, nil
+}
+
+// ReplayDeadLetterEvent - queues the parked payload on its original channel
+// through the outbox, then removes it from the dead-letter stream. If it fails
+// again it is dead-lettered afresh.
+func (service *deadLetterService) ReplayDeadLetterEvent(ctx context.Context, deadLetterReference string) (interface{}, error) {
+	logger.LogEvent("INFO", "Replaying dead-lettered event with reference: "+deadLetterReference)
+	deadLetterData, err := service.deadLetterRepository.GetDeadLetterEventByReference(ctx, deadLetterReference)
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to get dead-lettered event with reference: "+deadLetterReference)
+		return nil, err
+	}
+	deadLetterEvent := deadLetterData.(entity.DeadLetterEvent)
+
+	if deadLetterEvent.Channel == "" {
+		logger.LogEvent("ERROR", "Dead-lettered event "+deadLetterReference+" has no channel to replay to")
+		return nil, ErrDeadLetterNoChannel
+	}
+
+	envelope, _ := eto.DecodeEvent([]byte(deadLetterEvent.Payload))
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	outboxEvent := entity.OutboxEvent{
+		OutboxReference: uuid.New().String(),
+		EventReference:  deadLetterEvent.EventReference,
+		UserReference:   envelope.EventUserReference,
+		Channel:         deadLetterEvent.Channel,
+		Payload:         deadLetterEvent.Payload,
+		Status:          shared.OutboxPending,
+		CreatedOn:       now,
+		NextAttemptOn:   now,
+	}
+
+	_, err = service.outboxRepository.CreateOutboxEvent(ctx, outboxEvent)
+	if err != nil {
+		logger.LogEvent("ERROR", "failed to queue event for publishing")
+		return nil, err
+	}
+
+	_, err = service.deadLetterRepository.DeleteDeadLetterEvent(ctx, deadLetterReference)
+	if err != nil {
+		// The event is already queued; a second replay would deliver it twice.
+		logger.LogEvent("ERROR", "Failed to remove replayed dead-lettered event with reference: "+deadLetterReference)
+		return nil, err
+	}
+
+	return deadLetterReference, nil
+}
@@ -0,0 +1,60 @@
+package services
+
+import (
+	errorhelper "walls-user-service/internal/core/helper/error-helper"
+)
+
+// Errors the services return for known failures. Each carries the error type
+// that decides its HTTP status and a code clients can switch on.
+var (
+	ErrUserNotFound           = errorhelper.NotFound("USER_NOT_FOUND", "user not found")
+	ErrWallsBadgeNotFound     = errorhelper.NotFound("WALLS_BADGE_NOT_FOUND", "badge not found")
+	ErrNoDefaultWallsBadge    = errorhelper.NotFound("NO_DEFAULT_WALLS_BADGE", "no default wallsbadge found")
+	ErrBankNotFound           = errorhelper.NotFound("BANK_NOT_FOUND", "bank account not found for the user")
+	ErrCardNotFound           = errorhelper.NotFound("CARD_NOT_FOUND", "card not found for the user")
+	ErrPhotoNotFound          = errorhelper.NotFound("PHOTO_NOT_FOUND", "photo not found for the user")
+	ErrDocumentationNotFound  = errorhelper.NotFound("DOCUMENTATION_NOT_FOUND", "documentation not found for the user")
+	ErrTierNotFound           = errorhelper.NotFound("TIER_NOT_FOUND", "tier not found")
+	ErrDeviceNotFound         = errorhelper.NotFound("DEVICE_NOT_FOUND", "device not found for the user")
+	ErrNoPendingDeviceBinding = errorhelper.NotFound("NO_PENDING_DEVICE_BINDING", "no pending binding for this device")
+
+	ErrUserExists              = errorhelper.Conflict("USER_ALREADY_EXISTS", "sorry, user already exists")
+	ErrWallsTagInUse           = errorhelper.Conflict("WALLS_TAG_IN_USE", "this wallstag is already in use")
+	ErrDeviceAlreadyRegistered = errorhelper.Conflict("DEVICE_ALREADY_REGISTERED", "this device is already registered")
+	ErrBankNotVerified         = errorhelper.Conflict("BANK_NOT_VERIFIED", "bank account is not verified")
+	ErrCardNotVerified         = errorhelper.Conflict("CARD_NOT_VERIFIED", "card is not verified")
+	ErrStaleBalanceUpdate      = errorhelper.Conflict("STALE_BALANCE_UPDATE", "balance update is out of order")
+	ErrDeadLetterNoChannel     = errorhelper.Conflict("DEAD_LETTER_NO_CHANNEL", "dead-lettered event has no channel to replay to")
+	ErrLastTrustedDevice       = errorhelper.Conflict("LAST_TRUSTED_DEVICE", "the last trusted device of the user cannot be revoked")
+
+	ErrDeviceNotRegistered     = errorhelper.Forbidden("DEVICE_NOT_REGISTERED", "this device is not registered to this user")
+	ErrPhoneNotRegistered      = errorhelper.Forbidden("PHONE_NOT_REGISTERED", "the phone number is not registered to this user")
+	ErrPhoneMismatch           = errorhelper.Forbidden("PHONE_MISMATCH", "the current user phone number does not match the intended registration phone number")
+	ErrUnknownIdentity         = errorhelper.Forbidden("UNKNOWN_IDENTITY", "unauthorized user: failed to retrieve identity")
+	ErrWalletNotOwned          = errorhelper.Forbidden("WALLET_NOT_OWNED", "wallet does not belong to the user")
+	ErrUnauthorizedTransaction = errorhelper.Forbidden("UNAUTHORIZED_TRANSACTION", "unauthorized transaction")
+
+	ErrInvalidCardExpiry         = errorhelper.Validation("INVALID_CARD_EXPIRY", "invalid expiry month or year")
+	ErrInvalidOtpContact         = errorhelper.Validation("INVALID_OTP_CONTACT", "invalid otp contact")
+	ErrNoUploadedDocuments       = errorhelper.Validation("NO_UPLOADED_DOCUMENTS", "no uploaded documents: No uploaded documents found for this request. Kindly uploaded documents and try again")
+	ErrInvalidVerificationStatus = errorhelper.Validation("INVALID_VERIFICATION_STATUS", "invalid verification status")
+	ErrMissingTierReference      = errorhelper.Validation("MISSING_TIER_REFERENCE", "tier configuration has no tier reference")
+	ErrApprovedTierMissing       = errorhelper.Validation("APPROVED_TIER_MISSING", "approved tier upgrade carries no tier")
+	ErrInvalidCursor             = errorhelper.Validation("INVALID_CURSOR", "the cursor does not belong to this search")
+	ErrInvalidSort               = errorhelper.Validation("INVALID_SORT", "users cannot be sorted by this field")
+
+	ErrInsufficientFunds       = errorhelper.LimitExceeded("INSUFFICIENT_FUNDS", "insufficient funds in sender's wallet")
+	ErrSendingLimitExceeded    = errorhelper.LimitExceeded("SENDING_LIMIT_EXCEEDED", "the transaction amount exceeds the sender's sending limit")
+	ErrWalletLimitExceeded     = errorhelper.LimitExceeded("WALLET_LIMIT_EXCEEDED", "receiver's wallet limit exceeded")
+	ErrReceivingLimitExceeded  = errorhelper.LimitExceeded("RECEIVING_LIMIT_EXCEEDED", "receiver's receiving limit exceeded")
+	ErrCoolingOffLimitExceeded = errorhelper.LimitExceeded("COOLING_OFF_LIMIT_EXCEEDED", "the transaction amount exceeds the limit of a newly bound device")
+)
+
+// lookupError - a failed repository lookup: notFound when nothing matched,
+// otherwise the failure itself.
+func lookupError(err error, notFound errorhelper.DomainError) error {
+	if errorhelper.IsNotFound(err) {
+		return notFound.Wrap(err)
+	}
+	return err
+}
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"walls-user-service/internal/core/domain/dto"
+	"walls-user-service/internal/core/domain/entity"
+	logger "walls-user-service/internal/core/helper/log-helper"
+	ports "walls-user-service/internal/port"
+)
+
+var TierService = &tierService{}
+
+type tierService struct {
+	tierRepository ports.TierRepository
+}
+
+func NewTierService(tierRepository ports.TierRepository) *tierService {
+	TierService = &tierService{
+		tierRepository: tierRepository,
+	}
+
+	return TierService
+}
+
+func (service *tierService) GetTiers(ctx context.Context) (interface{}, error) {
+	logger.LogEvent("INFO", "Getting tiers")
+	tiers, err := service.tierRepository.GetTiers(ctx)
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to get tiers: "+err.Error())
+		return nil, errors.New("failed to retrieve tiers")
+	}
+	return tiers, nil
+}
+
+func (service *tierService) GetTierByReference(ctx context.Context, tierReference string) (interface{}, error) {
+	logger.LogEvent("INFO", "Getting tier with reference: "+tierReference)
+	tier, err := service.tierRepository.GetTierByReference(ctx, tierReference)
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to get tier with reference: "+tierReference)
+		return nil, lookupError(err, ErrTierNotFound)
+	}
+	return tier, nil
+}
+
+// UpdateTierConfig - brings a tier in the catalogue up to date. A change older
+// than the one the catalogue already holds is ignored.
+func (service *tierService) UpdateTierConfig(ctx context.Context, tierConfigDto dto.TierConfigDto) (interface{}, error) {
+	tierReference := tierConfigDto.Tier.TierReference
+	logger.LogEvent("INFO", "Updating tier with reference: "+tierReference)
+	if tierReference == "" {
+		logger.LogEvent("ERROR", "Tier configuration has no tier reference")
+		return nil, ErrMissingTierReference
+	}
+
+	tier := entity.TierConfig{
+		Tier:      tierConfigDto.Tier,
+		IsActive:  tierConfigDto.IsActive,
+		UpdatedOn: catalogueTimestamp(tierConfigDto.UpdatedOn),
+	}
+
+	_, err := service.tierRepository.UpsertTier(ctx, tier)
+	if errors.Is(err, ports.ErrStaleTierUpdate) {
+		logger.LogEvent("INFO", "Ignoring tier configuration for reference: "+tierReference+" older than the catalogue's")
+		return service.tierRepository.GetTierByReference(ctx, tierReference)
+	}
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to update tier with reference: "+tierReference)
+		return nil, errors.New("failed to update tier")
+	}
+
+	return tier, nil
+}
+
+// catalogueTimestamp - the update time of a tier as the catalogue orders it: in
+// RFC3339 UTC, so timestamps compare as strings. A tier without one is updated now.
+func catalogueTimestamp(updatedOn string) string {
+	if updatedOn == "" {
+		return time.Now().UTC().Format(time.RFC3339)
+	}
+	parsed, err := time.Parse(time.RFC3339, updatedOn)
+	if err != nil {
+		return updatedOn
+	}
+	return parsed.UTC().Format(time.RFC3339)
+}
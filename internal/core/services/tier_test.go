@@ -0,0 +1,66 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"testing"
+	memoryRepository "walls-user-service/internal/adapter/repository/memory"
+	"walls-user-service/internal/core/domain/dto"
+	"walls-user-service/internal/core/domain/entity"
+)
+
+func storedTier(t *testing.T, service *tierService, tierReference string) entity.TierConfig {
+	t.Helper()
+	tierData, err := service.GetTierByReference(context.Background(), tierReference)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tierData.(entity.TierConfig)
+}
+
+func TestUpdateTierConfigIgnoresAnOlderUpdate(t *testing.T) {
+	service := NewTierService(memoryRepository.NewTier())
+	ctx := context.Background()
+
+	latest := dto.TierConfigDto{Tier: entity.Tier{TierReference: "tier-1", SendingLimit: 500}, UpdatedOn: "2024-01-02T10:00:00+01:00"}
+	if _, err := service.UpdateTierConfig(ctx, latest); err != nil {
+		t.Fatal(err)
+	}
+	older := dto.TierConfigDto{Tier: entity.Tier{TierReference: "tier-1", SendingLimit: 100}, UpdatedOn: "2024-01-02T11:00:00+03:00"}
+	tierData, err := service.UpdateTierConfig(ctx, older)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if tier := tierData.(entity.TierConfig); tier.SendingLimit != 500 {
+		t.Errorf("expected the older update to report the catalogue's tier, got %+v", tier)
+	}
+	if tier := storedTier(t, service, "tier-1"); tier.SendingLimit != 500 || tier.UpdatedOn != "2024-01-02T09:00:00Z" {
+		t.Errorf("expected the later update to be kept in UTC, got %+v", tier)
+	}
+}
+
+func TestConcurrentTierConfigUpdatesKeepTheLatest(t *testing.T) {
+	service := NewTierService(memoryRepository.NewTier())
+	updates := []dto.TierConfigDto{
+		{Tier: entity.Tier{TierReference: "tier-1", SendingLimit: 100}, UpdatedOn: "2024-01-01T00:00:00Z"},
+		{Tier: entity.Tier{TierReference: "tier-1", SendingLimit: 300}, UpdatedOn: "2024-01-03T00:00:00Z"},
+		{Tier: entity.Tier{TierReference: "tier-1", SendingLimit: 200}, UpdatedOn: "2024-01-02T00:00:00Z"},
+	}
+
+	var wait sync.WaitGroup
+	for _, update := range updates {
+		wait.Add(1)
+		go func(update dto.TierConfigDto) {
+			defer wait.Done()
+			if _, err := service.UpdateTierConfig(context.Background(), update); err != nil {
+				t.Error(err)
+			}
+		}(update)
+	}
+	wait.Wait()
+
+	if tier := storedTier(t, service, "tier-1"); tier.SendingLimit != 300 {
+		t.Errorf("expected the latest update to win, got %+v", tier)
+	}
+}
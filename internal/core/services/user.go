@@ -2,36 +2,52 @@ package services
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"strconv"
 	"time"
 
-	publisher "walls-user-service/internal/adapter/events/publisher"
 	"walls-user-service/internal/core/domain/dto"
 	"walls-user-service/internal/core/domain/entity"
+	events "walls-user-service/internal/core/domain/event/data"
 	event "walls-user-service/internal/core/domain/event/eto"
 	"walls-user-service/internal/core/domain/mapper"
+	"walls-user-service/internal/core/domain/shared"
 	configuration "walls-user-service/internal/core/helper/configuration-helper"
+	errorhelper "walls-user-service/internal/core/helper/error-helper"
+	helper "walls-user-service/internal/core/helper/event-helper"
 	eto "walls-user-service/internal/core/helper/event-helper/eto"
 	logger "walls-user-service/internal/core/helper/log-helper"
 	validation "walls-user-service/internal/core/helper/validation-helper"
 	ports "walls-user-service/internal/port"
 
 	"github.com/google/uuid"
-	"github.com/redis/go-redis/v9"
 )
 
 var UserService = &userService{}
 
+var (
+	defaultDeviceCoolingOff   = 24 * time.Hour
+	defaultDeviceCoolingLimit = 20000.0
+	defaultSearchLimit        = int64(10)
+
+	// maxUpdateAttempts - how often a user update conflicting with a concurrent write is tried
+	maxUpdateAttempts = 3
+)
+
 type userService struct {
-	userRepository ports.UserRepository
-	redisClient    *redis.Client
+	userRepository   ports.UserRepository
+	outboxRepository ports.OutboxRepository
+	tierRepository   ports.TierRepository
 }
 
-func NewUserService(userRepository ports.UserRepository, redisClient *redis.Client) *userService {
+func NewUserService(userRepository ports.UserRepository, outboxRepository ports.OutboxRepository, tierRepository ports.TierRepository) *userService {
 	UserService = &userService{
-		userRepository: userRepository,
-		redisClient:    redisClient,
+		userRepository:   userRepository,
+		outboxRepository: outboxRepository,
+		tierRepository:   tierRepository,
 	}
 
 	return UserService
@@ -42,27 +58,18 @@ func (service *userService) CreateUser(ctx context.Context, createUserDto dto.Cr
 	userData, _ := service.GetUserByReference(ctx, currentUserDto.UserReference)
 	if userData != nil {
 		logger.LogEvent("ERROR", "Sorry, user already exists")
-		return nil, errors.New("sorry, user already exists")
+		return nil, ErrUserExists
 	}
 
 	if createUserDto.Phone != currentUserDto.Phone {
 		logger.LogEvent("ERROR", "The current user phone number does not match the intended registration phone number")
-		return nil, errors.New("the current user phone number does nto match the intended registration phone number")
+		return nil, ErrPhoneMismatch
 	}
 
 	user := mapper.CurrentUserDtoToUser(createUserDto, currentUserDto)
 
-	result, err := service.userRepository.CreateUser(ctx, user)
-	if err != nil {
-		logger.LogEvent("ERROR", "Unable to create User")
-		return nil, errors.New("unable to create User")
-	}
-
-	request := struct {
-		UserReference string        `json:"user_reference"`
-		Phone         string        `json:"phone"`
-		Device        entity.Device `json:"device"`
-	}{
+	request := events.UserCreatedEventData{
+		SchemaVersion: events.SchemaVersion,
 		UserReference: user.UserReference,
 		Phone:         user.UserProfile.Phone,
 		Device:        user.Device,
@@ -81,8 +88,21 @@ func (service *userService) CreateUser(ctx context.Context, createUserDto dto.Cr
 	}
 	//publishing user created event
 
-	eventPublisher := publisher.NewPublisher(service.redisClient)
-	eventPublisher.PublishUserCreatedEvent(ctx, userCreatedEvent)
+	outboxEvent, err := newOutboxEvent(userCreatedEvent)
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to prepare event for publishing")
+		return nil, errors.New("failed to prepare event for publishing")
+	}
+
+	result, err := service.userRepository.CreateUser(ctx, user, outboxEvent)
+	if errors.Is(err, ports.ErrDuplicateKey) {
+		logger.LogEvent("ERROR", "Sorry, user already exists")
+		return nil, ErrUserExists.Wrap(err)
+	}
+	if err != nil {
+		logger.LogEvent("ERROR", "Unable to create User")
+		return nil, errors.New("unable to create User")
+	}
 
 	// requestDto := dto.IdentityDto{
 	// 	Phone:  user.UserProfile.Phone,
@@ -96,33 +116,33 @@ func (service *userService) CreateUser(ctx context.Context, createUserDto dto.Cr
 }
 
 func (service *userService) CreateCompanyProfile(ctx context.Context, user_reference string, createCompanyProfileDto dto.CreateCompanyProfileDto, currentUserDto dto.CurrentUserDto) (interface{}, error) {
+	return retryOnConflict(func() (interface{}, error) {
+		return service.createCompanyProfile(ctx, user_reference, createCompanyProfileDto, currentUserDto)
+	})
+}
+
+func (service *userService) createCompanyProfile(ctx context.Context, user_reference string, createCompanyProfileDto dto.CreateCompanyProfileDto, currentUserDto dto.CurrentUserDto) (interface{}, error) {
 	logger.LogEvent("INFO", "Creating Company Profile")
 	userData, err := service.GetUserByReference(ctx, user_reference)
 	if err != nil {
 		logger.LogEvent("ERROR", "Failed to retrieve user")
-		return nil, errors.New("failed to retrieve user")
+		return nil, err
 	}
-	user := mapper.UserDtoToUser(userData.(dto.UserDto))
+	user := userData.(entity.User)
 
 	//check if device is registered
-	if !isRegisteredDevice(entity.Device(currentUserDto.Device), user.Device) {
+	if !isRegisteredDevice(entity.Device(currentUserDto.Device), user) {
 		logger.LogEvent("ERROR", "This device is not registered to this user")
-		return nil, errors.New("this device is not registered to this user")
+		return nil, ErrDeviceNotRegistered
 	}
 	//check if the phone number is registered
 	if !isRegisteredPhoneNumber(currentUserDto.Phone, user.UserProfile.Phone) {
 		logger.LogEvent("ERROR", "The phone number is not registered to this user")
-		return nil, errors.New("the phone number is not registered to this user")
+		return nil, ErrPhoneNotRegistered
 	}
 
 	user = mapper.CreateCompanyProfileDtoToUser(user, createCompanyProfileDto)
 
-	result, err := service.userRepository.UpdateUser(ctx, user_reference, user)
-	if err != nil {
-		logger.LogEvent("ERROR", "Failed to create walls tag")
-		return nil, errors.New("failed to create walls tag")
-	}
-
 	companyProfileCreatedEvent := event.CompanyProfileCreatedEvent{
 		Event: eto.Event{
 			EventReference:     uuid.New().String(),
@@ -131,54 +151,63 @@ func (service *userService) CreateCompanyProfile(ctx context.Context, user_refer
 			EventType:          "companyprofilecreatedevent",
 			EventSource:        configuration.ServiceConfiguration.ServiceName,
 			EventUserReference: user.UserReference,
-			EventData:          user,
+			EventData:          mapper.UserToCompanyProfileCreatedEventData(user),
 		},
 	}
 
-	eventPublisher := publisher.NewPublisher(service.redisClient)
-	eventPublisher.PublishCompanyProfileCreatedEvent(ctx, companyProfileCreatedEvent)
+	outboxEvent, err := newOutboxEvent(companyProfileCreatedEvent)
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to prepare event for publishing")
+		return nil, errors.New("failed to prepare event for publishing")
+	}
+
+	result, err := service.userRepository.UpdateUser(ctx, user_reference, user, outboxEvent)
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to create walls tag")
+		return nil, updateError(err, "failed to create walls tag")
+	}
 
 	return result, nil
 }
 
 func (service *userService) CreateCompanyWallsBadge(ctx context.Context, user_reference string, companyWallsBadgeDto dto.CompanyWallsBadgeDto, currentUserDto dto.CurrentUserDto) (interface{}, error) {
+	return retryOnConflict(func() (interface{}, error) {
+		return service.createCompanyWallsBadge(ctx, user_reference, companyWallsBadgeDto, currentUserDto)
+	})
+}
+
+func (service *userService) createCompanyWallsBadge(ctx context.Context, user_reference string, companyWallsBadgeDto dto.CompanyWallsBadgeDto, currentUserDto dto.CurrentUserDto) (interface{}, error) {
 	userData, err := service.GetUserByReference(ctx, user_reference)
 	if err != nil {
 		logger.LogEvent("ERROR", "Failed to retrieve user")
-		return nil, errors.New("failed to retrieve user")
+		return nil, err
 	}
 
-	user := mapper.UserDtoToUser(userData.(dto.UserDto))
+	user := userData.(entity.User)
 
 	wallsTagData, _ := service.GetUserByWallsTag(ctx, companyWallsBadgeDto.WallsTag)
 	if wallsTagData != nil {
 		logger.LogEvent("ERROR", "WallsTag already exists")
-		return nil, errors.New("this wallstag is already in use")
+		return nil, ErrWallsTagInUse
 	}
 
 	//check if device is registered
-	if !isRegisteredDevice(entity.Device(currentUserDto.Device), user.Device) {
+	if !isRegisteredDevice(entity.Device(currentUserDto.Device), user) {
 		logger.LogEvent("ERROR", "This device is not registered to this user")
-		return nil, errors.New("this device is not registered to this user")
+		return nil, ErrDeviceNotRegistered
 	}
 	//check if the phone number is registered
 	if !isRegisteredPhoneNumber(currentUserDto.Phone, user.UserProfile.Phone) {
 		logger.LogEvent("ERROR", "The phone number is not registered to this user")
-		return nil, errors.New("the phone number is not registered to this user")
+		return nil, ErrPhoneNotRegistered
 	}
 
 	if user.UserReference != currentUserDto.UserReference {
 		logger.LogEvent("ERROR", "This user with phone number "+currentUserDto.Phone+" is not registered")
-		return nil, errors.New("this user  with phone number " + currentUserDto.Phone + " is not registered")
+		return nil, ErrUserNotFound.WithMessage("this user  with phone number " + currentUserDto.Phone + " is not registered")
 	}
 
 	user = mapper.CreateCompanyWallsBadgeDtoToUser(user, companyWallsBadgeDto)
-	result, err := service.userRepository.UpdateUser(ctx, user_reference, user)
-	if err != nil {
-		logger.LogEvent("ERROR", "Failed to create walls tag")
-		return nil, errors.New("failed to create walls tag")
-	}
-
 	companyWallsBadgeCreatedEvent := event.CompanyWallsBadgeCreatedEvent{
 		Event: eto.Event{
 			EventReference:     uuid.New().String(),
@@ -187,66 +216,77 @@ func (service *userService) CreateCompanyWallsBadge(ctx context.Context, user_re
 			EventType:          "companywallsbadgecreatedevent",
 			EventSource:        configuration.ServiceConfiguration.ServiceName,
 			EventUserReference: user.UserReference,
-			EventData:          user,
+			EventData:          mapper.UserToCompanyWallsBadgeCreatedEventData(user, companyWallsBadgeDto.CompanyProfileReference),
 		},
 	}
 
-	eventPublisher := publisher.NewPublisher(service.redisClient)
-	eventPublisher.PublishCompanyWallsBadgeCreatedEvent(ctx, companyWallsBadgeCreatedEvent)
+	outboxEvent, err := newOutboxEvent(companyWallsBadgeCreatedEvent)
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to prepare event for publishing")
+		return nil, errors.New("failed to prepare event for publishing")
+	}
+
+	result, err := service.userRepository.UpdateUser(ctx, user_reference, user, outboxEvent)
+	if errors.Is(err, ports.ErrDuplicateKey) {
+		logger.LogEvent("ERROR", "WallsTag already exists")
+		return nil, ErrWallsTagInUse.Wrap(err)
+	}
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to create walls tag")
+		return nil, updateError(err, "failed to create walls tag")
+	}
 
 	return result, nil
 }
 
 func (service *userService) CreateUserWallsBadge(ctx context.Context, user_reference string, userWallsBadgeDto dto.UserWallsBadgeDto, currentUserDto dto.CurrentUserDto) (interface{}, error) {
+	return retryOnConflict(func() (interface{}, error) {
+		return service.createUserWallsBadge(ctx, user_reference, userWallsBadgeDto, currentUserDto)
+	})
+}
+
+func (service *userService) createUserWallsBadge(ctx context.Context, user_reference string, userWallsBadgeDto dto.UserWallsBadgeDto, currentUserDto dto.CurrentUserDto) (interface{}, error) {
 	userData, err := service.GetUserByReference(ctx, user_reference)
 	if err != nil {
 		logger.LogEvent("ERROR", "Failed to retrieve user")
-		return nil, errors.New("failed to retrieve user")
+		return nil, err
 	}
 
-	user := mapper.UserDtoToUser(userData.(dto.UserDto))
+	user := userData.(entity.User)
 
 	wallsTagData, _ := service.GetUserByWallsTag(ctx, userWallsBadgeDto.WallsTag)
 	if wallsTagData != nil {
 		logger.LogEvent("ERROR", "WallsTag already exists")
-		return nil, errors.New("this wallstag is already in use")
+		return nil, ErrWallsTagInUse
 	}
 
 	//check if device is registered
-	if !isRegisteredDevice(entity.Device(currentUserDto.Device), user.Device) {
+	if !isRegisteredDevice(entity.Device(currentUserDto.Device), user) {
 		logger.LogEvent("ERROR", "This device is not registered to this user")
-		return nil, errors.New("this device is not registered to this user")
+		return nil, ErrDeviceNotRegistered
 	}
 	//check if the phone number is registered
 	if !isRegisteredPhoneNumber(currentUserDto.Phone, user.UserProfile.Phone) {
 		logger.LogEvent("ERROR", "The phone number is not registered to this user")
-		return nil, errors.New("the phone number is not registered to this user")
+		return nil, ErrPhoneNotRegistered
 	}
 
 	if user.UserReference != currentUserDto.UserReference {
 		logger.LogEvent("ERROR", "This user with phone number "+currentUserDto.Phone+" is not registered")
-		return nil, errors.New("this user  with phone number " + currentUserDto.Phone + " is not registered")
+		return nil, ErrUserNotFound.WithMessage("this user  with phone number " + currentUserDto.Phone + " is not registered")
 	}
 
 	user = mapper.CreateUserWallsBadgeDtoToUser(user, userWallsBadgeDto)
-	result, err := service.userRepository.UpdateUser(ctx, user_reference, user)
-	if err != nil {
-		logger.LogEvent("ERROR", "Failed to create walls tag")
-		return nil, errors.New("failed to create walls tag")
-	}
-
-	request := struct {
-		UserReference   string `json:"user_reference"`
-		DeviceReference string `json:"device_reference"`
-		Contact         string `json:"contact"`
-		Channel         string `json:"channel"`
-		Message         string `json:"message"`
-	}{
-		UserReference:   user.UserReference,
-		DeviceReference: user.Device.DeviceReference,
-		Contact:         user.UserProfile.Email,
-		Channel:         "email",
-		Message:         "A new wallsbadge has been successfully created for you",
+	wallsBadge := user.UserProfile.WallsBadge[len(user.UserProfile.WallsBadge)-1]
+	request := events.UserWallsBadgeCreatedEventData{
+		SchemaVersion:       events.SchemaVersion,
+		UserReference:       user.UserReference,
+		WallsBadgeReference: wallsBadge.WallsBadgeReference,
+		WallsTag:            wallsBadge.WallsTag,
+		DeviceReference:     user.Device.DeviceReference,
+		Contact:             user.UserProfile.Email,
+		Channel:             "email",
+		Message:             "A new wallsbadge has been successfully created for you",
 	}
 
 	userWallsBadgeCreatedEvent := event.UserWallsBadgeCreatedEvent{
@@ -261,30 +301,49 @@ func (service *userService) CreateUserWallsBadge(ctx context.Context, user_refer
 		},
 	}
 
-	eventPublisher := publisher.NewPublisher(service.redisClient)
-	eventPublisher.PublishUserWallsBadgeCreatedEvent(ctx, userWallsBadgeCreatedEvent)
+	outboxEvent, err := newOutboxEvent(userWallsBadgeCreatedEvent)
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to prepare event for publishing")
+		return nil, errors.New("failed to prepare event for publishing")
+	}
+
+	result, err := service.userRepository.UpdateUser(ctx, user_reference, user, outboxEvent)
+	if errors.Is(err, ports.ErrDuplicateKey) {
+		logger.LogEvent("ERROR", "WallsTag already exists")
+		return nil, ErrWallsTagInUse.Wrap(err)
+	}
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to create walls tag")
+		return nil, updateError(err, "failed to create walls tag")
+	}
 
 	return result, nil
 }
 
 func (service *userService) UpdateCompanyProfile(ctx context.Context, user_reference string, companyProfileReference string, updateCompanyProfileDto dto.UpdateCompanyProfileDto, currentUserDto dto.CurrentUserDto) (interface{}, error) {
+	return retryOnConflict(func() (interface{}, error) {
+		return service.updateCompanyProfile(ctx, user_reference, companyProfileReference, updateCompanyProfileDto, currentUserDto)
+	})
+}
+
+func (service *userService) updateCompanyProfile(ctx context.Context, user_reference string, companyProfileReference string, updateCompanyProfileDto dto.UpdateCompanyProfileDto, currentUserDto dto.CurrentUserDto) (interface{}, error) {
 	logger.LogEvent("INFO", "Updating Company Profile")
 	userData, err := service.GetUserByReference(ctx, user_reference)
 	if err != nil {
 		logger.LogEvent("ERROR", "Failed to retrieve user")
-		return nil, errors.New("failed to retrieve user")
+		return nil, err
 	}
-	user := mapper.UserDtoToUser(userData.(dto.UserDto))
+	user := userData.(entity.User)
 
 	//check if device is registered
-	if !isRegisteredDevice(entity.Device(currentUserDto.Device), user.Device) {
+	if !isRegisteredDevice(entity.Device(currentUserDto.Device), user) {
 		logger.LogEvent("ERROR", "This device is not registered to this user")
-		return nil, errors.New("this device is not registered to this user")
+		return nil, ErrDeviceNotRegistered
 	}
 	//check if the phone number is registered
 	if !isRegisteredPhoneNumber(currentUserDto.Phone, user.UserProfile.Phone) {
 		logger.LogEvent("ERROR", "The phone number is not registered to this user")
-		return nil, errors.New("the phone number is not registered to this user")
+		return nil, ErrPhoneNotRegistered
 	}
 
 	for i, c := range user.CompanyProfile {
@@ -298,12 +357,6 @@ func (service *userService) UpdateCompanyProfile(ctx context.Context, user_refer
 		}
 	}
 
-	result, err := service.userRepository.UpdateUser(ctx, user_reference, user)
-	if err != nil {
-		logger.LogEvent("ERROR", "Failed to create walls tag")
-		return nil, errors.New("failed to create walls tag")
-	}
-
 	companyProfileUpdatedEvent := event.CompanyProfileUpdatedEvent{
 		Event: eto.Event{
 			EventReference:     uuid.New().String(),
@@ -312,32 +365,47 @@ func (service *userService) UpdateCompanyProfile(ctx context.Context, user_refer
 			EventType:          "companyprofilecreatedevent",
 			EventSource:        configuration.ServiceConfiguration.ServiceName,
 			EventUserReference: user.UserReference,
-			EventData:          user,
+			EventData:          mapper.UserToCompanyProfileUpdatedEventData(user, companyProfileReference),
 		},
 	}
 
-	eventPublisher := publisher.NewPublisher(service.redisClient)
-	eventPublisher.PublishCompanyProfileUpdatedEvent(ctx, companyProfileUpdatedEvent)
+	outboxEvent, err := newOutboxEvent(companyProfileUpdatedEvent)
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to prepare event for publishing")
+		return nil, errors.New("failed to prepare event for publishing")
+	}
+
+	result, err := service.userRepository.UpdateUser(ctx, user_reference, user, outboxEvent)
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to create walls tag")
+		return nil, updateError(err, "failed to create walls tag")
+	}
 
 	return result, nil
 }
 
 func (service *userService) DisableCompanyWallsBadge(ctx context.Context, user_reference string, companyProfileReference string, companyWallsBadgeReference string, currentUserDto dto.CurrentUserDto) (interface{}, error) {
+	return retryOnConflict(func() (interface{}, error) {
+		return service.disableCompanyWallsBadge(ctx, user_reference, companyProfileReference, companyWallsBadgeReference, currentUserDto)
+	})
+}
+
+func (service *userService) disableCompanyWallsBadge(ctx context.Context, user_reference string, companyProfileReference string, companyWallsBadgeReference string, currentUserDto dto.CurrentUserDto) (interface{}, error) {
 	userData, err := service.GetUserByReference(ctx, user_reference)
 	if err != nil {
 		logger.LogEvent("ERROR", "Failed to retrieve user")
-		return nil, errors.New("failed to retrieve user")
+		return nil, err
 	}
-	user := mapper.UserDtoToUser(userData.(dto.UserDto))
+	user := userData.(entity.User)
 	//check if device is registered
-	if !isRegisteredDevice(entity.Device(currentUserDto.Device), user.Device) {
+	if !isRegisteredDevice(entity.Device(currentUserDto.Device), user) {
 		logger.LogEvent("ERROR", "This device is not registered to this user")
-		return nil, errors.New("this device is not registered to this user")
+		return nil, ErrDeviceNotRegistered
 	}
 	//check if the phone number is registered
 	if !isRegisteredPhoneNumber(currentUserDto.Phone, user.UserProfile.Phone) {
 		logger.LogEvent("ERROR", "The phone number is not registered to this user")
-		return nil, errors.New("the phone number is not registered to this user")
+		return nil, ErrPhoneNotRegistered
 	}
 
 OUTER:
@@ -352,12 +420,6 @@ OUTER:
 		}
 	}
 
-	result, err := service.userRepository.UpdateUser(ctx, user_reference, user)
-	if err != nil {
-		logger.LogEvent("ERROR", "Failed to update user's name")
-		return nil, errors.New("failed to update user's name")
-	}
-
 	companyWallsBadgeDisabledEvent := event.CompanyWallsBadgeDisabledEvent{
 		Event: eto.Event{
 			EventReference:     uuid.New().String(),
@@ -366,33 +428,48 @@ OUTER:
 			EventType:          "companywallsbadgedisabledevent",
 			EventSource:        configuration.ServiceConfiguration.ServiceName,
 			EventUserReference: user.UserReference,
-			EventData:          user,
+			EventData:          mapper.UserToCompanyWallsBadgeDisabledEventData(user, companyProfileReference, companyWallsBadgeReference),
 		},
 	}
 
-	eventPublisher := publisher.NewPublisher(service.redisClient)
-	eventPublisher.PublishCompanyWallsBadgeDisabledEvent(ctx, companyWallsBadgeDisabledEvent)
+	outboxEvent, err := newOutboxEvent(companyWallsBadgeDisabledEvent)
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to prepare event for publishing")
+		return nil, errors.New("failed to prepare event for publishing")
+	}
+
+	result, err := service.userRepository.UpdateUser(ctx, user_reference, user, outboxEvent)
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to update user's name")
+		return nil, updateError(err, "failed to update user's name")
+	}
 
 	return result, nil
 }
 
 func (service *userService) DisableUserWallsBadge(ctx context.Context, user_reference string, userWallsBadgeReference string, currentUserDto dto.CurrentUserDto) (interface{}, error) {
+	return retryOnConflict(func() (interface{}, error) {
+		return service.disableUserWallsBadge(ctx, user_reference, userWallsBadgeReference, currentUserDto)
+	})
+}
+
+func (service *userService) disableUserWallsBadge(ctx context.Context, user_reference string, userWallsBadgeReference string, currentUserDto dto.CurrentUserDto) (interface{}, error) {
 	userData, err := service.GetUserByReference(ctx, user_reference)
 	if err != nil {
 		logger.LogEvent("ERROR", "Failed to retrieve user")
-		return nil, errors.New("failed to retrieve user")
+		return nil, err
 	}
-	user := mapper.UserDtoToUser(userData.(dto.UserDto))
+	user := userData.(entity.User)
 
 	//check if device is registered
-	if !isRegisteredDevice(entity.Device(currentUserDto.Device), user.Device) {
+	if !isRegisteredDevice(entity.Device(currentUserDto.Device), user) {
 		logger.LogEvent("ERROR", "This device is not registered to this user")
-		return nil, errors.New("this device is not registered to this user")
+		return nil, ErrDeviceNotRegistered
 	}
 	//check if the phone number is registered
 	if !isRegisteredPhoneNumber(currentUserDto.Phone, user.UserProfile.Phone) {
 		logger.LogEvent("ERROR", "The phone number is not registered to this user")
-		return nil, errors.New("the phone number is not registered to this user")
+		return nil, ErrPhoneNotRegistered
 	}
 
 	for index, userWallsBadge := range user.UserProfile.WallsBadge {
@@ -402,12 +479,6 @@ func (service *userService) DisableUserWallsBadge(ctx context.Context, user_refe
 		}
 	}
 
-	result, err := service.userRepository.UpdateUser(ctx, user_reference, user)
-	if err != nil {
-		logger.LogEvent("ERROR", "Failed to update user's name")
-		return nil, errors.New("failed to update user's name")
-	}
-
 	userWallsBadgeDisabledEvent := event.UserWallsBadgeDisabledEvent{
 		Event: eto.Event{
 			EventReference:     uuid.New().String(),
@@ -416,12 +487,21 @@ func (service *userService) DisableUserWallsBadge(ctx context.Context, user_refe
 			EventType:          "userwallsbadgedisabledEvent",
 			EventSource:        configuration.ServiceConfiguration.ServiceName,
 			EventUserReference: user.UserReference,
-			EventData:          user,
+			EventData:          mapper.UserToUserWallsBadgeDisabledEventData(user, userWallsBadgeReference),
 		},
 	}
 
-	eventPublisher := publisher.NewPublisher(service.redisClient)
-	eventPublisher.PublishUserWallsBadgeDisabledEvent(ctx, userWallsBadgeDisabledEvent)
+	outboxEvent, err := newOutboxEvent(userWallsBadgeDisabledEvent)
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to prepare event for publishing")
+		return nil, errors.New("failed to prepare event for publishing")
+	}
+
+	result, err := service.userRepository.UpdateUser(ctx, user_reference, user, outboxEvent)
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to update user's name")
+		return nil, updateError(err, "failed to update user's name")
+	}
 
 	return result, nil
 }
@@ -430,9 +510,9 @@ func (service *userService) GetCompanyWallsBadgeList(ctx context.Context, user_r
 	userData, err := service.GetUserByReference(ctx, user_reference)
 	if err != nil {
 		logger.LogEvent("ERROR", "Failed to retrieve user")
-		return nil, errors.New("failed to retrieve user")
+		return nil, err
 	}
-	user := mapper.UserDtoToUser(userData.(dto.UserDto))
+	user := userData.(entity.User)
 
 	result := []entity.WallsBadge{}
 	for _, companyProfile := range user.CompanyProfile {
@@ -448,9 +528,9 @@ func (service *userService) GetUserWallsBadgeList(ctx context.Context, user_refe
 	userData, err := service.GetUserByReference(ctx, user_reference)
 	if err != nil {
 		logger.LogEvent("ERROR", "Failed to retrieve user")
-		return nil, errors.New("failed to retrieve user")
+		return nil, err
 	}
-	user := mapper.UserDtoToUser(userData.(dto.UserDto))
+	user := userData.(entity.User)
 
 	result := user.UserProfile.WallsBadge
 
@@ -461,9 +541,9 @@ func (service *userService) GetDefaultCompanyWallsBadge(ctx context.Context, use
 	userData, err := service.GetUserByReference(ctx, user_reference)
 	if err != nil {
 		logger.LogEvent("ERROR", "Failed to retrieve user")
-		return nil, errors.New("failed to retrieve user")
+		return nil, err
 	}
-	user := mapper.UserDtoToUser(userData.(dto.UserDto))
+	user := userData.(entity.User)
 
 	result := entity.WallsBadge{}
 OUTER:
@@ -485,9 +565,9 @@ func (service *userService) GetDefaultUserWallsBadge(ctx context.Context, user_r
 	userData, err := service.GetUserByReference(ctx, user_reference)
 	if err != nil {
 		logger.LogEvent("ERROR", "Failed to retrieve user")
-		return nil, errors.New("failed to retrieve user")
+		return nil, err
 	}
-	user := mapper.UserDtoToUser(userData.(dto.UserDto))
+	user := userData.(entity.User)
 
 	// result := entity.WallsBadge{}
 	for _, wallsBagde := range user.UserProfile.WallsBadge {
@@ -496,16 +576,22 @@ func (service *userService) GetDefaultUserWallsBadge(ctx context.Context, user_r
 		}
 	}
 
-	return nil, errors.New("no default wallsbadge found")
+	return nil, ErrNoDefaultWallsBadge
 }
 
 func (service *userService) DisableCompanyProfile(ctx context.Context, user_reference string, companyProfileReference string) (interface{}, error) {
+	return retryOnConflict(func() (interface{}, error) {
+		return service.disableCompanyProfile(ctx, user_reference, companyProfileReference)
+	})
+}
+
+func (service *userService) disableCompanyProfile(ctx context.Context, user_reference string, companyProfileReference string) (interface{}, error) {
 	userData, err := service.GetUserByReference(ctx, user_reference)
 	if err != nil {
 		logger.LogEvent("ERROR", "Failed to retrieve user")
-		return nil, errors.New("failed to retrieve user")
+		return nil, err
 	}
-	user := mapper.UserDtoToUser(userData.(dto.UserDto))
+	user := userData.(entity.User)
 
 	for index, companyProfile := range user.CompanyProfile {
 		if companyProfile.CompanyProfileReference == companyProfileReference {
@@ -514,12 +600,6 @@ func (service *userService) DisableCompanyProfile(ctx context.Context, user_refe
 		}
 	}
 
-	result, err := service.userRepository.UpdateUser(ctx, user_reference, user)
-	if err != nil {
-		logger.LogEvent("ERROR", "Failed to update user's name")
-		return nil, errors.New("failed to update user's name")
-	}
-
 	companyProfileDisabledEvent := event.CompanyProfileDisabledEvent{
 		Event: eto.Event{
 			EventReference:     uuid.New().String(),
@@ -528,33 +608,48 @@ func (service *userService) DisableCompanyProfile(ctx context.Context, user_refe
 			EventType:          "companyprofiledisabledevent",
 			EventSource:        configuration.ServiceConfiguration.ServiceName,
 			EventUserReference: user.UserReference,
-			EventData:          user,
+			EventData:          mapper.UserToCompanyProfileDisabledEventData(user, companyProfileReference),
 		},
 	}
 
-	eventPublisher := publisher.NewPublisher(service.redisClient)
-	eventPublisher.PublishCompanyProfileDisabledEvent(ctx, companyProfileDisabledEvent)
+	outboxEvent, err := newOutboxEvent(companyProfileDisabledEvent)
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to prepare event for publishing")
+		return nil, errors.New("failed to prepare event for publishing")
+	}
+
+	result, err := service.userRepository.UpdateUser(ctx, user_reference, user, outboxEvent)
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to update user's name")
+		return nil, updateError(err, "failed to update user's name")
+	}
 
 	return result, nil
 }
 
 func (service *userService) UpdateCompanyLogo(ctx context.Context, user_reference string, companyProfileReference string, updateCompanyLogoDto dto.UpdateCompanyLogo, currentUserDto dto.CurrentUserDto) (interface{}, error) {
+	return retryOnConflict(func() (interface{}, error) {
+		return service.updateCompanyLogo(ctx, user_reference, companyProfileReference, updateCompanyLogoDto, currentUserDto)
+	})
+}
+
+func (service *userService) updateCompanyLogo(ctx context.Context, user_reference string, companyProfileReference string, updateCompanyLogoDto dto.UpdateCompanyLogo, currentUserDto dto.CurrentUserDto) (interface{}, error) {
 	userData, err := service.GetUserByReference(ctx, user_reference)
 	if err != nil {
 		logger.LogEvent("ERROR", "Failed to retrieve user")
-		return nil, errors.New("failed to retrieve user")
+		return nil, err
 	}
-	user := mapper.UserDtoToUser(userData.(dto.UserDto))
+	user := userData.(entity.User)
 
 	//check if device is registered
-	if !isRegisteredDevice(entity.Device(currentUserDto.Device), user.Device) {
+	if !isRegisteredDevice(entity.Device(currentUserDto.Device), user) {
 		logger.LogEvent("ERROR", "This device is not registered to this user")
-		return nil, errors.New("this device is not registered to this user")
+		return nil, ErrDeviceNotRegistered
 	}
 	//check if the phone number is registered
 	if !isRegisteredPhoneNumber(currentUserDto.Phone, user.UserProfile.Phone) {
 		logger.LogEvent("ERROR", "The phone number is not registered to this user")
-		return nil, errors.New("the phone number is not registered to this user")
+		return nil, ErrPhoneNotRegistered
 	}
 
 	for index, companyProfile := range user.CompanyProfile {
@@ -563,12 +658,6 @@ func (service *userService) UpdateCompanyLogo(ctx context.Context, user_referenc
 		}
 	}
 
-	result, err := service.userRepository.UpdateUser(ctx, user_reference, user)
-	if err != nil {
-		logger.LogEvent("ERROR", "Failed to update user's name")
-		return nil, errors.New("failed to update user's name")
-	}
-
 	companyLogoUpdatedEvent := event.CompanyLogoUpdatedEvent{
 		Event: eto.Event{
 			EventReference:     uuid.New().String(),
@@ -577,39 +666,44 @@ func (service *userService) UpdateCompanyLogo(ctx context.Context, user_referenc
 			EventType:          "companylogoupdatedevent",
 			EventSource:        configuration.ServiceConfiguration.ServiceName,
 			EventUserReference: user.UserReference,
-			EventData:          user,
+			EventData:          mapper.UserToCompanyLogoUpdatedEventData(user, companyProfileReference),
 		},
 	}
 
-	eventPublisher := publisher.NewPublisher(service.redisClient)
-	eventPublisher.PublishCompanyLogoUpdatedEvent(ctx, companyLogoUpdatedEvent)
+	outboxEvent, err := newOutboxEvent(companyLogoUpdatedEvent)
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to prepare event for publishing")
+		return nil, errors.New("failed to prepare event for publishing")
+	}
+
+	result, err := service.userRepository.UpdateUser(ctx, user_reference, user, outboxEvent)
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to update user's name")
+		return nil, updateError(err, "failed to update user's name")
+	}
 
 	return result, nil
 }
 
 func (service *userService) UpdateUserProfileEmailStatus(ctx context.Context, user_reference string) (interface{}, error) {
+	return retryOnConflict(func() (interface{}, error) {
+		return service.updateUserProfileEmailStatus(ctx, user_reference)
+	})
+}
+
+func (service *userService) updateUserProfileEmailStatus(ctx context.Context, user_reference string) (interface{}, error) {
 	userData, err := service.GetUserByReference(ctx, user_reference)
 	if err != nil {
 		logger.LogEvent("ERROR", "Failed to retrieve user")
-		return nil, errors.New("failed to retrieve user")
+		return nil, err
 	}
 	// user := mapper.UserDtoToUser(userData.(dto.UserDto))
 	user := userData.(entity.User)
 
 	user.UserProfile.IsVerifiedEmail = true
 
-	result, err := service.userRepository.UpdateUser(ctx, user_reference, user)
-	if err != nil {
-		logger.LogEvent("ERROR", "Failed to update user's name")
-		return nil, errors.New("failed to update user's name")
-	}
-
-	etoRequestData := struct {
-		UserReference string        `json:"user_reference"`
-		Contact       string        `json:"contact"`
-		Channel       string        `json:"channel"`
-		Device        entity.Device `json:"device"`
-	}{
+	etoRequestData := events.UserProfileEmailStatusUpdatedEventData{
+		SchemaVersion: events.SchemaVersion,
 		UserReference: user.UserReference,
 		Contact:       user.UserProfile.Email,
 		Channel:       "email",
@@ -628,8 +722,17 @@ func (service *userService) UpdateUserProfileEmailStatus(ctx context.Context, us
 		},
 	}
 
-	eventPublisher := publisher.NewPublisher(service.redisClient)
-	eventPublisher.PublishUserProfileEmailStatusUpdatedEvent(ctx, userProfileEmailStatusUpdatedEvent)
+	outboxEvent, err := newOutboxEvent(userProfileEmailStatusUpdatedEvent)
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to prepare event for publishing")
+		return nil, errors.New("failed to prepare event for publishing")
+	}
+
+	result, err := service.userRepository.UpdateUser(ctx, user_reference, user, outboxEvent)
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to update user's name")
+		return nil, updateError(err, "failed to update user's name")
+	}
 
 	return result, nil
 }
@@ -670,12 +773,18 @@ func (service *userService) UpdateUserProfileEmailStatus(ctx context.Context, us
 // }
 
 func (service *userService) UpdateCompanyProfileEmailStatus(ctx context.Context, user_reference string, companyProfileReference string) (interface{}, error) {
+	return retryOnConflict(func() (interface{}, error) {
+		return service.updateCompanyProfileEmailStatus(ctx, user_reference, companyProfileReference)
+	})
+}
+
+func (service *userService) updateCompanyProfileEmailStatus(ctx context.Context, user_reference string, companyProfileReference string) (interface{}, error) {
 	userData, err := service.GetUserByReference(ctx, user_reference)
 	if err != nil {
 		logger.LogEvent("ERROR", "Failed to retrieve user")
-		return nil, errors.New("failed to retrieve user")
+		return nil, err
 	}
-	user := mapper.UserDtoToUser(userData.(dto.UserDto))
+	user := userData.(entity.User)
 
 	for index, companyProfile := range user.CompanyProfile {
 		if companyProfile.CompanyProfileReference == companyProfileReference {
@@ -683,12 +792,6 @@ func (service *userService) UpdateCompanyProfileEmailStatus(ctx context.Context,
 		}
 	}
 
-	result, err := service.userRepository.UpdateUser(ctx, user_reference, user)
-	if err != nil {
-		logger.LogEvent("ERROR", "Failed to update user's name")
-		return nil, errors.New("failed to update user's name")
-	}
-
 	companyProfileEmailStatusUpdatedEvent := event.CompanyProfileEmailStatusUpdatedEvent{
 		Event: eto.Event{
 			EventReference:     uuid.New().String(),
@@ -697,12 +800,21 @@ func (service *userService) UpdateCompanyProfileEmailStatus(ctx context.Context,
 			EventType:          "companyprofileemailstatusupdatedevent",
 			EventSource:        configuration.ServiceConfiguration.ServiceName,
 			EventUserReference: user.UserReference,
-			EventData:          user,
+			EventData:          mapper.UserToCompanyProfileEmailStatusUpdatedEventData(user, companyProfileReference),
 		},
 	}
 
-	eventPublisher := publisher.NewPublisher(service.redisClient)
-	eventPublisher.PublishCompanyProfileEmailStatusUpdatedEvent(ctx, companyProfileEmailStatusUpdatedEvent)
+	outboxEvent, err := newOutboxEvent(companyProfileEmailStatusUpdatedEvent)
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to prepare event for publishing")
+		return nil, errors.New("failed to prepare event for publishing")
+	}
+
+	result, err := service.userRepository.UpdateUser(ctx, user_reference, user, outboxEvent)
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to update user's name")
+		return nil, updateError(err, "failed to update user's name")
+	}
 
 	return result, nil
 }
@@ -710,35 +822,45 @@ func (service *userService) UpdateCompanyProfileEmailStatus(ctx context.Context,
 
 
 func (service *userService) SetDefaultBank(ctx context.Context, user_reference string, bankReference string, currentUserDto dto.CurrentUserDto) (interface{}, error) {
+	return retryOnConflict(func() (interface{}, error) {
+		return service.setDefaultBank(ctx, user_reference, bankReference, currentUserDto)
+	})
+}
+
+func (service *userService) setDefaultBank(ctx context.Context, user_reference string, bankReference string, currentUserDto dto.CurrentUserDto) (interface{}, error) {
 	userData, err := service.GetUserByReference(ctx, user_reference)
 	if err != nil {
 		logger.LogEvent("ERROR", "Failed to retrieve user")
-		return nil, errors.New("failed to retrieve user")
+		return nil, err
 	}
-	user := mapper.UserDtoToUser(userData.(dto.UserDto))
+	user := userData.(entity.User)
 
 	//check if device is registered
-	if !isRegisteredDevice(entity.Device(currentUserDto.Device), user.Device) {
+	if !isRegisteredDevice(entity.Device(currentUserDto.Device), user) {
 		logger.LogEvent("ERROR", "This device is not registered to this user")
-		return nil, errors.New("this device is not registered to this user")
+		return nil, ErrDeviceNotRegistered
 	}
 	//check if the phone number is registered
 	if !isRegisteredPhoneNumber(currentUserDto.Phone, user.UserProfile.Phone) {
 		logger.LogEvent("ERROR", "The phone number is not registered to this user")
-		return nil, errors.New("the phone number is not registered to this user")
+		return nil, ErrPhoneNotRegistered
 	}
 
+	found := false
 	for index, bankAccount := range user.BankAccounts {
 		if bankAccount.BankReference == bankReference {
+			if bankAccount.Status != shared.VerificationVerified {
+				logger.LogEvent("ERROR", "Bank account with reference: "+bankReference+" is not verified")
+				return nil, ErrBankNotVerified
+			}
 			user.BankAccounts[index].IsDefault = true
+			found = true
 			break
 		}
 	}
-
-	result, err := service.userRepository.UpdateUser(ctx, user_reference, user)
-	if err != nil {
-		logger.LogEvent("ERROR", "Failed to update user's name")
-		return nil, errors.New("failed to update user's name")
+	if !found {
+		logger.LogEvent("ERROR", "Bank account with reference: "+bankReference+" not found for the user")
+		return nil, ErrBankNotFound
 	}
 
 	defaultBankSetEvent := event.DefaultBankSetEvent{
@@ -749,45 +871,64 @@ func (service *userService) SetDefaultBank(ctx context.Context, user_reference s
 			EventType:          "defaultbanksetevent",
 			EventSource:        configuration.ServiceConfiguration.ServiceName,
 			EventUserReference: user.UserReference,
-			EventData:          user,
+			EventData:          mapper.UserToDefaultBankSetEventData(user, bankReference),
 		},
 	}
 
-	eventPublisher := publisher.NewPublisher(service.redisClient)
-	eventPublisher.PublishDefaultBankSetEvent(ctx, defaultBankSetEvent)
+	outboxEvent, err := newOutboxEvent(defaultBankSetEvent)
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to prepare event for publishing")
+		return nil, errors.New("failed to prepare event for publishing")
+	}
+
+	result, err := service.userRepository.SetDefaultBank(ctx, user_reference, bankReference, outboxEvent)
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to update user's name")
+		return nil, updateError(err, "failed to update user's name")
+	}
 
 	return result, nil
 }
 
 func (service *userService) SetDefaultCard(ctx context.Context, user_reference string, cardReference string, currentUserDto dto.CurrentUserDto) (interface{}, error) {
+	return retryOnConflict(func() (interface{}, error) {
+		return service.setDefaultCard(ctx, user_reference, cardReference, currentUserDto)
+	})
+}
+
+func (service *userService) setDefaultCard(ctx context.Context, user_reference string, cardReference string, currentUserDto dto.CurrentUserDto) (interface{}, error) {
 	userData, err := service.GetUserByReference(ctx, user_reference)
 	if err != nil {
 		logger.LogEvent("ERROR", "Failed to retrieve user")
-		return nil, errors.New("failed to retrieve user")
+		return nil, err
 	}
-	user := mapper.UserDtoToUser(userData.(dto.UserDto))
+	user := userData.(entity.User)
 
 	//check if device is registered
-	if !isRegisteredDevice(entity.Device(currentUserDto.Device), user.Device) {
+	if !isRegisteredDevice(entity.Device(currentUserDto.Device), user) {
 		logger.LogEvent("ERROR", "This device is not registered to this user")
-		return nil, errors.New("this device is not registered to this user")
+		return nil, ErrDeviceNotRegistered
 	}
 	//check if the phone number is registered
 	if !isRegisteredPhoneNumber(currentUserDto.Phone, user.UserProfile.Phone) {
 		logger.LogEvent("ERROR", "The phone number is not registered to this user")
-		return nil, errors.New("the phone number is not registered to this user")
+		return nil, ErrPhoneNotRegistered
 	}
 
+	found := false
 	for index, card := range user.Cards {
 		if card.CardReference == cardReference {
+			if card.Status != shared.VerificationVerified {
+				logger.LogEvent("ERROR", "Card with reference: "+cardReference+" is not verified")
+				return nil, ErrCardNotVerified
+			}
 			user.Cards[index].IsDefault = true
+			found = true
 		}
 	}
-
-	result, err := service.userRepository.UpdateUser(ctx, user_reference, user)
-	if err != nil {
-		logger.LogEvent("ERROR", "Failed to update user's name")
-		return nil, errors.New("failed to update user's name")
+	if !found {
+		logger.LogEvent("ERROR", "Card with reference: "+cardReference+" not found for the user")
+		return nil, ErrCardNotFound
 	}
 
 	defaultCardSetEvent := event.DefaultCardSetEvent{
@@ -798,50 +939,57 @@ func (service *userService) SetDefaultCard(ctx context.Context, user_reference s
 			EventType:          "defaultcardsetevent",
 			EventSource:        configuration.ServiceConfiguration.ServiceName,
 			EventUserReference: user.UserReference,
-			EventData:          user,
+			EventData:          mapper.UserToDefaultCardSetEventData(user, cardReference),
 		},
 	}
 
-	eventPublisher := publisher.NewPublisher(service.redisClient)
-	eventPublisher.PublishDefaultCardSetEvent(ctx, defaultCardSetEvent)
+	outboxEvent, err := newOutboxEvent(defaultCardSetEvent)
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to prepare event for publishing")
+		return nil, errors.New("failed to prepare event for publishing")
+	}
+
+	result, err := service.userRepository.SetDefaultCard(ctx, user_reference, cardReference, outboxEvent)
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to update user's name")
+		return nil, updateError(err, "failed to update user's name")
+	}
 
 	return result, nil
 }
 
 func (service *userService) UpdateUserName(ctx context.Context, user_reference string, usernameDto dto.UserNameDto, currentUserDto dto.CurrentUserDto) (interface{}, error) {
+	return retryOnConflict(func() (interface{}, error) {
+		return service.updateUserName(ctx, user_reference, usernameDto, currentUserDto)
+	})
+}
+
+func (service *userService) updateUserName(ctx context.Context, user_reference string, usernameDto dto.UserNameDto, currentUserDto dto.CurrentUserDto) (interface{}, error) {
 	userData, err := service.GetUserByReference(ctx, user_reference)
 	if err != nil {
 		logger.LogEvent("ERROR", "Failed to retrieve user")
-		return nil, errors.New("failed to retrieve user")
+		return nil, err
 	}
 	user := userData.(entity.User)
 
 	//check if device is registered
-	if !isRegisteredDevice(entity.Device(currentUserDto.Device), user.Device) {
+	if !isRegisteredDevice(entity.Device(currentUserDto.Device), user) {
 		logger.LogEvent("ERROR", "This device is not registered to this user")
-		return nil, errors.New("this device is not registered to this user")
+		return nil, ErrDeviceNotRegistered
 	}
 	//check if the phone number is registered
 	if !isRegisteredPhoneNumber(currentUserDto.Phone, user.UserProfile.Phone) {
 		logger.LogEvent("ERROR", "The phone number is not registered to this user")
-		return nil, errors.New("the phone number is not registered to this user")
+		return nil, ErrPhoneNotRegistered
 	}
 
 	user = mapper.UpdateUserNameDtoToUser(user, usernameDto)
 
-	result, err := service.userRepository.UpdateUser(ctx, user_reference, user)
-	if err != nil {
-		logger.LogEvent("ERROR", "Failed to update user's name")
-		return nil, errors.New("failed to update user's name")
-	}
-
-	etoRequestData := struct {
-		UserReference string        `json:"userReference"`
-		Contact       string        `json:"contact"`
-		Channel       string        `json:"channel"`
-		Device        entity.Device `json:"device"`
-	}{
+	etoRequestData := events.UsernameUpdatedEventData{
+		SchemaVersion: events.SchemaVersion,
 		UserReference: user.UserReference,
+		FirstName:     user.UserProfile.FirstName,
+		LastName:      user.UserProfile.LastName,
 		Contact:       user.UserProfile.Phone,
 		Channel:       "sms",
 		Device:        user.Device,
@@ -859,30 +1007,45 @@ func (service *userService) UpdateUserName(ctx context.Context, user_reference s
 		},
 	}
 
-	eventPublisher := publisher.NewPublisher(service.redisClient)
-	eventPublisher.PublishUsernameUpdatedEvent(ctx, usernameUpdatedEvent)
+	outboxEvent, err := newOutboxEvent(usernameUpdatedEvent)
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to prepare event for publishing")
+		return nil, errors.New("failed to prepare event for publishing")
+	}
+
+	result, err := service.userRepository.UpdateUser(ctx, user_reference, user, outboxEvent)
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to update user's name")
+		return nil, updateError(err, "failed to update user's name")
+	}
 
 	return result, nil
 }
 
 func (service *userService) UpdateEmail(ctx context.Context, user_reference string, emailDto dto.EmailDto, currentUserDto dto.CurrentUserDto) (interface{}, error) {
+	return retryOnConflict(func() (interface{}, error) {
+		return service.updateEmail(ctx, user_reference, emailDto, currentUserDto)
+	})
+}
+
+func (service *userService) updateEmail(ctx context.Context, user_reference string, emailDto dto.EmailDto, currentUserDto dto.CurrentUserDto) (interface{}, error) {
 	userData, err := service.GetUserByReference(ctx, user_reference)
 	if err != nil {
 		logger.LogEvent("ERROR", "Failed to retrieve user")
-		return nil, errors.New("failed to retrieve user")
+		return nil, err
 	}
 	// user := mapper.UserDtoToUser(userData.(dto.UserDto))
 	user := userData.(entity.User)
 
 	//check if device is registered
-	if !isRegisteredDevice(entity.Device(currentUserDto.Device), user.Device) {
+	if !isRegisteredDevice(entity.Device(currentUserDto.Device), user) {
 		logger.LogEvent("ERROR", "This device is not registered to this user")
-		return nil, errors.New("this device is not registered to this user")
+		return nil, ErrDeviceNotRegistered
 	}
 	//check if the phone number is registered
 	if !isRegisteredPhoneNumber(currentUserDto.Phone, user.UserProfile.Phone) {
 		logger.LogEvent("ERROR", "The phone number is not registered to this user")
-		return nil, errors.New("the phone number is not registered to this user")
+		return nil, ErrPhoneNotRegistered
 	}
 
 	user = mapper.UpdateEmailDtoToUser(user, emailDto)
@@ -890,7 +1053,7 @@ func (service *userService) UpdateEmail(ctx context.Context, user_reference stri
 	result, err := service.userRepository.UpdateUser(ctx, user_reference, user)
 	if err != nil {
 		logger.LogEvent("ERROR", "Failed to update user's email")
-		return nil, errors.New("failed to update user's email")
+		return nil, updateError(err, "failed to update user's email")
 	}
 
 	// emailUpdatedEvent := event.EmailUpdatedEvent{
@@ -912,31 +1075,31 @@ func (service *userService) UpdateEmail(ctx context.Context, user_reference stri
 }
 
 func (service *userService) UpdateDateOfBirth(ctx context.Context, user_reference string, dobDto dto.DobDto, currentUserDto dto.CurrentUserDto) (interface{}, error) {
+	return retryOnConflict(func() (interface{}, error) {
+		return service.updateDateOfBirth(ctx, user_reference, dobDto, currentUserDto)
+	})
+}
+
+func (service *userService) updateDateOfBirth(ctx context.Context, user_reference string, dobDto dto.DobDto, currentUserDto dto.CurrentUserDto) (interface{}, error) {
 	userData, err := service.GetUserByReference(ctx, user_reference)
 	if err != nil {
 		logger.LogEvent("ERROR", "Failed to retrieve user")
-		return nil, errors.New("failed to retrieve user")
+		return nil, err
 	}
 
-	user := mapper.UserDtoToUser(userData.(dto.UserDto))
+	user := userData.(entity.User)
 	//check if device is registered
-	if !isRegisteredDevice(entity.Device(currentUserDto.Device), user.Device) {
+	if !isRegisteredDevice(entity.Device(currentUserDto.Device), user) {
 		logger.LogEvent("ERROR", "This device is not registered to this user")
-		return nil, errors.New("this device is not registered to this user")
+		return nil, ErrDeviceNotRegistered
 	}
 	//check if the phone number is registered
 	if !isRegisteredPhoneNumber(currentUserDto.Phone, user.UserProfile.Phone) {
 		logger.LogEvent("ERROR", "The phone number is not registered to this user")
-		return nil, errors.New("the phone number is not registered to this user")
+		return nil, ErrPhoneNotRegistered
 	}
 	user = mapper.UpdateDobDtoToUser(user, dobDto)
 
-	result, err := service.userRepository.UpdateUser(ctx, user_reference, user)
-	if err != nil {
-		logger.LogEvent("ERROR", "Failed to update user's date of birth")
-		return nil, errors.New("failed to update user's date of birth")
-	}
-
 	dobUpdatedEvent := event.DOBUpdatedEvent{
 		Event: eto.Event{
 			EventReference:     uuid.New().String(),
@@ -945,44 +1108,53 @@ func (service *userService) UpdateDateOfBirth(ctx context.Context, user_referenc
 			EventType:          "dobupdatedevent",
 			EventSource:        configuration.ServiceConfiguration.ServiceName,
 			EventUserReference: user.UserReference,
-			EventData:          user,
+			EventData:          mapper.UserToDOBUpdatedEventData(user),
 		},
 	}
 
-	eventPublisher := publisher.NewPublisher(service.redisClient)
-	eventPublisher.PublishDOBUpdatedEvent(ctx, dobUpdatedEvent)
+	outboxEvent, err := newOutboxEvent(dobUpdatedEvent)
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to prepare event for publishing")
+		return nil, errors.New("failed to prepare event for publishing")
+	}
+
+	result, err := service.userRepository.UpdateUser(ctx, user_reference, user, outboxEvent)
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to update user's date of birth")
+		return nil, updateError(err, "failed to update user's date of birth")
+	}
 
 	return result, nil
 }
 
 func (service *userService) UpdateAddress(ctx context.Context, user_reference string, addressDto dto.AddressDto, currentUserDto dto.CurrentUserDto) (interface{}, error) {
+	return retryOnConflict(func() (interface{}, error) {
+		return service.updateAddress(ctx, user_reference, addressDto, currentUserDto)
+	})
+}
+
+func (service *userService) updateAddress(ctx context.Context, user_reference string, addressDto dto.AddressDto, currentUserDto dto.CurrentUserDto) (interface{}, error) {
 	userData, err := service.GetUserByReference(ctx, user_reference)
 	if err != nil {
 		logger.LogEvent("ERROR", "Failed to retrieve user")
-		return nil, errors.New("failed to retrieve user")
+		return nil, err
 	}
 
-	user := mapper.UserDtoToUser(userData.(dto.UserDto))
+	user := userData.(entity.User)
 
 	//check if device is registered
-	if !isRegisteredDevice(entity.Device(currentUserDto.Device), user.Device) {
+	if !isRegisteredDevice(entity.Device(currentUserDto.Device), user) {
 		logger.LogEvent("ERROR", "This device is not registered to this user")
-		return nil, errors.New("this device is not registered to this user")
+		return nil, ErrDeviceNotRegistered
 	}
 	//check if the phone number is registered
 	if !isRegisteredPhoneNumber(currentUserDto.Phone, user.UserProfile.Phone) {
 		logger.LogEvent("ERROR", "The phone number is not registered to this user")
-		return nil, errors.New("the phone number is not registered to this user")
+		return nil, ErrPhoneNotRegistered
 	}
 
 	user = mapper.UpdateAddressDtoToUser(user, addressDto)
 
-	result, err := service.userRepository.UpdateUser(ctx, user_reference, user)
-	if err != nil {
-		logger.LogEvent("ERROR", "Failed to update user's address")
-		return nil, errors.New("failed to update user's address")
-	}
-
 	addressUpdatedEvent := event.AddressUpdatedEvent{
 		Event: eto.Event{
 			EventReference:     uuid.New().String(),
@@ -991,44 +1163,53 @@ func (service *userService) UpdateAddress(ctx context.Context, user_reference st
 			EventType:          "addressupdatedevent",
 			EventSource:        configuration.ServiceConfiguration.ServiceName,
 			EventUserReference: user.UserReference,
-			EventData:          user,
+			EventData:          mapper.UserToAddressUpdatedEventData(user),
 		},
 	}
 
-	eventPublisher := publisher.NewPublisher(service.redisClient)
-	eventPublisher.PublishAddressUpdatedEvent(ctx, addressUpdatedEvent)
+	outboxEvent, err := newOutboxEvent(addressUpdatedEvent)
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to prepare event for publishing")
+		return nil, errors.New("failed to prepare event for publishing")
+	}
+
+	result, err := service.userRepository.UpdateUser(ctx, user_reference, user, outboxEvent)
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to update user's address")
+		return nil, updateError(err, "failed to update user's address")
+	}
 
 	return result, nil
 }
 
 func (service *userService) UpdatePhoto(ctx context.Context, user_reference string, photoDto dto.PhotoDto, currentUserDto dto.CurrentUserDto) (interface{}, error) {
+	return retryOnConflict(func() (interface{}, error) {
+		return service.updatePhoto(ctx, user_reference, photoDto, currentUserDto)
+	})
+}
+
+func (service *userService) updatePhoto(ctx context.Context, user_reference string, photoDto dto.PhotoDto, currentUserDto dto.CurrentUserDto) (interface{}, error) {
 	userData, err := service.GetUserByReference(ctx, user_reference)
 	if err != nil {
 		logger.LogEvent("ERROR", "Failed to retrieve user")
-		return nil, errors.New("failed to retrieve user")
+		return nil, err
 	}
 
-	user := mapper.UserDtoToUser(userData.(dto.UserDto))
+	user := userData.(entity.User)
 
 	//check if device is registered
-	if !isRegisteredDevice(entity.Device(currentUserDto.Device), user.Device) {
+	if !isRegisteredDevice(entity.Device(currentUserDto.Device), user) {
 		logger.LogEvent("ERROR", "This device is not registered to this user")
-		return nil, errors.New("this device is not registered to this user")
+		return nil, ErrDeviceNotRegistered
 	}
 	//check if the phone number is registered
 	if !isRegisteredPhoneNumber(currentUserDto.Phone, user.UserProfile.Phone) {
 		logger.LogEvent("ERROR", "The phone number is not registered to this user")
-		return nil, errors.New("the phone number is not registered to this user")
+		return nil, ErrPhoneNotRegistered
 	}
 
 	user = mapper.UpdatePhotoDtoToUser(user, photoDto)
 
-	result, err := service.userRepository.UpdateUser(ctx, user_reference, user)
-	if err != nil {
-		logger.LogEvent("ERROR", "Failed to update user's photos")
-		return nil, errors.New("failed to update user's photos")
-	}
-
 	photosUpdatedEvent := event.PhotosUpdatedEvent{
 		Event: eto.Event{
 			EventReference:     uuid.New().String(),
@@ -1037,44 +1218,140 @@ func (service *userService) UpdatePhoto(ctx context.Context, user_reference stri
 			EventType:          "photoupdatedevent",
 			EventSource:        configuration.ServiceConfiguration.ServiceName,
 			EventUserReference: user.UserReference,
-			EventData:          user,
+			EventData:          mapper.UserToPhotosUpdatedEventData(user, photoDto.Photo.PhotoReference),
 		},
 	}
 
-	eventPublisher := publisher.NewPublisher(service.redisClient)
-	eventPublisher.PublishPhotosUpdatedEvent(ctx, photosUpdatedEvent)
+	outboxEvent, err := newOutboxEvent(photosUpdatedEvent)
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to prepare event for publishing")
+		return nil, errors.New("failed to prepare event for publishing")
+	}
+
+	result, err := service.userRepository.UpdateUser(ctx, user_reference, user, outboxEvent)
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to update user's photos")
+		return nil, updateError(err, "failed to update user's photos")
+	}
 
 	return result, nil
 }
 
-func (service *userService) UpdateWallet(ctx context.Context, user_reference string, walletDto dto.UpdateWalletDto, currentUserDto dto.CurrentUserDto) (interface{}, error) {
+// VerifyPhoto - applies the moderation result to one of the user's photos. The
+// newest verified photo becomes the default, so a rejection can leave the user
+// without one.
+func (service *userService) VerifyPhoto(ctx context.Context, user_reference string, photo_reference string, photoVerificationDto dto.PhotoVerificationDto) (interface{}, error) {
+	return retryOnConflict(func() (interface{}, error) {
+		return service.verifyPhoto(ctx, user_reference, photo_reference, photoVerificationDto)
+	})
+}
+
+func (service *userService) verifyPhoto(ctx context.Context, user_reference string, photo_reference string, photoVerificationDto dto.PhotoVerificationDto) (interface{}, error) {
+	logger.LogEvent("INFO", "Verifying photo with reference: "+photo_reference)
 	userData, err := service.GetUserByReference(ctx, user_reference)
 	if err != nil {
 		logger.LogEvent("ERROR", "Failed to retrieve user")
-		return nil, errors.New("failed to retrieve user")
+		return nil, err
 	}
+	user := userData.(entity.User)
 
-	user := mapper.UserDtoToUser(userData.(dto.UserDto))
-
-	//check if device is registered
-	if !isRegisteredDevice(entity.Device(currentUserDto.Device), user.Device) {
-		logger.LogEvent("ERROR", "This device is not registered to this user")
-		return nil, errors.New("this device is not registered to this user")
+	if photoVerificationDto.Status != shared.PhotoVerified && photoVerificationDto.Status != shared.PhotoRejected {
+		logger.LogEvent("ERROR", "Invalid photo verification status: "+photoVerificationDto.Status)
+		return nil, ErrInvalidVerificationStatus.WithMessage("invalid photo verification status")
+	}
+
+	photos := user.UserProfile.Photos
+	found := false
+	for i := range photos {
+		if photos[i].PhotoReference == photo_reference {
+			photos[i].Status = photoVerificationDto.Status
+			photos[i].IsVerified = photoVerificationDto.Status == shared.PhotoVerified
+			photos[i].RejectionReason = ""
+			photos[i].VerifiedOn = ""
+			if photos[i].IsVerified {
+				verifiedOn := photoVerificationDto.VerifiedOn
+				if verifiedOn == "" {
+					verifiedOn = time.Now().Format(time.RFC3339)
+				}
+				photos[i].VerifiedOn = verifiedOn
+			} else {
+				photos[i].RejectionReason = photoVerificationDto.RejectionReason
+			}
+			found = true
+			break
+		}
+	}
+	if !found {
+		logger.LogEvent("ERROR", "Photo with reference: "+photo_reference+" not found for the user")
+		return nil, ErrPhotoNotFound
+	}
+
+	// Photos are kept oldest first
+	newestVerified := -1
+	for i := range photos {
+		if photos[i].IsVerified {
+			newestVerified = i
+		}
+	}
+	for i := range photos {
+		photos[i].IsDefault = i == newestVerified
+	}
+
+	photoStatusUpdatedEvent := event.PhotoStatusUpdatedEvent{
+		Event: eto.Event{
+			EventReference:     uuid.New().String(),
+			EventName:          "photostatusupdatedevent",
+			EventDate:          time.Now().Format(time.RFC3339),
+			EventType:          "photostatusupdatedevent",
+			EventSource:        configuration.ServiceConfiguration.ServiceName,
+			EventUserReference: user.UserReference,
+			EventData:          mapper.UserToPhotoStatusUpdatedEventData(user, photo_reference),
+		},
+	}
+
+	outboxEvent, err := newOutboxEvent(photoStatusUpdatedEvent)
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to prepare event for publishing")
+		return nil, errors.New("failed to prepare event for publishing")
+	}
+
+	result, err := service.userRepository.UpdateUser(ctx, user_reference, user, outboxEvent)
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to update user's photos")
+		return nil, updateError(err, "failed to update user's photos")
+	}
+
+	return result, nil
+}
+
+func (service *userService) UpdateWallet(ctx context.Context, user_reference string, walletDto dto.UpdateWalletDto, currentUserDto dto.CurrentUserDto) (interface{}, error) {
+	return retryOnConflict(func() (interface{}, error) {
+		return service.updateWallet(ctx, user_reference, walletDto, currentUserDto)
+	})
+}
+
+func (service *userService) updateWallet(ctx context.Context, user_reference string, walletDto dto.UpdateWalletDto, currentUserDto dto.CurrentUserDto) (interface{}, error) {
+	userData, err := service.GetUserByReference(ctx, user_reference)
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to retrieve user")
+		return nil, err
+	}
+
+	user := userData.(entity.User)
+
+	//check if device is registered
+	if !isRegisteredDevice(entity.Device(currentUserDto.Device), user) {
+		logger.LogEvent("ERROR", "This device is not registered to this user")
+		return nil, ErrDeviceNotRegistered
 	}
 	//check if the phone number is registered
 	if !isRegisteredPhoneNumber(currentUserDto.Phone, user.UserProfile.Phone) {
 		logger.LogEvent("ERROR", "The phone number is not registered to this user")
-		return nil, errors.New("the phone number is not registered to this user")
+		return nil, ErrPhoneNotRegistered
 	}
 
 	user = mapper.UpdateWalletDtoToWallet(user, walletDto)
 
-	result, err := service.userRepository.UpdateUser(ctx, user_reference, user)
-	if err != nil {
-		logger.LogEvent("ERROR", "Failed to update user's wallet")
-		return nil, errors.New("failed to update user's wallet")
-	}
-
 	walletUpdatedEvent := event.WalletUpdatedEvent{
 		Event: eto.Event{
 			EventReference:     uuid.New().String(),
@@ -1083,12 +1360,21 @@ func (service *userService) UpdateWallet(ctx context.Context, user_reference str
 			EventType:          "walletupdatedevent",
 			EventSource:        configuration.ServiceConfiguration.ServiceName,
 			EventUserReference: user.UserReference,
-			EventData:          user,
+			EventData:          mapper.UserToWalletUpdatedEventData(user),
 		},
 	}
 
-	eventPublisher := publisher.NewPublisher(service.redisClient)
-	eventPublisher.PublishWalletUpdatedEvent(ctx, walletUpdatedEvent)
+	outboxEvent, err := newOutboxEvent(walletUpdatedEvent)
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to prepare event for publishing")
+		return nil, errors.New("failed to prepare event for publishing")
+	}
+
+	result, err := service.userRepository.UpdateUser(ctx, user_reference, user, outboxEvent)
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to update user's wallet")
+		return nil, updateError(err, "failed to update user's wallet")
+	}
 
 	return result, nil
 }
@@ -1097,30 +1383,24 @@ func (service *userService) AddBank(ctx context.Context, user_reference string,
 	userData, err := service.GetUserByReference(ctx, user_reference)
 	if err != nil {
 		logger.LogEvent("ERROR", "Failed to retrieve user")
-		return nil, errors.New("failed to retrieve user")
+		return nil, err
 	}
 
-	user := mapper.UserDtoToUser(userData.(dto.UserDto))
+	user := userData.(entity.User)
 
 	//check if device is registered
-	if !isRegisteredDevice(entity.Device(currentUserDto.Device), user.Device) {
+	if !isRegisteredDevice(entity.Device(currentUserDto.Device), user) {
 		logger.LogEvent("ERROR", "This device is not registered to this user")
-		return nil, errors.New("this device is not registered to this user")
+		return nil, ErrDeviceNotRegistered
 	}
 	//check if the phone number is registered
 	if !isRegisteredPhoneNumber(currentUserDto.Phone, user.UserProfile.Phone) {
 		logger.LogEvent("ERROR", "The phone number is not registered to this user")
-		return nil, errors.New("the phone number is not registered to this user")
+		return nil, ErrPhoneNotRegistered
 	}
 
 	user = mapper.AddBankDtoToBank(user, bankDto)
 
-	result, err := service.userRepository.UpdateUser(ctx, user_reference, user)
-	if err != nil {
-		logger.LogEvent("ERROR", "Failed to add bank for the user")
-		return nil, errors.New("failed to add bank for the user")
-	}
-
 	bankAddedEvent := event.BankAddedEvent{
 		Event: eto.Event{
 			EventReference:     uuid.New().String(),
@@ -1129,47 +1409,144 @@ func (service *userService) AddBank(ctx context.Context, user_reference string,
 			EventType:          "bankaddedevent",
 			EventSource:        configuration.ServiceConfiguration.ServiceName,
 			EventUserReference: user.UserReference,
-			EventData:          user,
+			EventData:          mapper.UserToBankAddedEventData(user),
 		},
 	}
 
-	eventPublisher := publisher.NewPublisher(service.redisClient)
-	eventPublisher.PublishBankAddedEvent(ctx, bankAddedEvent)
+	outboxEvent, err := newOutboxEvent(bankAddedEvent)
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to prepare event for publishing")
+		return nil, errors.New("failed to prepare event for publishing")
+	}
+
+	result, err := service.userRepository.PushBank(ctx, user_reference, user.BankAccounts[len(user.BankAccounts)-1], outboxEvent)
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to add bank for the user")
+		return nil, updateError(err, "failed to add bank for the user")
+	}
 
 	return result, nil
 }
 
 func (service *userService) UpdateBank(ctx context.Context, user_reference string, bank_reference string, bankDto dto.BankDto, currentUserDto dto.CurrentUserDto) (interface{}, error) {
+	return retryOnConflict(func() (interface{}, error) {
+		return service.updateBank(ctx, user_reference, bank_reference, bankDto, currentUserDto)
+	})
+}
+
+func (service *userService) updateBank(ctx context.Context, user_reference string, bank_reference string, bankDto dto.BankDto, currentUserDto dto.CurrentUserDto) (interface{}, error) {
 	userData, err := service.GetUserByReference(ctx, user_reference)
 	if err != nil {
 		logger.LogEvent("ERROR", "Failed to retrieve user")
-		return nil, errors.New("failed to retrieve user")
+		return nil, err
 	}
 
-	user := mapper.UserDtoToUser(userData.(dto.UserDto))
+	user := userData.(entity.User)
 
 	//check if device is registered
-	if !isRegisteredDevice(entity.Device(currentUserDto.Device), user.Device) {
+	if !isRegisteredDevice(entity.Device(currentUserDto.Device), user) {
 		logger.LogEvent("ERROR", "This device is not registered to this user")
-		return nil, errors.New("this device is not registered to this user")
+		return nil, ErrDeviceNotRegistered
 	}
 	//check if the phone number is registered
 	if !isRegisteredPhoneNumber(currentUserDto.Phone, user.UserProfile.Phone) {
 		logger.LogEvent("ERROR", "The phone number is not registered to this user")
-		return nil, errors.New("the phone number is not registered to this user")
+		return nil, ErrPhoneNotRegistered
 	}
 
 	for i, b := range user.BankAccounts {
 		if b.BankReference == bank_reference {
-			user.BankAccounts[i] = mapper.UpdateBankDtoToBank(bankDto, bank_reference)
+			bank := mapper.UpdateBankDtoToBank(bankDto, bank_reference)
+			bank.IntegrationType = b.IntegrationType
+			bank.IntegrationReference = b.IntegrationReference
+			bank.Status, bank.StatusReason, bank.VerifiedOn = b.Status, b.StatusReason, b.VerifiedOn
+			if bank.AccountNumber != b.AccountNumber {
+				// A different account has to be verified afresh
+				bank.Status, bank.StatusReason, bank.VerifiedOn = shared.VerificationPending, "", ""
+			}
+			if bank.Status != shared.VerificationVerified {
+				bank.IsDefault = false
+			}
+			user.BankAccounts[i] = bank
 			break
 		}
 	}
 
-	result, err := service.userRepository.UpdateUser(ctx, user_reference, user)
+	bankUpdatedEvent := event.BankUpdatedEvent{
+		Event: eto.Event{
+			EventReference:     uuid.New().String(),
+			EventName:          "bankupdatedevent",
+			EventDate:          time.Now().Format(time.RFC3339),
+			EventType:          "bankupdatedevent",
+			EventSource:        configuration.ServiceConfiguration.ServiceName,
+			EventUserReference: user.UserReference,
+			EventData:          mapper.UserToBankUpdatedEventData(user, bank_reference),
+		},
+	}
+
+	outboxEvent, err := newOutboxEvent(bankUpdatedEvent)
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to prepare event for publishing")
+		return nil, errors.New("failed to prepare event for publishing")
+	}
+
+	result, err := service.userRepository.UpdateUser(ctx, user_reference, user, outboxEvent)
 	if err != nil {
 		logger.LogEvent("ERROR", "Failed to update bank for the user")
-		return nil, errors.New("failed to update bank for the user")
+		return nil, updateError(err, "failed to update bank for the user")
+	}
+
+	return result, nil
+}
+
+// VerifyBank - applies the payment integration service's verification of one
+// of the user's bank accounts. A bank account that fails verification stops being the default.
+func (service *userService) VerifyBank(ctx context.Context, user_reference string, bank_reference string, verificationDto dto.PaymentMethodVerificationDto) (interface{}, error) {
+	return retryOnConflict(func() (interface{}, error) {
+		return service.verifyBank(ctx, user_reference, bank_reference, verificationDto)
+	})
+}
+
+func (service *userService) verifyBank(ctx context.Context, user_reference string, bank_reference string, verificationDto dto.PaymentMethodVerificationDto) (interface{}, error) {
+	logger.LogEvent("INFO", "Verifying bank account with reference: "+bank_reference)
+	userData, err := service.GetUserByReference(ctx, user_reference)
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to retrieve user")
+		return nil, err
+	}
+	user := userData.(entity.User)
+
+	switch verificationDto.Status {
+	case shared.VerificationPending, shared.VerificationVerified, shared.VerificationFailed:
+	default:
+		logger.LogEvent("ERROR", "Invalid bank account verification status: "+verificationDto.Status)
+		return nil, ErrInvalidVerificationStatus.WithMessage("invalid bank account verification status")
+	}
+
+	found := false
+	for i, bankAccount := range user.BankAccounts {
+		if bankAccount.BankReference == bank_reference {
+			user.BankAccounts[i].IntegrationType = verificationDto.IntegrationType
+			user.BankAccounts[i].IntegrationReference = verificationDto.IntegrationReference
+			user.BankAccounts[i].Status = verificationDto.Status
+			user.BankAccounts[i].StatusReason = verificationDto.StatusReason
+			user.BankAccounts[i].VerifiedOn = ""
+			if verificationDto.Status == shared.VerificationVerified {
+				verifiedOn := verificationDto.VerifiedOn
+				if verifiedOn == "" {
+					verifiedOn = time.Now().Format(time.RFC3339)
+				}
+				user.BankAccounts[i].VerifiedOn = verifiedOn
+			} else {
+				user.BankAccounts[i].IsDefault = false
+			}
+			found = true
+			break
+		}
+	}
+	if !found {
+		logger.LogEvent("ERROR", "Bank account with reference: "+bank_reference+" not found for the user")
+		return nil, ErrBankNotFound
 	}
 
 	bankUpdatedEvent := event.BankUpdatedEvent{
@@ -1180,12 +1557,21 @@ func (service *userService) UpdateBank(ctx context.Context, user_reference strin
 			EventType:          "bankupdatedevent",
 			EventSource:        configuration.ServiceConfiguration.ServiceName,
 			EventUserReference: user.UserReference,
-			EventData:          user,
+			EventData:          mapper.UserToBankUpdatedEventData(user, bank_reference),
 		},
 	}
 
-	eventPublisher := publisher.NewPublisher(service.redisClient)
-	eventPublisher.PublishBankUpdatedEvent(ctx, bankUpdatedEvent)
+	outboxEvent, err := newOutboxEvent(bankUpdatedEvent)
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to prepare event for publishing")
+		return nil, errors.New("failed to prepare event for publishing")
+	}
+
+	result, err := service.userRepository.UpdateUser(ctx, user_reference, user, outboxEvent)
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to update bank account verification for the user")
+		return nil, updateError(err, "failed to update bank account verification for the user")
+	}
 
 	return result, nil
 }
@@ -1194,35 +1580,29 @@ func (service *userService) AddCard(ctx context.Context, user_reference string,
 	userData, err := service.GetUserByReference(ctx, user_reference)
 	if err != nil {
 		logger.LogEvent("ERROR", "Failed to retrieve user")
-		return nil, errors.New("failed to retrieve user")
+		return nil, err
 	}
 
-	user := mapper.UserDtoToUser(userData.(dto.UserDto))
+	user := userData.(entity.User)
 
 	//check if device is registered
-	if !isRegisteredDevice(entity.Device(currentUserDto.Device), user.Device) {
+	if !isRegisteredDevice(entity.Device(currentUserDto.Device), user) {
 		logger.LogEvent("ERROR", "This device is not registered to this user")
-		return nil, errors.New("this device is not registered to this user")
+		return nil, ErrDeviceNotRegistered
 	}
 	//check if the phone number is registered
 	if !isRegisteredPhoneNumber(currentUserDto.Phone, user.UserProfile.Phone) {
 		logger.LogEvent("ERROR", "The phone number is not registered to this user")
-		return nil, errors.New("the phone number is not registered to this user")
+		return nil, ErrPhoneNotRegistered
 	}
 	//check if expry month and year is valid
 	if !isValidExpiryMonthAndYear(cardDto.ExpiryMonth, cardDto.ExpiryYear) {
 		logger.LogEvent("ERROR", "Invalid expiry month or year")
-		return nil, errors.New("invalid expiry month or year")
+		return nil, ErrInvalidCardExpiry
 	}
 
 	user = mapper.AddCardDtoToCard(user, cardDto)
 
-	result, err := service.userRepository.UpdateUser(ctx, user_reference, user)
-	if err != nil {
-		logger.LogEvent("ERROR", "Failed to add card for the user")
-		return nil, errors.New("failed to add card for the user")
-	}
-
 	cardAddedEvent := event.CardAddedEvent{
 		Event: eto.Event{
 			EventReference:     uuid.New().String(),
@@ -1231,52 +1611,149 @@ func (service *userService) AddCard(ctx context.Context, user_reference string,
 			EventType:          "cardaddedevent",
 			EventSource:        configuration.ServiceConfiguration.ServiceName,
 			EventUserReference: user.UserReference,
-			EventData:          user,
+			EventData:          mapper.UserToCardAddedEventData(user),
 		},
 	}
 
-	eventPublisher := publisher.NewPublisher(service.redisClient)
-	eventPublisher.PublishCardAddedEvent(ctx, cardAddedEvent)
+	outboxEvent, err := newOutboxEvent(cardAddedEvent)
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to prepare event for publishing")
+		return nil, errors.New("failed to prepare event for publishing")
+	}
+
+	result, err := service.userRepository.PushCard(ctx, user_reference, user.Cards[len(user.Cards)-1], outboxEvent)
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to add card for the user")
+		return nil, updateError(err, "failed to add card for the user")
+	}
 
 	return result, nil
 }
 
 func (service *userService) UpdateCard(ctx context.Context, user_reference string, card_reference string, cardDto dto.CardDto, currentUserDto dto.CurrentUserDto) (interface{}, error) {
+	return retryOnConflict(func() (interface{}, error) {
+		return service.updateCard(ctx, user_reference, card_reference, cardDto, currentUserDto)
+	})
+}
+
+func (service *userService) updateCard(ctx context.Context, user_reference string, card_reference string, cardDto dto.CardDto, currentUserDto dto.CurrentUserDto) (interface{}, error) {
 	userData, err := service.GetUserByReference(ctx, user_reference)
 	if err != nil {
 		logger.LogEvent("ERROR", "Failed to retrieve user")
-		return nil, errors.New("failed to retrieve user")
+		return nil, err
 	}
 
-	user := mapper.UserDtoToUser(userData.(dto.UserDto))
+	user := userData.(entity.User)
 
 	//check if device is registered
-	if !isRegisteredDevice(entity.Device(currentUserDto.Device), user.Device) {
+	if !isRegisteredDevice(entity.Device(currentUserDto.Device), user) {
 		logger.LogEvent("ERROR", "This device is not registered to this user")
-		return nil, errors.New("this device is not registered to this user")
+		return nil, ErrDeviceNotRegistered
 	}
 	//check if the phone number is registered
 	if !isRegisteredPhoneNumber(currentUserDto.Phone, user.UserProfile.Phone) {
 		logger.LogEvent("ERROR", "The phone number is not registered to this user")
-		return nil, errors.New("the phone number is not registered to this user")
+		return nil, ErrPhoneNotRegistered
 	}
 	//check if expry month and year is valid
 	if !isValidExpiryMonthAndYear(cardDto.ExpiryMonth, cardDto.ExpiryYear) {
 		logger.LogEvent("ERROR", "Invalid expiry month or year")
-		return nil, errors.New("invalid expiry month or year")
+		return nil, ErrInvalidCardExpiry
 	}
 
 	for i, c := range user.Cards {
 		if c.CardReference == card_reference {
-			user.Cards[i] = mapper.UpdateCardDtoToCard(cardDto, card_reference)
+			card := mapper.UpdateCardDtoToCard(cardDto, card_reference)
+			card.IntegrationType = c.IntegrationType
+			card.IntegrationReference = c.IntegrationReference
+			card.Status, card.StatusReason, card.VerifiedOn = c.Status, c.StatusReason, c.VerifiedOn
+			if card.Pan != c.Pan {
+				// A different card has to be verified afresh
+				card.Status, card.StatusReason, card.VerifiedOn = shared.VerificationPending, "", ""
+			}
+			if card.Status != shared.VerificationVerified {
+				card.IsDefault = false
+			}
+			user.Cards[i] = card
 			break
 		}
 	}
 
-	result, err := service.userRepository.UpdateUser(ctx, user_reference, user)
+	cardUpdatedEvent := event.CardUpdatedEvent{
+		Event: eto.Event{
+			EventReference:     uuid.New().String(),
+			EventName:          "cardupdatedevent",
+			EventDate:          time.Now().Format(time.RFC3339),
+			EventType:          "cardupdatedevent",
+			EventSource:        configuration.ServiceConfiguration.ServiceName,
+			EventUserReference: user.UserReference,
+			EventData:          mapper.UserToCardUpdatedEventData(user, card_reference),
+		},
+	}
+
+	outboxEvent, err := newOutboxEvent(cardUpdatedEvent)
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to prepare event for publishing")
+		return nil, errors.New("failed to prepare event for publishing")
+	}
+
+	result, err := service.userRepository.UpdateUser(ctx, user_reference, user, outboxEvent)
 	if err != nil {
 		logger.LogEvent("ERROR", "Failed to update card for the user")
-		return nil, errors.New("failed to update card for the user")
+		return nil, updateError(err, "failed to update card for the user")
+	}
+
+	return result, nil
+}
+
+// VerifyCard - applies the payment integration service's verification of one
+// of the user's cards. A card that fails verification stops being the default.
+func (service *userService) VerifyCard(ctx context.Context, user_reference string, card_reference string, verificationDto dto.PaymentMethodVerificationDto) (interface{}, error) {
+	return retryOnConflict(func() (interface{}, error) {
+		return service.verifyCard(ctx, user_reference, card_reference, verificationDto)
+	})
+}
+
+func (service *userService) verifyCard(ctx context.Context, user_reference string, card_reference string, verificationDto dto.PaymentMethodVerificationDto) (interface{}, error) {
+	logger.LogEvent("INFO", "Verifying card with reference: "+card_reference)
+	userData, err := service.GetUserByReference(ctx, user_reference)
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to retrieve user")
+		return nil, err
+	}
+	user := userData.(entity.User)
+
+	switch verificationDto.Status {
+	case shared.VerificationPending, shared.VerificationVerified, shared.VerificationFailed:
+	default:
+		logger.LogEvent("ERROR", "Invalid card verification status: "+verificationDto.Status)
+		return nil, ErrInvalidVerificationStatus.WithMessage("invalid card verification status")
+	}
+
+	found := false
+	for i, card := range user.Cards {
+		if card.CardReference == card_reference {
+			user.Cards[i].IntegrationType = verificationDto.IntegrationType
+			user.Cards[i].IntegrationReference = verificationDto.IntegrationReference
+			user.Cards[i].Status = verificationDto.Status
+			user.Cards[i].StatusReason = verificationDto.StatusReason
+			user.Cards[i].VerifiedOn = ""
+			if verificationDto.Status == shared.VerificationVerified {
+				verifiedOn := verificationDto.VerifiedOn
+				if verifiedOn == "" {
+					verifiedOn = time.Now().Format(time.RFC3339)
+				}
+				user.Cards[i].VerifiedOn = verifiedOn
+			} else {
+				user.Cards[i].IsDefault = false
+			}
+			found = true
+			break
+		}
+	}
+	if !found {
+		logger.LogEvent("ERROR", "Card with reference: "+card_reference+" not found for the user")
+		return nil, ErrCardNotFound
 	}
 
 	cardUpdatedEvent := event.CardUpdatedEvent{
@@ -1287,44 +1764,53 @@ func (service *userService) UpdateCard(ctx context.Context, user_reference strin
 			EventType:          "cardupdatedevent",
 			EventSource:        configuration.ServiceConfiguration.ServiceName,
 			EventUserReference: user.UserReference,
-			EventData:          user,
+			EventData:          mapper.UserToCardUpdatedEventData(user, card_reference),
 		},
 	}
 
-	eventPublisher := publisher.NewPublisher(service.redisClient)
-	eventPublisher.PublishCardUpdatedEvent(ctx, cardUpdatedEvent)
+	outboxEvent, err := newOutboxEvent(cardUpdatedEvent)
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to prepare event for publishing")
+		return nil, errors.New("failed to prepare event for publishing")
+	}
+
+	result, err := service.userRepository.UpdateUser(ctx, user_reference, user, outboxEvent)
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to update card verification for the user")
+		return nil, updateError(err, "failed to update card verification for the user")
+	}
 
 	return result, nil
 }
 
 func (service *userService) UpdateNotificationOptions(ctx context.Context, user_reference string, optionsDto dto.UpdateNotificationOptionsDto, currentUserDto dto.CurrentUserDto) (interface{}, error) {
+	return retryOnConflict(func() (interface{}, error) {
+		return service.updateNotificationOptions(ctx, user_reference, optionsDto, currentUserDto)
+	})
+}
+
+func (service *userService) updateNotificationOptions(ctx context.Context, user_reference string, optionsDto dto.UpdateNotificationOptionsDto, currentUserDto dto.CurrentUserDto) (interface{}, error) {
 	userData, err := service.GetUserByReference(ctx, user_reference)
 	if err != nil {
 		logger.LogEvent("ERROR", "Failed to retrieve user")
-		return nil, errors.New("failed to retrieve user")
+		return nil, err
 	}
 
-	user := mapper.UserDtoToUser(userData.(dto.UserDto))
+	user := userData.(entity.User)
 
 	//check if device is registered
-	if !isRegisteredDevice(entity.Device(currentUserDto.Device), user.Device) {
+	if !isRegisteredDevice(entity.Device(currentUserDto.Device), user) {
 		logger.LogEvent("ERROR", "This device is not registered to this user")
-		return nil, errors.New("this device is not registered to this user")
+		return nil, ErrDeviceNotRegistered
 	}
 	//check if the phone number is registered
 	if !isRegisteredPhoneNumber(currentUserDto.Phone, user.UserProfile.Phone) {
 		logger.LogEvent("ERROR", "The phone number is not registered to this user")
-		return nil, errors.New("the phone number is not registered to this user")
+		return nil, ErrPhoneNotRegistered
 	}
 
 	user = mapper.UpdateNotificationOptionsDtoToNotificationOptions(user, optionsDto)
 
-	result, err := service.userRepository.UpdateUser(ctx, user_reference, user)
-	if err != nil {
-		logger.LogEvent("ERROR", "Failed to update user's notification options")
-		return nil, errors.New("failed to update user's notification options")
-	}
-
 	notificationOptionsUpdatedEvent := event.NotificationOptionsUpdatedEvent{
 		Event: eto.Event{
 			EventReference:     uuid.New().String(),
@@ -1333,50 +1819,198 @@ func (service *userService) UpdateNotificationOptions(ctx context.Context, user_
 			EventType:          "notificationoptionsupdatedevent",
 			EventSource:        configuration.ServiceConfiguration.ServiceName,
 			EventUserReference: user.UserReference,
-			EventData:          user,
+			EventData:          mapper.UserToNotificationOptionsUpdatedEventData(user),
 		},
 	}
 
-	eventPublisher := publisher.NewPublisher(service.redisClient)
-	eventPublisher.PublishNotificationOptionsUpdatedEvent(ctx, notificationOptionsUpdatedEvent)
+	outboxEvent, err := newOutboxEvent(notificationOptionsUpdatedEvent)
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to prepare event for publishing")
+		return nil, errors.New("failed to prepare event for publishing")
+	}
+
+	result, err := service.userRepository.UpdateUser(ctx, user_reference, user, outboxEvent)
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to update user's notification options")
+		return nil, updateError(err, "failed to update user's notification options")
+	}
 
 	return result, nil
 }
 
 func (service *userService) UpdateDevice(ctx context.Context, user_reference string, deviceDto dto.UpdateDeviceDto, currentUserDto dto.CurrentUserDto) (interface{}, error) {
+	return retryOnConflict(func() (interface{}, error) {
+		return service.updateDevice(ctx, user_reference, deviceDto, currentUserDto)
+	})
+}
+
+func (service *userService) updateDevice(ctx context.Context, user_reference string, deviceDto dto.UpdateDeviceDto, currentUserDto dto.CurrentUserDto) (interface{}, error) {
 	userData, err := service.GetUserByReference(ctx, user_reference)
 	if err != nil {
 		logger.LogEvent("ERROR", "Failed to retrieve user")
-		return nil, errors.New("failed to retrieve user")
+		return nil, err
 	}
 
-	user := mapper.UserDtoToUser(userData.(dto.UserDto))
+	user := userData.(entity.User)
 
 	//check if device is registered
-	if !isRegisteredDevice(entity.Device(currentUserDto.Device), user.Device) {
+	if !isRegisteredDevice(entity.Device(currentUserDto.Device), user) {
 		logger.LogEvent("ERROR", "This device is not registered to this user")
-		return nil, errors.New("this device is not registered to this user")
+		return nil, ErrDeviceNotRegistered
 	}
 	//check if the phone number is registered
 	if !isRegisteredPhoneNumber(currentUserDto.Phone, user.UserProfile.Phone) {
 		logger.LogEvent("ERROR", "The phone number is not registered to this user")
-		return nil, errors.New("the phone number is not registered to this user")
+		return nil, ErrPhoneNotRegistered
 	}
 
 	//check if the new device is registered at all here, if it is registered stop
-	registeredUserwithDevice, _ := service.GetUserByDevice(ctx, dto.DeviceDto(deviceDto.NewDevice))
-	if registeredUserwithDevice != nil {
+	if isRegisteredDevice(deviceDto.NewDevice, user) {
 		logger.LogEvent("ERROR", "This device is already registered")
-		return nil, errors.New("this device is already registered")
+		return nil, ErrDeviceAlreadyRegistered
+	}
+	if err := service.checkDeviceUnbound(ctx, user.UserReference, deviceDto.NewDevice); err != nil {
+		return nil, err
 	}
 
-	user = mapper.UpdateDeviceDtoToDevice(user, deviceDto)
-	result, err := service.userRepository.UpdateUser(ctx, user_reference, user)
+	// the new device is trusted once the otp sent to the registered phone confirms its binding
+	return service.startDeviceBinding(ctx, user, deviceDto.NewDevice, deviceDto.Name, user.UserProfile.Phone, "sms")
+}
+
+// RequestDeviceBinding - starts binding the requesting device to the user, for
+// a user who no longer has a trusted device at hand. The device stays pending
+// until the otp sent to the registered phone number or verified email is validated.
+func (service *userService) RequestDeviceBinding(ctx context.Context, user_reference string, bindDeviceDto dto.BindDeviceDto, currentUserDto dto.CurrentUserDto) (interface{}, error) {
+	return retryOnConflict(func() (interface{}, error) {
+		return service.requestDeviceBinding(ctx, user_reference, bindDeviceDto, currentUserDto)
+	})
+}
+
+func (service *userService) requestDeviceBinding(ctx context.Context, user_reference string, bindDeviceDto dto.BindDeviceDto, currentUserDto dto.CurrentUserDto) (interface{}, error) {
+	logger.LogEvent("INFO", "Requesting device binding for user with reference: "+user_reference)
+
+	userData, err := service.GetUserByReference(ctx, user_reference)
 	if err != nil {
-		logger.LogEvent("ERROR", "Failed to update device for the user")
-		return nil, errors.New("failed to update device for the user")
+		logger.LogEvent("ERROR", "Failed to retrieve user")
+		return nil, err
+	}
+	user := userData.(entity.User)
+
+	//check if the phone number is registered
+	if !isRegisteredPhoneNumber(currentUserDto.Phone, user.UserProfile.Phone) {
+		logger.LogEvent("ERROR", "The phone number is not registered to this user")
+		return nil, ErrPhoneNotRegistered
+	}
+
+	device := entity.Device(currentUserDto.Device)
+	if isRegisteredDevice(device, user) {
+		logger.LogEvent("ERROR", "This device is already registered")
+		return nil, ErrDeviceAlreadyRegistered
+	}
+	if err := service.checkDeviceUnbound(ctx, user.UserReference, device); err != nil {
+		return nil, err
+	}
+
+	// the otp must reach the user through a contact they already own
+	isRegisteredEmail := user.UserProfile.IsVerifiedEmail && bindDeviceDto.Contact == user.UserProfile.Email
+	if bindDeviceDto.Contact != user.UserProfile.Phone && !isRegisteredEmail {
+		logger.LogEvent("ERROR", "otp for device binding must go to the registered phone number or verified email")
+		return nil, ErrInvalidOtpContact.WithMessage("otp for device binding must go to the registered phone number or verified email")
+	}
+
+	return service.startDeviceBinding(ctx, user, device, bindDeviceDto.Name, bindDeviceDto.Contact, bindDeviceDto.Channel)
+}
+
+// checkDeviceUnbound - the device must not be trusted by, or pending binding
+// to, any other user.
+func (service *userService) checkDeviceUnbound(ctx context.Context, user_reference string, device entity.Device) error {
+	_, err := service.userRepository.GetOtherUserByDevice(ctx, user_reference, device)
+	if err == nil {
+		logger.LogEvent("ERROR", "This device is already registered")
+		return ErrDeviceAlreadyRegistered
+	}
+	if !errorhelper.IsNotFound(err) {
+		logger.LogEvent("ERROR", "Failed to look up the device: "+err.Error())
+		return err
+	}
+	return nil
+}
+
+// startDeviceBinding - registers the device as pending and queues the otp that
+// confirms its binding, saved together so a binding is never left without its otp.
+func (service *userService) startDeviceBinding(ctx context.Context, user entity.User, device entity.Device, name string, contact string, channel string) (interface{}, error) {
+	user = mapper.AddPendingDevice(user, device, name)
+
+	createOtpRequestEvent := event.OtpRequestCreatedEvent{
+		Event: eto.Event{
+			EventReference:     uuid.New().String(),
+			EventName:          "CREATEOTPREQUESTEVENT",
+			EventDate:          time.Now().Format(time.RFC3339),
+			EventType:          "bind_device",
+			EventSource:        configuration.ServiceConfiguration.ServiceName,
+			EventUserReference: user.UserReference,
+			EventData: events.OtpRequestCreatedEventData{
+				SchemaVersion: events.SchemaVersion,
+				UserReference: user.UserReference,
+				Contact:       contact,
+				Channel:       channel,
+				Device:        dto.DeviceDto(device),
+			},
+		},
+	}
+
+	outboxEvent, err := newOutboxEvent(createOtpRequestEvent, createOtpRequestEvent.EventType)
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to prepare event for publishing")
+		return nil, errors.New("failed to prepare event for publishing")
+	}
+
+	_, err = service.userRepository.UpdateUser(ctx, user.UserReference, user, outboxEvent)
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to request device binding for the user")
+		return nil, updateError(err, "failed to request device binding for the user")
 	}
 
+	return user.UserReference, nil
+}
+
+// CompleteDeviceBinding - trusts a pending device once its binding otp has been
+// validated and makes it the user's device. Its outgoing transactions are limited
+// for the cooling-off period.
+func (service *userService) CompleteDeviceBinding(ctx context.Context, user_reference string, device entity.Device) (interface{}, error) {
+	return retryOnConflict(func() (interface{}, error) {
+		return service.completeDeviceBinding(ctx, user_reference, device)
+	})
+}
+
+func (service *userService) completeDeviceBinding(ctx context.Context, user_reference string, device entity.Device) (interface{}, error) {
+	logger.LogEvent("INFO", "Completing device binding for user with reference: "+user_reference)
+
+	userData, err := service.GetUserByReference(ctx, user_reference)
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to retrieve user")
+		return nil, err
+	}
+	user := userData.(entity.User)
+
+	pending := -1
+	for i, registered := range user.Devices {
+		if registered.Status == shared.DevicePending && registered.Is(device) {
+			pending = i
+		}
+	}
+	if pending == -1 {
+		logger.LogEvent("ERROR", "No pending binding for device "+device.DeviceReference)
+		return nil, ErrNoPendingDeviceBinding
+	}
+
+	now := time.Now()
+	user.Devices[pending].Status = shared.DeviceTrusted
+	user.Devices[pending].LastSeenOn = now.Format(time.RFC3339)
+	user.Devices[pending].CoolingOffUntil = now.Add(deviceCoolingOff()).Format(time.RFC3339)
+	user.Devices[pending].CoolingOffSent = 0
+	user.Device = user.Devices[pending].Device
+
 	deviceUpdatedEvent := event.DeviceUpdatedEvent{
 		Event: eto.Event{
 			EventReference:     uuid.New().String(),
@@ -1385,44 +2019,168 @@ func (service *userService) UpdateDevice(ctx context.Context, user_reference str
 			EventType:          "deviceupdatedevent",
 			EventSource:        configuration.ServiceConfiguration.ServiceName,
 			EventUserReference: user.UserReference,
-			EventData:          user,
+			EventData:          mapper.UserToDeviceUpdatedEventData(user),
 		},
 	}
 
-	eventPublisher := publisher.NewPublisher(service.redisClient)
-	eventPublisher.PublishDeviceUpdatedEvent(ctx, deviceUpdatedEvent)
+	outboxEvent, err := newOutboxEvent(deviceUpdatedEvent)
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to prepare event for publishing")
+		return nil, errors.New("failed to prepare event for publishing")
+	}
+
+	result, err := service.userRepository.UpdateUser(ctx, user_reference, user, outboxEvent)
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to update device for the user")
+		return nil, updateError(err, "failed to update device for the user")
+	}
 
 	return result, nil
 }
 
-func (service *userService) AddDocumentation(ctx context.Context, user_reference string, documentationDto dto.AddDocumentationDto, currentUserDto dto.CurrentUserDto) (interface{}, error) {
+func (service *userService) ListDevices(ctx context.Context, user_reference string) (interface{}, error) {
+	logger.LogEvent("INFO", "Listing devices for user with reference: "+user_reference)
+
 	userData, err := service.GetUserByReference(ctx, user_reference)
 	if err != nil {
 		logger.LogEvent("ERROR", "Failed to retrieve user")
-		return nil, errors.New("failed to retrieve user")
+		return nil, err
 	}
+	user := userData.(entity.User)
+
+	// users registered before the device list only have their single device
+	if len(user.Devices) == 0 {
+		user = mapper.AddDevice(user, user.Device, "")
+	}
+
+	return user.Devices, nil
+}
+
+// RevokeDevice - revokes one of the user's devices so it can no longer act for
+// them, or cancels its pending binding. The last trusted device cannot be revoked;
+// the user would be locked out.
+func (service *userService) RevokeDevice(ctx context.Context, user_reference string, device_reference string, currentUserDto dto.CurrentUserDto) (interface{}, error) {
+	return retryOnConflict(func() (interface{}, error) {
+		return service.revokeDevice(ctx, user_reference, device_reference, currentUserDto)
+	})
+}
+
+func (service *userService) revokeDevice(ctx context.Context, user_reference string, device_reference string, currentUserDto dto.CurrentUserDto) (interface{}, error) {
+	logger.LogEvent("INFO", "Revoking device "+device_reference+" for user with reference: "+user_reference)
 
-	user := mapper.UserDtoToUser(userData.(dto.UserDto))
+	userData, err := service.GetUserByReference(ctx, user_reference)
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to retrieve user")
+		return nil, err
+	}
+	user := userData.(entity.User)
 
 	//check if device is registered
-	if !isRegisteredDevice(entity.Device(currentUserDto.Device), user.Device) {
+	if !isRegisteredDevice(entity.Device(currentUserDto.Device), user) {
 		logger.LogEvent("ERROR", "This device is not registered to this user")
-		return nil, errors.New("this device is not registered to this user")
+		return nil, ErrDeviceNotRegistered
 	}
 	//check if the phone number is registered
 	if !isRegisteredPhoneNumber(currentUserDto.Phone, user.UserProfile.Phone) {
 		logger.LogEvent("ERROR", "The phone number is not registered to this user")
-		return nil, errors.New("the phone number is not registered to this user")
+		return nil, ErrPhoneNotRegistered
 	}
 
-	user = mapper.AddDocumentationDtoToDocumentation(user, documentationDto)
+	if len(user.Devices) == 0 {
+		user = mapper.AddDevice(user, user.Device, "")
+	}
 
-	result, err := service.userRepository.UpdateUser(ctx, user_reference, user)
+	revoked := -1
+	trusted := 0
+	for i, device := range user.Devices {
+		if device.Status == shared.DeviceRevoked {
+			continue
+		}
+		if device.Status == shared.DeviceTrusted {
+			trusted++
+		}
+		if device.DeviceReference == device_reference {
+			revoked = i
+		}
+	}
+	if revoked == -1 {
+		logger.LogEvent("ERROR", "Device "+device_reference+" not found for the user")
+		return nil, ErrDeviceNotFound
+	}
+	if user.Devices[revoked].Status == shared.DeviceTrusted && trusted == 1 {
+		logger.LogEvent("ERROR", "Refused to revoke the last trusted device of the user")
+		return nil, ErrLastTrustedDevice
+	}
+
+	user.Devices[revoked].Status = shared.DeviceRevoked
+	user.Devices[revoked].RevokedOn = time.Now().Format(time.RFC3339)
+
+	// the primary device moves to the most recently seen device still trusted
+	if user.Device.DeviceReference == device_reference {
+		lastSeenOn := ""
+		for _, device := range user.Devices {
+			if device.Status == shared.DeviceTrusted && device.LastSeenOn >= lastSeenOn {
+				user.Device = device.Device
+				lastSeenOn = device.LastSeenOn
+			}
+		}
+	}
+
+	deviceRevokedEvent := event.DeviceRevokedEvent{
+		Event: eto.Event{
+			EventReference:     uuid.New().String(),
+			EventName:          "devicerevokedevent",
+			EventDate:          time.Now().Format(time.RFC3339),
+			EventType:          "devicerevokedevent",
+			EventSource:        configuration.ServiceConfiguration.ServiceName,
+			EventUserReference: user.UserReference,
+			EventData:          mapper.UserToDeviceRevokedEventData(user, user.Devices[revoked]),
+		},
+	}
+
+	outboxEvent, err := newOutboxEvent(deviceRevokedEvent)
 	if err != nil {
-		logger.LogEvent("ERROR", "Failed to add documentation for the user")
-		return nil, errors.New("failed to add documentation for the user")
+		logger.LogEvent("ERROR", "Failed to prepare event for publishing")
+		return nil, errors.New("failed to prepare event for publishing")
+	}
+
+	result, err := service.userRepository.UpdateUser(ctx, user_reference, user, outboxEvent)
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to revoke device for the user")
+		return nil, updateError(err, "failed to revoke device for the user")
+	}
+
+	return result, nil
+}
+
+func (service *userService) AddDocumentation(ctx context.Context, user_reference string, documentationDto dto.AddDocumentationDto, currentUserDto dto.CurrentUserDto) (interface{}, error) {
+	return retryOnConflict(func() (interface{}, error) {
+		return service.addDocumentation(ctx, user_reference, documentationDto, currentUserDto)
+	})
+}
+
+func (service *userService) addDocumentation(ctx context.Context, user_reference string, documentationDto dto.AddDocumentationDto, currentUserDto dto.CurrentUserDto) (interface{}, error) {
+	userData, err := service.GetUserByReference(ctx, user_reference)
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to retrieve user")
+		return nil, err
+	}
+
+	user := userData.(entity.User)
+
+	//check if device is registered
+	if !isRegisteredDevice(entity.Device(currentUserDto.Device), user) {
+		logger.LogEvent("ERROR", "This device is not registered to this user")
+		return nil, ErrDeviceNotRegistered
+	}
+	//check if the phone number is registered
+	if !isRegisteredPhoneNumber(currentUserDto.Phone, user.UserProfile.Phone) {
+		logger.LogEvent("ERROR", "The phone number is not registered to this user")
+		return nil, ErrPhoneNotRegistered
 	}
 
+	user = mapper.AddDocumentationDtoToDocumentation(user, documentationDto)
+
 	documentationAddedEvent := event.DocumentationAddedEvent{
 		Event: eto.Event{
 			EventReference:     uuid.New().String(),
@@ -1431,34 +2189,49 @@ func (service *userService) AddDocumentation(ctx context.Context, user_reference
 			EventType:          "documentationaddedevent",
 			EventSource:        configuration.ServiceConfiguration.ServiceName,
 			EventUserReference: user.UserReference,
-			EventData:          user,
+			EventData:          mapper.UserToDocumentationAddedEventData(user),
 		},
 	}
 
-	eventPublisher := publisher.NewPublisher(service.redisClient)
-	eventPublisher.PublishDocumentationAddedEvent(ctx, documentationAddedEvent)
+	outboxEvent, err := newOutboxEvent(documentationAddedEvent)
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to prepare event for publishing")
+		return nil, errors.New("failed to prepare event for publishing")
+	}
+
+	result, err := service.userRepository.UpdateUser(ctx, user_reference, user, outboxEvent)
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to add documentation for the user")
+		return nil, updateError(err, "failed to add documentation for the user")
+	}
 
 	return result, nil
 }
 
 func (service *userService) UpdateDocumentation(ctx context.Context, user_reference string, documentation_reference string, documentationDto dto.AddDocumentationDto, currentUserDto dto.CurrentUserDto) (interface{}, error) {
+	return retryOnConflict(func() (interface{}, error) {
+		return service.updateDocumentation(ctx, user_reference, documentation_reference, documentationDto, currentUserDto)
+	})
+}
+
+func (service *userService) updateDocumentation(ctx context.Context, user_reference string, documentation_reference string, documentationDto dto.AddDocumentationDto, currentUserDto dto.CurrentUserDto) (interface{}, error) {
 	userData, err := service.GetUserByReference(ctx, user_reference)
 	if err != nil {
 		logger.LogEvent("ERROR", "Failed to retrieve user")
-		return nil, errors.New("failed to retrieve user")
+		return nil, err
 	}
 
-	user := mapper.UserDtoToUser(userData.(dto.UserDto))
+	user := userData.(entity.User)
 
 	//check if device is registered
-	if !isRegisteredDevice(entity.Device(currentUserDto.Device), user.Device) {
+	if !isRegisteredDevice(entity.Device(currentUserDto.Device), user) {
 		logger.LogEvent("ERROR", "This device is not registered to this user")
-		return nil, errors.New("this device is not registered to this user")
+		return nil, ErrDeviceNotRegistered
 	}
 	//check if the phone number is registered
 	if !isRegisteredPhoneNumber(currentUserDto.Phone, user.UserProfile.Phone) {
 		logger.LogEvent("ERROR", "The phone number is not registered to this user")
-		return nil, errors.New("the phone number is not registered to this user")
+		return nil, ErrPhoneNotRegistered
 	}
 
 	for i, id := range user.Kyc.Documentations {
@@ -1467,12 +2240,95 @@ func (service *userService) UpdateDocumentation(ctx context.Context, user_refere
 			break
 		}
 	}
+	// A replaced documentation is unverified again, which may change the KYC status.
+	previousKyc := user.Kyc
+	refreshKycStatus(&user.Kyc)
 
-	result, err := service.userRepository.UpdateUser(ctx, user_reference, user)
+	documentationUpdatedEvent := event.DocumentationUpdatedEvent{
+		Event: eto.Event{
+			EventReference:     uuid.New().String(),
+			EventName:          "documentationupdatedevent",
+			EventDate:          time.Now().Format(time.RFC3339),
+			EventType:          "documentationupdatedevent",
+			EventSource:        configuration.ServiceConfiguration.ServiceName,
+			EventUserReference: user.UserReference,
+			EventData:          mapper.UserToDocumentationUpdatedEventData(user, documentation_reference),
+		},
+	}
+
+	outboxEvent, err := newOutboxEvent(documentationUpdatedEvent)
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to prepare event for publishing")
+		return nil, errors.New("failed to prepare event for publishing")
+	}
+	outboxEvents := []entity.OutboxEvent{outboxEvent}
+
+	if user.Kyc.IsVerified != previousKyc.IsVerified || user.Kyc.VerifiedDocumentCount != previousKyc.VerifiedDocumentCount {
+		kycStatusUpdatedEvent := event.KycStatusUpdatedEvent{
+			Event: eto.Event{
+				EventReference:     uuid.New().String(),
+				EventName:          "kycstatusupdatedevent",
+				EventDate:          time.Now().Format(time.RFC3339),
+				EventType:          "kycstatusupdatedevent",
+				EventSource:        configuration.ServiceConfiguration.ServiceName,
+				EventUserReference: user.UserReference,
+				EventData:          mapper.UserToKycStatusUpdatedEventData(user),
+			},
+		}
+		kycOutboxEvent, err := newOutboxEvent(kycStatusUpdatedEvent)
+		if err != nil {
+			logger.LogEvent("ERROR", "Failed to prepare event for publishing")
+			return nil, errors.New("failed to prepare event for publishing")
+		}
+		outboxEvents = append(outboxEvents, kycOutboxEvent)
+	}
+
+	result, err := service.userRepository.UpdateUser(ctx, user_reference, user, outboxEvents...)
 	if err != nil {
 		logger.LogEvent("ERROR", "Failed to update documentation for the user")
-		return nil, errors.New("failed to update documentation for the user")
+		return nil, updateError(err, "failed to update documentation for the user")
+	}
+
+	return result, nil
+}
+
+// VerifyDocumentation - applies the identity service's verification result to
+// one of the user's documentations and publishes the user's recomputed KYC status.
+func (service *userService) VerifyDocumentation(ctx context.Context, user_reference string, documentation_reference string, documentationVerificationDto dto.DocumentationVerificationDto) (interface{}, error) {
+	return retryOnConflict(func() (interface{}, error) {
+		return service.verifyDocumentation(ctx, user_reference, documentation_reference, documentationVerificationDto)
+	})
+}
+
+func (service *userService) verifyDocumentation(ctx context.Context, user_reference string, documentation_reference string, documentationVerificationDto dto.DocumentationVerificationDto) (interface{}, error) {
+	logger.LogEvent("INFO", "Verifying documentation with reference: "+documentation_reference)
+	userData, err := service.GetUserByReference(ctx, user_reference)
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to retrieve user")
+		return nil, err
 	}
+	user := userData.(entity.User)
+
+	found := false
+	for i, documentation := range user.Kyc.Documentations {
+		if documentation.DocumentationReference == documentation_reference {
+			verifiedOn := documentationVerificationDto.VerifiedOn
+			if verifiedOn == "" {
+				verifiedOn = time.Now().Format(time.RFC3339)
+			}
+
+			user.Kyc.Documentations[i].IsVerified = documentationVerificationDto.IsVerified
+			user.Kyc.Documentations[i].VerifiedOn = verifiedOn
+			user.Kyc.Documentations[i].VerificationMethod = documentationVerificationDto.VerificationMethod
+			found = true
+			break
+		}
+	}
+	if !found {
+		logger.LogEvent("ERROR", "Documentation with reference: "+documentation_reference+" not found for the user")
+		return nil, ErrDocumentationNotFound
+	}
+	refreshKycStatus(&user.Kyc)
 
 	documentationUpdatedEvent := event.DocumentationUpdatedEvent{
 		Event: eto.Event{
@@ -1482,44 +2338,76 @@ func (service *userService) UpdateDocumentation(ctx context.Context, user_refere
 			EventType:          "documentationupdatedevent",
 			EventSource:        configuration.ServiceConfiguration.ServiceName,
 			EventUserReference: user.UserReference,
-			EventData:          user,
+			EventData:          mapper.UserToDocumentationUpdatedEventData(user, documentation_reference),
+		},
+	}
+
+	kycStatusUpdatedEvent := event.KycStatusUpdatedEvent{
+		Event: eto.Event{
+			EventReference:     uuid.New().String(),
+			EventName:          "kycstatusupdatedevent",
+			EventDate:          time.Now().Format(time.RFC3339),
+			EventType:          "kycstatusupdatedevent",
+			EventSource:        configuration.ServiceConfiguration.ServiceName,
+			EventUserReference: user.UserReference,
+			EventData:          mapper.UserToKycStatusUpdatedEventData(user),
 		},
 	}
 
-	eventPublisher := publisher.NewPublisher(service.redisClient)
-	eventPublisher.PublishDocumentationUpdatedEvent(ctx, documentationUpdatedEvent)
+	documentationOutboxEvent, err := newOutboxEvent(documentationUpdatedEvent)
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to prepare event for publishing")
+		return nil, errors.New("failed to prepare event for publishing")
+	}
+	kycOutboxEvent, err := newOutboxEvent(kycStatusUpdatedEvent)
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to prepare event for publishing")
+		return nil, errors.New("failed to prepare event for publishing")
+	}
+
+	result, err := service.userRepository.UpdateUser(ctx, user_reference, user, documentationOutboxEvent, kycOutboxEvent)
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to verify documentation for the user")
+		return nil, updateError(err, "failed to verify documentation for the user")
+	}
 
 	return result, nil
 }
 
+// refreshKycStatus - the user is KYC verified once any of their
+// documentations has been verified.
+func refreshKycStatus(kyc *entity.Kyc) {
+	kyc.VerifiedDocumentCount = 0
+	for _, documentation := range kyc.Documentations {
+		if documentation.IsVerified {
+			kyc.VerifiedDocumentCount++
+		}
+	}
+	kyc.IsVerified = kyc.VerifiedDocumentCount > 0
+}
+
 func (service *userService) AddContact(ctx context.Context, user_reference string, contactDto dto.ContactDto, currentUserDto dto.CurrentUserDto) (interface{}, error) {
 	userData, err := service.GetUserByReference(ctx, user_reference)
 	if err != nil {
 		logger.LogEvent("ERROR", "Failed to retrieve user")
-		return nil, errors.New("failed to retrieve user")
+		return nil, err
 	}
 
-	user := mapper.UserDtoToUser(userData.(dto.UserDto))
+	user := userData.(entity.User)
 
 	//check if device is registered
-	if !isRegisteredDevice(entity.Device(currentUserDto.Device), user.Device) {
+	if !isRegisteredDevice(entity.Device(currentUserDto.Device), user) {
 		logger.LogEvent("ERROR", "This device is not registered to this user")
-		return nil, errors.New("this device is not registered to this user")
+		return nil, ErrDeviceNotRegistered
 	}
 	//check if the phone number is registered
 	if !isRegisteredPhoneNumber(currentUserDto.Phone, user.UserProfile.Phone) {
 		logger.LogEvent("ERROR", "The phone number is not registered to this user")
-		return nil, errors.New("the phone number is not registered to this user")
+		return nil, ErrPhoneNotRegistered
 	}
 
 	user = mapper.AddContactDtoToContact(user, contactDto)
 
-	result, err := service.userRepository.UpdateUser(ctx, user_reference, user)
-	if err != nil {
-		logger.LogEvent("ERROR", "Failed to add contact for the user")
-		return nil, errors.New("failed to add contact for the user")
-	}
-
 	contactAddedEvent := event.ContactAddedEvent{
 		Event: eto.Event{
 			EventReference:     uuid.New().String(),
@@ -1528,12 +2416,21 @@ func (service *userService) AddContact(ctx context.Context, user_reference strin
 			EventType:          "contactaddedevent",
 			EventSource:        configuration.ServiceConfiguration.ServiceName,
 			EventUserReference: user.UserReference,
-			EventData:          user,
+			EventData:          mapper.UserToContactAddedEventData(user),
 		},
 	}
 
-	eventPublisher := publisher.NewPublisher(service.redisClient)
-	eventPublisher.PublishContactAddedEvent(ctx, contactAddedEvent)
+	outboxEvent, err := newOutboxEvent(contactAddedEvent)
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to prepare event for publishing")
+		return nil, errors.New("failed to prepare event for publishing")
+	}
+
+	result, err := service.userRepository.PushContact(ctx, user_reference, user.Contacts[len(user.Contacts)-1], outboxEvent)
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to add contact for the user")
+		return nil, updateError(err, "failed to add contact for the user")
+	}
 
 	return result, nil
 }
@@ -1544,17 +2441,17 @@ func (service *userService) GetUserByReference(ctx context.Context, user_referen
 	user, err := service.userRepository.GetUserByReference(ctx, user_reference)
 	if err != nil {
 		logger.LogEvent("ERROR", "Failed to fetch User by reference: "+user_reference)
-		return nil, errors.New("failed to retrieve user")
+		return nil, lookupError(err, ErrUserNotFound)
 	}
 
 	if user == nil {
 		logger.LogEvent("ERROR", "User not found for reference: "+user_reference)
-		return nil, errors.New("user not found")
+		return nil, ErrUserNotFound
 	}
 
 	logger.LogEvent("INFO", "User fetched successfully by reference: "+user_reference)
 
-	return user, nil
+	return service.withCatalogueTier(ctx, user), nil
 }
 func (service *userService) GetUserDefaultBadge(ctx context.Context, user_reference string) (interface{}, error) {
 	logger.LogEvent("INFO", "Fetching User by reference: "+user_reference)
@@ -1562,12 +2459,12 @@ func (service *userService) GetUserDefaultBadge(ctx context.Context, user_refere
 	badge, err := service.userRepository.GetUserDefaultWallsBadge(ctx, user_reference)
 	if err != nil {
 		logger.LogEvent("ERROR", "Failed to fetch default user wallsbadge by reference: "+user_reference)
-		return nil, errors.New("failed to retrieve user")
+		return nil, lookupError(err, ErrWallsBadgeNotFound)
 	}
 
 	if badge == nil {
 		logger.LogEvent("ERROR", "badge not found for reference: "+user_reference)
-		return nil, errors.New("badge not found")
+		return nil, ErrWallsBadgeNotFound
 	}
 
 	logger.LogEvent("INFO", "Badge fetched successfully by reference: "+user_reference)
@@ -1578,93 +2475,194 @@ func (service *userService) GetUserByPhone(ctx context.Context, phone string) (i
 	user, err := service.userRepository.GetUserByPhone(ctx, phone)
 	if err != nil {
 		logger.LogEvent("ERROR", "Failed to fetch User by phone: "+phone)
-		return nil, errors.New("failed to retrieve user")
+		return nil, lookupError(err, ErrUserNotFound)
 	}
 
 	if user == nil {
 		logger.LogEvent("ERROR", "User not found for phone: "+phone)
-		return nil, errors.New("user not found")
+		return nil, ErrUserNotFound
 	}
 
 	logger.LogEvent("INFO", "User fetched successfully by phone: "+phone)
 
-	return user, nil
+	return service.withCatalogueTier(ctx, user), nil
 }
 
 func (service *userService) GetUserByWallsTag(ctx context.Context, wallsTag string) (interface{}, error) {
 	user, err := service.userRepository.GetUserByWallsTag(ctx, wallsTag)
 	if err != nil {
 		logger.LogEvent("ERROR", "Failed to fetch User by reference: "+wallsTag)
-		return nil, errors.New("failed to retrieve user")
+		return nil, lookupError(err, ErrUserNotFound)
 	}
 
 	if user == nil {
 		logger.LogEvent("ERROR", "User not found for reference: "+wallsTag)
-		return nil, errors.New("user not found")
+		return nil, ErrUserNotFound
+	}
+
+	logger.LogEvent("INFO", "User fetched successfully by reference: "+wallsTag)
+
+	return service.withCatalogueTier(ctx, user), nil
+}
+
+func (service *userService) GetUserByWallsBagdeReference(ctx context.Context, wallsBagdeReference string) (interface{}, error) {
+	user, err := service.userRepository.GetUserByWallsBadgeReference(ctx, wallsBagdeReference)
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to fetch User by reference: "+wallsBagdeReference)
+		return nil, lookupError(err, ErrUserNotFound)
+	}
+
+	if user == nil {
+		logger.LogEvent("ERROR", "User not found for reference: "+wallsBagdeReference)
+		return nil, ErrUserNotFound
+	}
+
+	logger.LogEvent("INFO", "User fetched successfully by reference: "+wallsBagdeReference)
+
+	return service.withCatalogueTier(ctx, user), nil
+}
+
+func (service *userService) GetUserByDevice(ctx context.Context, device dto.DeviceDto) (interface{}, error) {
+	user, err := service.userRepository.GetUserByDevice(ctx, entity.Device(device))
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to fetch User by device")
+		return nil, lookupError(err, ErrUserNotFound)
+	}
+
+	return service.withCatalogueTier(ctx, user), nil
+}
+
+// SearchUsers - a page of the users matching the back-office search. The next
+// cursor continues the same search and is empty on the last page.
+func (service *userService) SearchUsers(ctx context.Context, userSearchDto dto.UserSearchDto) (interface{}, error) {
+	logger.LogEvent("INFO", "Searching users")
+	query := mapper.UserSearchDtoToUserQuery(userSearchDto)
+	if query.Limit == 0 {
+		query.Limit = searchLimit()
+	}
+	if !entity.IsUserSortField(query.SortBy) {
+		logger.LogEvent("ERROR", "Invalid user search sort: "+query.SortBy)
+		return nil, ErrInvalidSort
+	}
+
+	if userSearchDto.Cursor != "" {
+		after, err := decodeUserCursor(userSearchDto.Cursor)
+		if err != nil || after.SortBy != query.SortBy || after.Descending != query.Descending {
+			logger.LogEvent("ERROR", "Invalid user search cursor")
+			return nil, ErrInvalidCursor
+		}
+		query.After = &after
+	}
+
+	result, err := service.userRepository.SearchUsers(ctx, query)
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to search users: "+err.Error())
+		return nil, errors.New("unable to search users")
+	}
+
+	page := result.(entity.UserPage)
+	tiers := map[string]entity.Tier{}
+	for i, user := range page.Users {
+		tier, ok := tiers[user.Wallet.Tier.TierReference]
+		if !ok {
+			tier = service.catalogueTier(ctx, user.Wallet.Tier)
+			tiers[user.Wallet.Tier.TierReference] = tier
+		}
+		page.Users[i].Wallet.Tier = tier
+	}
+	nextCursor := ""
+	if page.Next != nil {
+		nextCursor = encodeUserCursor(*page.Next)
+	}
+
+	logger.LogEvent("INFO", fmt.Sprintf("User search returned %d of %d users", len(page.Users), page.Total))
+	return mapper.UserPageToUserPageDto(page, nextCursor), nil
+}
+
+// UpdateBalance - sets the user's balance on behalf of ops or the ledger,
+// which are not on the user's device.
+func (service *userService) UpdateBalance(ctx context.Context, user_reference string, balanceDto dto.BalanceDto) (interface{}, error) {
+	return retryOnConflict(func() (interface{}, error) {
+		return service.updateBalance(ctx, user_reference, balanceDto)
+	})
+}
+
+func (service *userService) updateBalance(ctx context.Context, user_reference string, balanceDto dto.BalanceDto) (interface{}, error) {
+	userData, err := service.GetUserByReference(ctx, user_reference)
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to retrieve user")
+		return nil, err
+	}
+	user := userData.(entity.User)
+
+	user.Wallet.Balance.PendingIncomingAmount = balanceDto.BookAmount
+	user.Wallet.Balance.AvailableAmount = balanceDto.AvailableAmount
+	user.Wallet.Balance.IsSynced = true
+	user.Wallet.Balance.LastSyncedOn = time.Now().Format(time.RFC3339)
+
+	balanceUpdatedEvent := event.BalanceUpdatedEvent{
+		Event: eto.Event{
+			EventReference:     uuid.New().String(),
+			EventName:          "balanceupdatedevent",
+			EventDate:          time.Now().Format(time.RFC3339),
+			EventType:          "balanceupdatedevent",
+			EventSource:        configuration.ServiceConfiguration.ServiceName,
+			EventUserReference: user.UserReference,
+			EventData:          mapper.UserToBalanceUpdatedEventData(user),
+		},
 	}
 
-	logger.LogEvent("INFO", "User fetched successfully by reference: "+wallsTag)
-
-	return user, nil
-}
-
-func (service *userService) GetUserByWallsBagdeReference(ctx context.Context, wallsBagdeReference string) (interface{}, error) {
-	user, err := service.userRepository.GetUserByWallsBadgeReference(ctx, wallsBagdeReference)
+	outboxEvent, err := newOutboxEvent(balanceUpdatedEvent)
 	if err != nil {
-		logger.LogEvent("ERROR", "Failed to fetch User by reference: "+wallsBagdeReference)
-		return nil, errors.New("failed to retrieve user")
+		logger.LogEvent("ERROR", "Failed to prepare event for publishing")
+		return nil, errors.New("failed to prepare event for publishing")
 	}
 
-	if user == nil {
-		logger.LogEvent("ERROR", "User not found for reference: "+wallsBagdeReference)
-		return nil, errors.New("user not found")
+	result, err := service.userRepository.SetBalance(ctx, user_reference, user.Wallet.Balance, outboxEvent)
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to update book balance for the user")
+		return nil, updateError(err, "failed to update book balance for the user")
 	}
 
-	logger.LogEvent("INFO", "User fetched successfully by reference: "+wallsBagdeReference)
-
-	return user, nil
+	return result, nil
 }
 
-func (service *userService) GetUserByDevice(ctx context.Context, device dto.DeviceDto) (interface{}, error) {
-	user, err := service.userRepository.GetUserByDevice(ctx, entity.Device(device))
-	if err != nil {
-		logger.LogEvent("ERROR", "Failed to fetch User by device")
-		return nil, errors.New("failed to retrieve user")
-	}
-
-	return user, nil
+// ApplyLedgerBalance - applies a balance pushed by the ledger. Updates carry a
+// ledger sequence that only ever increases, so one that arrives after a newer
+// update has been applied is rejected rather than rolling the balance back.
+func (service *userService) ApplyLedgerBalance(ctx context.Context, user_reference string, ledgerBalanceDto dto.LedgerBalanceDto) (interface{}, error) {
+	return retryOnConflict(func() (interface{}, error) {
+		return service.applyLedgerBalance(ctx, user_reference, ledgerBalanceDto)
+	})
 }
 
-func (service *userService) UpdateBalance(ctx context.Context, user_reference string, balanceDto dto.BalanceDto, currentUserDto dto.CurrentUserDto) (interface{}, error) {
+func (service *userService) applyLedgerBalance(ctx context.Context, user_reference string, ledgerBalanceDto dto.LedgerBalanceDto) (interface{}, error) {
+	logger.LogEvent("INFO", fmt.Sprintf("Applying ledger balance %d for user with reference: %s", ledgerBalanceDto.LedgerSequence, user_reference))
 	userData, err := service.GetUserByReference(ctx, user_reference)
 	if err != nil {
 		logger.LogEvent("ERROR", "Failed to retrieve user")
-		return nil, errors.New("failed to retrieve user")
+		return nil, err
 	}
+	user := userData.(entity.User)
 
-	user := mapper.UserDtoToUser(userData.(dto.UserDto))
-	//check if device is registered
-	if !isRegisteredDevice(entity.Device(currentUserDto.Device), user.Device) {
-		logger.LogEvent("ERROR", "This device is not registered to this user")
-		return nil, errors.New("this device is not registered to this user")
+	if ledgerBalanceDto.WalletReference != "" && ledgerBalanceDto.WalletReference != user.Wallet.WalletReference {
+		logger.LogEvent("ERROR", "Wallet with reference: "+ledgerBalanceDto.WalletReference+" does not belong to the user")
+		return nil, ErrWalletNotOwned
 	}
-	//check if the phone number is registered
-	if !isRegisteredPhoneNumber(currentUserDto.Phone, user.UserProfile.Phone) {
-		logger.LogEvent("ERROR", "The phone number is not registered to this user")
-		return nil, errors.New("the phone number is not registered to this user")
+	if ledgerBalanceDto.LedgerSequence <= user.Wallet.Balance.LedgerSequence {
+		logger.LogEvent("ERROR", fmt.Sprintf("Rejecting ledger balance %d for user with reference: %s, balance is already at %d", ledgerBalanceDto.LedgerSequence, user_reference, user.Wallet.Balance.LedgerSequence))
+		return nil, ErrStaleBalanceUpdate
 	}
 
-	user.Wallet.Balance.PendingIncomingAmount = balanceDto.BookAmount
-	user.Wallet.Balance.AvailableAmount = balanceDto.AvailableAmount
+	user.Wallet.Balance.AvailableAmount = ledgerBalanceDto.AvailableAmount
+	user.Wallet.Balance.PendingIncomingAmount = ledgerBalanceDto.PendingIncomingAmount
+	if ledgerBalanceDto.Currency != "" {
+		user.Wallet.Balance.Currency = ledgerBalanceDto.Currency
+	}
+	user.Wallet.Balance.LedgerSequence = ledgerBalanceDto.LedgerSequence
 	user.Wallet.Balance.IsSynced = true
 	user.Wallet.Balance.LastSyncedOn = time.Now().Format(time.RFC3339)
 
-	result, err := service.userRepository.UpdateUser(ctx, user_reference, user)
-	if err != nil {
-		logger.LogEvent("ERROR", "Failed to update book balance for the user")
-		return nil, errors.New("failed to update book balance for the user")
-	}
-
 	balanceUpdatedEvent := event.BalanceUpdatedEvent{
 		Event: eto.Event{
 			EventReference:     uuid.New().String(),
@@ -1673,48 +2671,51 @@ func (service *userService) UpdateBalance(ctx context.Context, user_reference st
 			EventType:          "balanceupdatedevent",
 			EventSource:        configuration.ServiceConfiguration.ServiceName,
 			EventUserReference: user.UserReference,
-			EventData:          user,
+			EventData:          mapper.UserToBalanceUpdatedEventData(user),
 		},
 	}
 
-	eventPublisher := publisher.NewPublisher(service.redisClient)
-	eventPublisher.PublishBalanceUpdatedEvent(ctx, balanceUpdatedEvent)
+	outboxEvent, err := newOutboxEvent(balanceUpdatedEvent)
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to prepare event for publishing")
+		return nil, errors.New("failed to prepare event for publishing")
+	}
+
+	result, err := service.userRepository.SetLedgerBalance(ctx, user_reference, user.Wallet.Balance, outboxEvent)
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to update balance for the user")
+		return nil, updateError(err, "failed to update balance for the user")
+	}
 
 	return result, nil
 }
 
-func (service *userService) UpdateTier(ctx context.Context, user_reference string, tierDto dto.TierDto, currentUserDto dto.CurrentUserDto) (interface{}, error) {
+// UpdateTier - moves the user onto a tier on behalf of ops or another
+// service, which are not on the user's device. Only the tier is stored: its
+// limits are the tier catalogue's.
+func (service *userService) UpdateTier(ctx context.Context, user_reference string, tierDto dto.TierDto) (interface{}, error) {
+	return retryOnConflict(func() (interface{}, error) {
+		return service.updateTier(ctx, user_reference, tierDto)
+	})
+}
+
+func (service *userService) updateTier(ctx context.Context, user_reference string, tierDto dto.TierDto) (interface{}, error) {
 	userData, err := service.GetUserByReference(ctx, user_reference)
 	if err != nil {
 		logger.LogEvent("ERROR", "Failed to retrieve user")
-		return nil, errors.New("failed to retrieve user")
-	}
-	user := mapper.UserDtoToUser(userData.(dto.UserDto))
-
-	//check if device is registered
-	if !isRegisteredDevice(entity.Device(currentUserDto.Device), user.Device) {
-		logger.LogEvent("ERROR", "This device is not registered to this user")
-		return nil, errors.New("this device is not registered to this user")
-	}
-	//check if the phone number is registered
-	if !isRegisteredPhoneNumber(currentUserDto.Phone, user.UserProfile.Phone) {
-		logger.LogEvent("ERROR", "The phone number is not registered to this user")
-		return nil, errors.New("the phone number is not registered to this user")
+		return nil, err
 	}
+	user := userData.(entity.User)
 
 	user.Wallet.Tier.TierReference = tierDto.TierReference
 	user.Wallet.Tier.TierName = tierDto.TierName
 	user.Wallet.Tier.SendingLimit = tierDto.SendingLimit
-	user.Wallet.Tier.DailyTransactionLimit = tierDto.DailyTransactionLimit
+	user.Wallet.Tier.ReceivingLimit = tierDto.ReceivingLimit
+	user.Wallet.Tier.WalletLimit = tierDto.WalletLimit
 	user.Wallet.Tier.MinimumBalance = tierDto.MinimumBalance
 	user.Wallet.Tier.DailyTransactionLimit = tierDto.DailyTransactionLimit
 	user.Wallet.Tier.UpgradeOptions = tierDto.UpgradeOptions
-
-	result, err := service.userRepository.UpdateUser(ctx, user_reference, user)
-	if err != nil {
-		logger.LogEvent("ERROR", "Failed to update tier for the user")
-		return nil, errors.New("failed to update tier for the user")
-	}
+	user.Wallet.Tier = service.catalogueTier(ctx, user.Wallet.Tier)
 
 	tierUpdatedEvent := event.TierUpdatedEvent{
 		Event: eto.Event{
@@ -1724,12 +2725,21 @@ func (service *userService) UpdateTier(ctx context.Context, user_reference strin
 			EventType:          "tierupdatedevent",
 			EventSource:        configuration.ServiceConfiguration.ServiceName,
 			EventUserReference: user.UserReference,
-			EventData:          user,
+			EventData:          mapper.UserToTierUpdatedEventData(user),
 		},
 	}
 
-	eventPublisher := publisher.NewPublisher(service.redisClient)
-	eventPublisher.PublishTierUpdatedEvent(ctx, tierUpdatedEvent)
+	outboxEvent, err := newOutboxEvent(tierUpdatedEvent)
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to prepare event for publishing")
+		return nil, errors.New("failed to prepare event for publishing")
+	}
+
+	result, err := service.userRepository.UpdateUser(ctx, user_reference, user, outboxEvent)
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to update tier for the user")
+		return nil, updateError(err, "failed to update tier for the user")
+	}
 
 	return result, nil
 }
@@ -1738,17 +2748,12 @@ func (service *userService) AddCoupon(ctx context.Context, user_reference string
 	userData, err := service.GetUserByReference(ctx, user_reference)
 	if err != nil {
 		logger.LogEvent("ERROR", "Failed to retrieve user")
-		return nil, errors.New("failed to retrieve user")
+		return nil, err
 	}
-	user := mapper.UserDtoToUser(userData.(dto.UserDto))
-
-	user.Wallet.Coupons = append(user.Wallet.Coupons, entity.Coupon(couponDto))
+	user := userData.(entity.User)
 
-	result, err := service.userRepository.UpdateUser(ctx, user_reference, user)
-	if err != nil {
-		logger.LogEvent("ERROR", "Failed to update coupons for the user")
-		return nil, errors.New("failed to update coupons for the user")
-	}
+	coupon := entity.Coupon(couponDto)
+	user.Wallet.Coupons = append(user.Wallet.Coupons, coupon)
 
 	couponAddedEvent := event.CouponAddedEvent{
 		Event: eto.Event{
@@ -1758,49 +2763,58 @@ func (service *userService) AddCoupon(ctx context.Context, user_reference string
 			EventType:          "couponaddedevent",
 			EventSource:        configuration.ServiceConfiguration.ServiceName,
 			EventUserReference: user.UserReference,
-			EventData:          user,
+			EventData:          mapper.UserToCouponAddedEventData(user),
 		},
 	}
 
-	eventPublisher := publisher.NewPublisher(service.redisClient)
-	eventPublisher.PublishCouponAddedEvent(ctx, couponAddedEvent)
+	outboxEvent, err := newOutboxEvent(couponAddedEvent)
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to prepare event for publishing")
+		return nil, errors.New("failed to prepare event for publishing")
+	}
+
+	result, err := service.userRepository.PushCoupon(ctx, user_reference, coupon, outboxEvent)
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to update coupons for the user")
+		return nil, updateError(err, "failed to update coupons for the user")
+	}
 
 	return result, nil
 }
 
+// UpdateRewards - adds the points to the user's rewards. The rewards updated
+// event carries the total the addition produced, even when other additions
+// happen at the same time.
 func (service *userService) UpdateRewards(ctx context.Context, user_reference string, rewardDto dto.RewardDto) (interface{}, error) {
-	userData, err := service.GetUserByReference(ctx, user_reference)
-	if err != nil {
-		logger.LogEvent("ERROR", "Failed to retrieve user")
-		return nil, errors.New("failed to retrieve user")
-	}
-	user := mapper.UserDtoToUser(userData.(dto.UserDto))
-
-	// user.Wallet.Reward.Points = rewardDto.Points
-	user.Wallet.Reward.Points += rewardDto.Points
+	_, err := service.userRepository.IncrementRewardPoints(ctx, user_reference, rewardDto.Points, func(user entity.User) ([]entity.OutboxEvent, error) {
+		rewardsUpdatedEvent := event.RewardsUpdatedEvent{
+			Event: eto.Event{
+				EventReference:     uuid.New().String(),
+				EventName:          "rewardsupdatedevent",
+				EventDate:          time.Now().Format(time.RFC3339),
+				EventType:          "rewardsupdatedevent",
+				EventSource:        configuration.ServiceConfiguration.ServiceName,
+				EventUserReference: user.UserReference,
+				EventData:          mapper.UserToRewardsUpdatedEventData(user),
+			},
+		}
 
-	result, err := service.userRepository.UpdateUser(ctx, user_reference, user)
+		outboxEvent, err := newOutboxEvent(rewardsUpdatedEvent)
+		if err != nil {
+			logger.LogEvent("ERROR", "Failed to prepare event for publishing")
+			return nil, errors.New("failed to prepare event for publishing")
+		}
+		return []entity.OutboxEvent{outboxEvent}, nil
+	})
 	if err != nil {
 		logger.LogEvent("ERROR", "Failed to update rewards for the user")
-		return nil, errors.New("failed to update rewards for the user")
-	}
-
-	rewardsUpdatedEvent := event.RewardsUpdatedEvent{
-		Event: eto.Event{
-			EventReference:     uuid.New().String(),
-			EventName:          "rewardsupdatedevent",
-			EventDate:          time.Now().Format(time.RFC3339),
-			EventType:          "rewardsupdatedevent",
-			EventSource:        configuration.ServiceConfiguration.ServiceName,
-			EventUserReference: user.UserReference,
-			EventData:          user,
-		},
+		if errorhelper.IsNotFound(err) {
+			return nil, lookupError(err, ErrUserNotFound)
+		}
+		return nil, updateError(err, "failed to update rewards for the user")
 	}
 
-	eventPublisher := publisher.NewPublisher(service.redisClient)
-	eventPublisher.PublishRewardsUpdatedEvent(ctx, rewardsUpdatedEvent)
-
-	return result, nil
+	return user_reference, nil
 }
 
 
@@ -1808,19 +2822,13 @@ func (service *userService) EnableUser(ctx context.Context, user_reference strin
 	userData, err := service.GetUserByReference(ctx, user_reference)
 	if err != nil {
 		logger.LogEvent("ERROR", "Failed to retrieve user")
-		return nil, errors.New("failed to retrieve user")
+		return nil, err
 	}
 
-	user := mapper.UserDtoToUser(userData.(dto.UserDto))
+	user := userData.(entity.User)
 
 	user.IsActive = true
 
-	result, err := service.userRepository.UpdateUser(ctx, user_reference, user)
-	if err != nil {
-		logger.LogEvent("ERROR", "Failed to enable user")
-		return nil, errors.New("failed to enable user")
-	}
-
 	userEnabledEvent := event.UserEnabledEvent{
 		Event: eto.Event{
 			EventReference:     uuid.New().String(),
@@ -1829,12 +2837,21 @@ func (service *userService) EnableUser(ctx context.Context, user_reference strin
 			EventType:          "userenabledevent",
 			EventSource:        configuration.ServiceConfiguration.ServiceName,
 			EventUserReference: user.UserReference,
-			EventData:          user,
+			EventData:          mapper.UserToUserEnabledEventData(user),
 		},
 	}
 
-	eventPublisher := publisher.NewPublisher(service.redisClient)
-	eventPublisher.PublishUserEnabledEvent(ctx, userEnabledEvent)
+	outboxEvent, err := newOutboxEvent(userEnabledEvent)
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to prepare event for publishing")
+		return nil, errors.New("failed to prepare event for publishing")
+	}
+
+	result, err := service.userRepository.SetUserActive(ctx, user_reference, true, outboxEvent)
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to enable user")
+		return nil, updateError(err, "failed to enable user")
+	}
 
 	return result, nil
 }
@@ -1843,18 +2860,12 @@ func (service *userService) DisableUser(ctx context.Context, user_reference stri
 	userData, err := service.GetUserByReference(ctx, user_reference)
 	if err != nil {
 		logger.LogEvent("ERROR", "Failed to retrieve user")
-		return nil, errors.New("failed to retrieve user")
+		return nil, err
 	}
-	user := mapper.UserDtoToUser(userData.(dto.UserDto))
+	user := userData.(entity.User)
 
 	user.IsActive = false
 
-	result, err := service.userRepository.UpdateUser(ctx, user_reference, user)
-	if err != nil {
-		logger.LogEvent("ERROR", "Failed to disable user")
-		return nil, errors.New("failed to disable user")
-	}
-
 	userDisabledEvent := event.UserDisabledEvent{
 		Event: eto.Event{
 			EventReference:     uuid.New().String(),
@@ -1863,12 +2874,21 @@ func (service *userService) DisableUser(ctx context.Context, user_reference stri
 			EventType:          "userdisabledevent",
 			EventSource:        configuration.ServiceConfiguration.ServiceName,
 			EventUserReference: user.UserReference,
-			EventData:          user,
+			EventData:          mapper.UserToUserDisabledEventData(user),
 		},
 	}
 
-	eventPublisher := publisher.NewPublisher(service.redisClient)
-	eventPublisher.PublishUserDisabledEvent(ctx, userDisabledEvent)
+	outboxEvent, err := newOutboxEvent(userDisabledEvent)
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to prepare event for publishing")
+		return nil, errors.New("failed to prepare event for publishing")
+	}
+
+	result, err := service.userRepository.SetUserActive(ctx, user_reference, false, outboxEvent)
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to disable user")
+		return nil, updateError(err, "failed to disable user")
+	}
 
 	return result, nil
 }
@@ -1881,7 +2901,7 @@ func (service *userService) CreateOtpRequest(ctx context.Context, requestOtpDto
 		userData, err := service.GetUserByReference(ctx, currentUserDto.UserReference)
 		if err != nil {
 			logger.LogEvent("ERROR", "Unauthorized User: Failed to fetch identity for the user with reference: "+currentUserDto.UserReference)
-			return nil, errors.New("unauthorized user: failed to retrieve identity")
+			return nil, lookupError(err, ErrUnknownIdentity)
 		}
 
 		// Convert identityData to entity.Identity type
@@ -1889,14 +2909,14 @@ func (service *userService) CreateOtpRequest(ctx context.Context, requestOtpDto
 
 		//check if device is registered
 
-		if !isRegisteredDevice(entity.Device(currentUserDto.Device), user.Device) {
+		if !isRegisteredDevice(entity.Device(currentUserDto.Device), user) {
 			logger.LogEvent("ERROR", "Unauthorized Device: This device is not registered to this user")
-			return nil, errors.New("unauthorized device: this device is not registered to this user")
+			return nil, ErrDeviceNotRegistered.WithMessage("unauthorized device: this device is not registered to this user")
 		}
 		//check if the phone number is registered
 		if !isRegisteredPhoneNumber(currentUserDto.Phone, user.UserProfile.Phone) {
 			logger.LogEvent("ERROR", "Unauthorized Phone: The phone number is not registered to this user")
-			return nil, errors.New("unauthorized phone: the phone number is not registered to this user")
+			return nil, ErrPhoneNotRegistered.WithMessage("unauthorized phone: the phone number is not registered to this user")
 		}
 	}
 
@@ -1904,33 +2924,29 @@ func (service *userService) CreateOtpRequest(ctx context.Context, requestOtpDto
 	if requestOtpDto.OtpType == "create_user" && !validation.IsValidPhone(requestOtpDto.Contact) {
 		fmt.Println("contact for new user otp must be a phone number")
 		logger.LogEvent("ERROR", "contact for new user otp must be a phone number")
-		return nil, errors.New("contact for new user otp must be a phone number")
+		return nil, ErrInvalidOtpContact.WithMessage("contact for new user otp must be a phone number")
 	}
 
 	// email verification must have an email as contact
 	if requestOtpDto.OtpType == "verify_email" && !validation.IsValidEmail(requestOtpDto.Contact) {
 		fmt.Println("otp for email verification requires an email contact")
 		logger.LogEvent("ERROR", "otp for email verification requires an email as contact")
-		return nil, errors.New("otp for email verification requires an email as contact")
+		return nil, ErrInvalidOtpContact.WithMessage("otp for email verification requires an email as contact")
 	}
 
 	// phone number verification must have a phone number as contact
 	if requestOtpDto.OtpType == "verify_phone" && !validation.IsValidPhone(requestOtpDto.Contact) {
 		fmt.Println("otp for phone number verification requires an email contact")
 		logger.LogEvent("ERROR", "otp for phone number verification requires a phone number as contact")
-		return nil, errors.New("otp for email verification requires a phone number as contact")
+		return nil, ErrInvalidOtpContact.WithMessage("otp for email verification requires a phone number as contact")
 	}
 
-	request := struct {
-		UserReference string        `json:"userReference"`
-		Contact       string        `json:"contact"`
-		Channel       string        `json:"channel"`
-		Device        dto.DeviceDto `json:"device"`
-	}{
-		currentUserDto.UserReference,
-		requestOtpDto.Contact,
-		requestOtpDto.Channel,
-		requestOtpDto.Device,
+	request := events.OtpRequestCreatedEventData{
+		SchemaVersion: events.SchemaVersion,
+		UserReference: currentUserDto.UserReference,
+		Contact:       requestOtpDto.Contact,
+		Channel:       requestOtpDto.Channel,
+		Device:        requestOtpDto.Device,
 	}
 
 	createOtpRequestEvent := event.OtpRequestCreatedEvent{
@@ -1945,8 +2961,17 @@ func (service *userService) CreateOtpRequest(ctx context.Context, requestOtpDto
 		},
 	}
 
-	eventPublisher := publisher.NewPublisher(service.redisClient)
-	eventPublisher.PublishCreateOtpRequestEvent(ctx, createOtpRequestEvent, createOtpRequestEvent.EventType)
+	outboxEvent, err := newOutboxEvent(createOtpRequestEvent, createOtpRequestEvent.EventType)
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to prepare event for publishing")
+		return nil, errors.New("failed to prepare event for publishing")
+	}
+
+	_, err = service.outboxRepository.CreateOutboxEvent(ctx, outboxEvent)
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to queue event for publishing")
+		return nil, errors.New("failed to queue event for publishing")
+	}
 	return request.UserReference, nil
 }
 
@@ -1958,22 +2983,29 @@ func (service *userService) ValidateOtpRequest(ctx context.Context, user_referen
 		userData, err := service.GetUserByReference(ctx, currentUserDto.UserReference)
 		if err != nil {
 			logger.LogEvent("ERROR", "Unauthorized User: Failed to fetch identity for the user with reference: "+user_reference)
-			return nil, errors.New("unauthorized user: failed to retrieve identity")
+			return nil, lookupError(err, ErrUnknownIdentity)
 		}
 
 		// Convert identityData to entity.Identity type
 		user := userData.(entity.User)
 
+		// a device binding is validated for a device still pending, from that device or a trusted one
+		if validateOtpDto.OtpType == "bind_device" && !isPendingDevice(entity.Device(validateOtpDto.Device), user) {
+			logger.LogEvent("ERROR", "No pending binding for device "+validateOtpDto.Device.DeviceReference)
+			return nil, ErrNoPendingDeviceBinding
+		}
+		isBindingDevice := validateOtpDto.OtpType == "bind_device" && validateOtpDto.Device == currentUserDto.Device
+
 		//check if device is registered
 
-		if !isRegisteredDevice(entity.Device(currentUserDto.Device), user.Device) {
+		if !isBindingDevice && !isRegisteredDevice(entity.Device(currentUserDto.Device), user) {
 			logger.LogEvent("ERROR", "Unauthorized Device: This device is not registered to this user")
-			return nil, errors.New("unauthorized device: this device is not registered to this user")
+			return nil, ErrDeviceNotRegistered.WithMessage("unauthorized device: this device is not registered to this user")
 		}
 		//check if the phone number is registered
 		if !isRegisteredPhoneNumber(currentUserDto.Phone, user.UserProfile.Phone) {
 			logger.LogEvent("ERROR", "Unauthorized Phone: The phone number is not registered to this user")
-			return nil, errors.New("unauthorized phone: the phone number is not registered to this user")
+			return nil, ErrPhoneNotRegistered.WithMessage("unauthorized phone: the phone number is not registered to this user")
 		}
 	}
 
@@ -1981,29 +3013,25 @@ func (service *userService) ValidateOtpRequest(ctx context.Context, user_referen
 	if validateOtpDto.OtpType == "create_user" && !validation.IsValidPhone(validateOtpDto.Contact) {
 		fmt.Println("validating otp for new user otp requires a phone number as contact")
 		logger.LogEvent("ERROR", "contact field for validating otp for new user otp requires a phone number as contact")
-		return nil, errors.New("contact for new user otp must be a phone number")
+		return nil, ErrInvalidOtpContact.WithMessage("contact for new user otp must be a phone number")
 	}
 
 	// check if contact is an email
 	if validateOtpDto.OtpType == "verify_email" && !validation.IsValidEmail(validateOtpDto.Contact) {
 		fmt.Println("otp verification for email email must have an email in contact field")
 		logger.LogEvent("ERROR", "otp verification for email email must have an email in contact field")
-		return nil, errors.New("otp verification for email email must have an email in contact field")
+		return nil, ErrInvalidOtpContact.WithMessage("otp verification for email email must have an email in contact field")
 	}
 
 	// check if contact is a phone number
 	if validateOtpDto.OtpType == "verify_phone" && !validation.IsValidPhone(validateOtpDto.Contact) {
 		fmt.Println("otp verification for phone number must have a phone number in contact field")
 		logger.LogEvent("ERROR", "otp verification for phone number must have a phone number in contact field")
-		return nil, errors.New("otp verification for phone number must have a phone number in contact field")
+		return nil, ErrInvalidOtpContact.WithMessage("otp verification for phone number must have a phone number in contact field")
 	}
 
-	etoRequestData := struct {
-		UserReference string        `json:"userReference"`
-		Contact       string        `json:"contact"`
-		Otp           string        `json:"otp"`
-		Device        dto.DeviceDto `json:"device"`
-	}{
+	etoRequestData := events.ValidateOtpRequestEventData{
+		SchemaVersion: events.SchemaVersion,
 		UserReference: currentUserDto.UserReference,
 		Contact:       validateOtpDto.Contact,
 		Otp:           validateOtpDto.Otp,
@@ -2021,8 +3049,17 @@ func (service *userService) ValidateOtpRequest(ctx context.Context, user_referen
 			EventData:          etoRequestData,
 		},
 	}
-	eventPublisher := publisher.NewPublisher(service.redisClient)
-	eventPublisher.PublishValidateOtpRequestEvent(ctx, validateOtpRequestEvent, validateOtpDto.OtpType)
+	outboxEvent, err := newOutboxEvent(validateOtpRequestEvent)
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to prepare event for publishing")
+		return nil, errors.New("failed to prepare event for publishing")
+	}
+
+	_, err = service.outboxRepository.CreateOutboxEvent(ctx, outboxEvent)
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to queue event for publishing")
+		return nil, errors.New("failed to queue event for publishing")
+	}
 
 	result := "OTP sent for validation"
 
@@ -2037,7 +3074,7 @@ func (service *userService) CreateIdentityRequest(ctx context.Context, user_refe
 	userData, err := service.GetUserByReference(ctx, user_reference)
 	if err != nil {
 		logger.LogEvent("ERROR", "Unauthorized User: Failed to fetch identity for the user with reference: "+user_reference)
-		return nil, errors.New("unauthorized user: failed to retrieve identity")
+		return nil, lookupError(err, ErrUnknownIdentity)
 	}
 
 	// Convert identityData to entity.Identity type
@@ -2045,24 +3082,21 @@ func (service *userService) CreateIdentityRequest(ctx context.Context, user_refe
 
 	//check if device is registered
 
-	if !isRegisteredDevice(entity.Device(currentUserDto.Device), user.Device) {
+	if !isRegisteredDevice(entity.Device(currentUserDto.Device), user) {
 		logger.LogEvent("ERROR", "Unauthorized Device: This device is not registered to this user")
-		return nil, errors.New("unauthorized device: this device is not registered to this user")
+		return nil, ErrDeviceNotRegistered.WithMessage("unauthorized device: this device is not registered to this user")
 	}
 	//check if the phone number is registered
 	if !isRegisteredPhoneNumber(currentUserDto.Phone, user.UserProfile.Phone) {
 		logger.LogEvent("ERROR", "Unauthorized Phone: The phone number is not registered to this user")
-		return nil, errors.New("unauthorized phone: the phone number is not registered to this user")
+		return nil, ErrPhoneNotRegistered.WithMessage("unauthorized phone: the phone number is not registered to this user")
 	}
 
-	request := struct {
-		UserReference string        `json:"userReference"`
-		Phone         string        `json:"phone"`
-		Device        dto.DeviceDto `json:"device"`
-	}{
-		currentUserDto.UserReference,
-		requestIdentityDto.Phone,
-		requestIdentityDto.Device,
+	request := events.CreateIdentityRequestEventData{
+		SchemaVersion: events.SchemaVersion,
+		UserReference: currentUserDto.UserReference,
+		Phone:         requestIdentityDto.Phone,
+		Device:        requestIdentityDto.Device,
 	}
 	createIdentityRequestEvent := event.CreateIdentityRequestEvent{
 		Event: eto.Event{
@@ -2076,20 +3110,35 @@ func (service *userService) CreateIdentityRequest(ctx context.Context, user_refe
 		},
 	}
 
-	eventPublisher := publisher.NewPublisher(service.redisClient)
-	eventPublisher.PublishCreateIdentityRequestEvent(ctx, createIdentityRequestEvent)
+	outboxEvent, err := newOutboxEvent(createIdentityRequestEvent)
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to prepare event for publishing")
+		return nil, errors.New("failed to prepare event for publishing")
+	}
+
+	_, err = service.outboxRepository.CreateOutboxEvent(ctx, outboxEvent)
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to queue event for publishing")
+		return nil, errors.New("failed to queue event for publishing")
+	}
 	return request.UserReference, nil
 
 }
 
 func (service *userService) UpgradeTierRequest(ctx context.Context, user_reference string, requestTierDto dto.TierUpgradeRequestDto, currentUserDto dto.CurrentUserDto) (interface{}, error) {
+	return retryOnConflict(func() (interface{}, error) {
+		return service.upgradeTierRequest(ctx, user_reference, requestTierDto, currentUserDto)
+	})
+}
+
+func (service *userService) upgradeTierRequest(ctx context.Context, user_reference string, requestTierDto dto.TierUpgradeRequestDto, currentUserDto dto.CurrentUserDto) (interface{}, error) {
 	logger.LogEvent("INFO", "Requesting Tier Upgrade")
 
 	// Fetch the identity by user reference
 	userData, err := service.GetUserByReference(ctx, user_reference)
 	if err != nil {
 		logger.LogEvent("ERROR", "Unauthorized User: Failed to fetch identity for the user with reference: "+user_reference)
-		return nil, errors.New("unauthorized user: failed to retrieve identity")
+		return nil, lookupError(err, ErrUnknownIdentity)
 	}
 
 	// Convert identityData to entity.Identity type
@@ -2097,38 +3146,30 @@ func (service *userService) UpgradeTierRequest(ctx context.Context, user_referen
 
 	//check if device is registered
 
-	if !isRegisteredDevice(entity.Device(currentUserDto.Device), user.Device) {
+	if !isRegisteredDevice(entity.Device(currentUserDto.Device), user) {
 		logger.LogEvent("ERROR", "Unauthorized Device: This device is not registered to this user")
-		return nil, errors.New("unauthorized device: this device is not registered to this user")
+		return nil, ErrDeviceNotRegistered.WithMessage("unauthorized device: this device is not registered to this user")
 	}
 	//check if the phone number is registered
 	if !isRegisteredPhoneNumber(currentUserDto.Phone, user.UserProfile.Phone) {
 		logger.LogEvent("ERROR", "Unauthorized Phone: The phone number is not registered to this user")
-		return nil, errors.New("unauthorized phone: the phone number is not registered to this user")
+		return nil, ErrPhoneNotRegistered.WithMessage("unauthorized phone: the phone number is not registered to this user")
 	}
 
 	if len(requestTierDto.TierDocuments) == 0 {
 		logger.LogEvent("ERROR", "No uploaded documents: No uploaded documents found for this request. Kindly uploaded documents and try again.")
-		return nil, errors.New("no uploaded documents: No uploaded documents found for this request. Kindly uploaded documents and try again")
-	}
-
-	request := struct {
-		RequestReference string                  `json:"request_reference"`
-		User             dto.UserDto             `json:"user"`
-		CurrentTier      entity.Tier             `json:"current_tier"`
-		RequestedTier    dto.TierDto             `json:"requested_tier"`
-		KycDocuments     []entity.Documentation `json:"kyc_documents"`
-		TierDocuments []dto.DocumentationDto 	`json:"tier_documents"`
-		RequestStatus   string                  `json:"request_status"`
-	}{
-		uuid.New().String(),
-		requestTierDto.User,
-		user.Wallet.Tier,
-		requestTierDto.RequestedTier,
-		user.Kyc.Documentations,
-		requestTierDto.TierDocuments,
-		"pending",
+		return nil, ErrNoUploadedDocuments
+	}
 
+	request := events.TierUpgradeRequestEventData{
+		SchemaVersion:    events.SchemaVersion,
+		RequestReference: uuid.New().String(),
+		UserReference:    user.UserReference,
+		CurrentTier:      user.Wallet.Tier,
+		RequestedTier:    requestTierDto.RequestedTier,
+		KycDocuments:     user.Kyc.Documentations,
+		TierDocuments:    requestTierDto.TierDocuments,
+		RequestStatus:    shared.TierRequestPending,
 	}
 	upgradeTierRequest := event.TierUpgradeRequestEvent{
 		Event: eto.Event{
@@ -2142,64 +3183,189 @@ func (service *userService) UpgradeTierRequest(ctx context.Context, user_referen
 		},
 	}
 
-	eventPublisher := publisher.NewPublisher(service.redisClient)
-	eventPublisher.PublishUpgradeTierRequestEvent(ctx, upgradeTierRequest)
+	outboxEvent, err := newOutboxEvent(upgradeTierRequest)
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to prepare event for publishing")
+		return nil, errors.New("failed to prepare event for publishing")
+	}
+
+	// Keep the request so the approval or rejection can be recorded against it
+	user.TierRequests = append(user.TierRequests, entity.TierRequest{
+		RequestReference: request.RequestReference,
+		UserReference:    user.UserReference,
+		CurrentTier:      user.Wallet.Tier,
+		RequestedTier:    entity.Tier(requestTierDto.RequestedTier),
+		RequestStatus:    shared.TierRequestPending,
+		RequestedOn:      time.Now().Format(time.RFC3339),
+	})
+
+	_, err = service.userRepository.UpdateUser(ctx, user_reference, user, outboxEvent)
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to record tier upgrade request")
+		return nil, updateError(err, "failed to record tier upgrade request")
+	}
 	return request.RequestReference, nil
 }
 
+// ApplyTierUpgradeDecision - records the approval or rejection of a tier
+// upgrade request against it. An approval moves the user's wallet onto the
+// approved tier, limits included, and publishes the updated tier.
+func (service *userService) ApplyTierUpgradeDecision(ctx context.Context, user_reference string, request_reference string, tierUpgradeDecisionDto dto.TierUpgradeDecisionDto) (interface{}, error) {
+	return retryOnConflict(func() (interface{}, error) {
+		return service.applyTierUpgradeDecision(ctx, user_reference, request_reference, tierUpgradeDecisionDto)
+	})
+}
+
+func (service *userService) applyTierUpgradeDecision(ctx context.Context, user_reference string, request_reference string, tierUpgradeDecisionDto dto.TierUpgradeDecisionDto) (interface{}, error) {
+	logger.LogEvent("INFO", "Applying tier upgrade decision for request with reference: "+request_reference)
+	userData, err := service.GetUserByReference(ctx, user_reference)
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to retrieve user")
+		return nil, err
+	}
+	user := userData.(entity.User)
+
+	requestIndex := -1
+	for i, tierRequest := range user.TierRequests {
+		if tierRequest.RequestReference == request_reference {
+			requestIndex = i
+			break
+		}
+	}
+	if requestIndex == -1 {
+		// Requests raised before they were kept on the user are recorded as decided
+		user.TierRequests = append(user.TierRequests, entity.TierRequest{
+			RequestReference: request_reference,
+			UserReference:    user.UserReference,
+			CurrentTier:      user.Wallet.Tier,
+			RequestedTier:    tierUpgradeDecisionDto.ApprovedTier,
+		})
+		requestIndex = len(user.TierRequests) - 1
+	} else if user.TierRequests[requestIndex].RequestStatus != shared.TierRequestPending {
+		logger.LogEvent("INFO", "Tier upgrade request with reference: "+request_reference+" has already been "+user.TierRequests[requestIndex].RequestStatus)
+		return request_reference, nil
+	}
+
+	decidedOn := tierUpgradeDecisionDto.DecidedOn
+	if decidedOn == "" {
+		decidedOn = time.Now().Format(time.RFC3339)
+	}
+	tierRequest := &user.TierRequests[requestIndex]
+	tierRequest.Reason = tierUpgradeDecisionDto.Reason
+	tierRequest.DecidedOn = decidedOn
+
+	switch tierUpgradeDecisionDto.RequestStatus {
+	case shared.TierRequestApproved:
+		if tierUpgradeDecisionDto.ApprovedTier.TierReference == "" {
+			logger.LogEvent("ERROR", "Approved tier upgrade request with reference: "+request_reference+" carries no tier")
+			return nil, ErrApprovedTierMissing
+		}
+		tierRequest.RequestStatus = shared.TierRequestApproved
+		user.Wallet.Tier = service.catalogueTier(ctx, tierUpgradeDecisionDto.ApprovedTier)
+
+	case shared.TierRequestRejected:
+		tierRequest.RequestStatus = shared.TierRequestRejected
+		logger.LogEvent("INFO", "Tier upgrade request with reference: "+request_reference+" rejected: "+tierUpgradeDecisionDto.Reason)
+
+		result, err := service.userRepository.UpdateUser(ctx, user_reference, user)
+		if err != nil {
+			logger.LogEvent("ERROR", "Failed to record tier upgrade rejection for the user")
+			return nil, updateError(err, "failed to record tier upgrade rejection for the user")
+		}
+		return result, nil
+
+	default:
+		logger.LogEvent("ERROR", "Invalid tier upgrade request status: "+tierUpgradeDecisionDto.RequestStatus)
+		return nil, ErrInvalidVerificationStatus.WithMessage("invalid tier upgrade request status")
+	}
+
+	tierUpdatedEvent := event.TierUpdatedEvent{
+		Event: eto.Event{
+			EventReference:     uuid.New().String(),
+			EventName:          "tierupdatedevent",
+			EventDate:          time.Now().Format(time.RFC3339),
+			EventType:          "tierupdatedevent",
+			EventSource:        configuration.ServiceConfiguration.ServiceName,
+			EventUserReference: user.UserReference,
+			EventData:          mapper.UserToTierUpdatedEventData(user),
+		},
+	}
+
+	outboxEvent, err := newOutboxEvent(tierUpdatedEvent)
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to prepare event for publishing")
+		return nil, errors.New("failed to prepare event for publishing")
+	}
+
+	result, err := service.userRepository.UpdateUser(ctx, user_reference, user, outboxEvent)
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to update tier for the user")
+		return nil, updateError(err, "failed to update tier for the user")
+	}
+
+	return result, nil
+}
+
 func (service *userService) CreateTransactionRequest(ctx context.Context, user_reference string, transactionDto dto.CreateTransactionDto, currentUserDto dto.CurrentUserDto) (interface{}, error) {
 	logger.LogEvent("INFO", "Requesting Tier Upgrade")
 
 	senderData, err := service.GetUserByReference(ctx, currentUserDto.UserReference)
 	if err != nil {
 		logger.LogEvent("ERROR", "Failed to retrieve user")
-		return nil, errors.New("failed to retrieve user")
+		return nil, err
 	}
-	sender := mapper.UserDtoToUser(senderData.(dto.UserDto))
+	sender := senderData.(entity.User)
 
 	receiverData, err := service.GetUserByReference(ctx, currentUserDto.UserReference)
 	if err != nil {
 		logger.LogEvent("ERROR", "Failed to retrieve user")
-		return nil, errors.New("failed to retrieve user")
+		return nil, err
 	}
-	receiver := mapper.UserDtoToUser(receiverData.(dto.UserDto))
+	receiver := receiverData.(entity.User)
 
-	if !isRegisteredDevice(entity.Device(currentUserDto.Device), sender.Device) {
+	if !isRegisteredDevice(entity.Device(currentUserDto.Device), sender) {
 		logger.LogEvent("ERROR", "Unauthorized Device: This device is not registered to this user")
-		return nil, errors.New("unauthorized device: this device is not registered to this user")
+		return nil, ErrDeviceNotRegistered.WithMessage("unauthorized device: this device is not registered to this user")
 	}
 	//check if the phone number is registered
 	if !isRegisteredPhoneNumber(currentUserDto.Phone, sender.UserProfile.Phone) {
 		logger.LogEvent("ERROR", "Unauthorized Phone: The phone number is not registered to this user")
-		return nil, errors.New("unauthorized phone: the phone number is not registered to this user")
+		return nil, ErrPhoneNotRegistered.WithMessage("unauthorized phone: the phone number is not registered to this user")
 	}
 
 	if sender.UserReference != currentUserDto.UserReference {
 		logger.LogEvent("ERROR", "Unauthorized transaction")
-		return nil, errors.New("unauthorized transaction")
+		return nil, ErrUnauthorizedTransaction
+	}
+
+	// what a newly bound device sends is limited in total while it cools off
+	coolingOff := isCoolingOff(entity.Device(currentUserDto.Device), sender)
+	if coolingOff && coolingOffSent(entity.Device(currentUserDto.Device), sender)+transactionDto.Amount > deviceCoolingOffLimit() {
+		logger.LogEvent("ERROR", "The transaction amount exceeds the limit of a newly bound device")
+		return nil, ErrCoolingOffLimitExceeded
 	}
 
 	if sender.Wallet.Balance.AvailableAmount < transactionDto.Amount {
 		logger.LogEvent("ERROR", "Insufficient funds in sender's wallet")
-		return nil, errors.New("insufficient funds in sender's wallet")
+		return nil, ErrInsufficientFunds
 	}
 
 	// Check if the transaction amount exceeds the sender's sending limit
 	if transactionDto.Amount > sender.Wallet.Tier.SendingLimit {
 		logger.LogEvent("ERROR", "The transaction amount exceeds the sender's sending limit")
-		return nil, errors.New("the transaction amount exceeds the sender's sending limit")
+		return nil, ErrSendingLimitExceeded
 	}
 
 	// Check if receiver's wallet can accept more funds
 	if receiver.Wallet.Balance.AvailableAmount+transactionDto.Amount > receiver.Wallet.Tier.WalletLimit {
 		logger.LogEvent("ERROR", "Receiver's wallet limit exceeded")
-		return nil, errors.New("receiver's wallet limit exceeded")
+		return nil, ErrWalletLimitExceeded
 	}
 
 	// Check if receiver's wallet can accept these funds at once
 	if receiver.Wallet.Balance.AvailableAmount > receiver.Wallet.Tier.ReceivingLimit {
 		logger.LogEvent("ERROR", "Receiver's receiving limit exceeded")
-		return nil, errors.New("receiver's receiving limit exceeded")
+		return nil, ErrReceivingLimitExceeded
 	}
 
 	requestSender := entity.Sender{
@@ -2213,7 +3379,7 @@ func (service *userService) CreateTransactionRequest(ctx context.Context, user_r
 		requestReceiverDefaultWallsBadge, err = service.userRepository.GetUserDefaultWallsBadge(ctx, transactionDto.ReceiverReference)
 		if err != nil {
 			logger.LogEvent("ERROR", "Failed to retrieve user default badge. Aborting transaction.")
-			return nil, errors.New("failed to retrieve user. aborting transaction")
+			return nil, lookupError(err, ErrWallsBadgeNotFound)
 		}
 		transactionDto.ReceiverWallsBadgeReference = requestReceiverDefaultWallsBadge.(entity.WallsBadge).WallsBadgeReference
 	}
@@ -2225,20 +3391,14 @@ func (service *userService) CreateTransactionRequest(ctx context.Context, user_r
 		WallsBadgeReference: transactionDto.ReceiverWallsBadgeReference,
 	}
 
-	request := struct {
-		RequestReference string          `json:"request_reference"`
-		TransactionType  string          `json:"transaction_type"`
-		Amount           float64         `json:"amount"`
-		Sender           entity.Sender   `json:"sender"`
-		Receiver         entity.Receiver `json:"receiver"`
-		Metadata         entity.Metadata `json:"metadata"`
-	}{
-		uuid.New().String(),
-		transactionDto.TransactionType,
-		transactionDto.Amount,
-		requestSender,
-		requestReceiver,
-		transactionDto.Metadata,
+	request := events.TransactionCreateRequestData{
+		SchemaVersion:    events.SchemaVersion,
+		RequestReference: uuid.New().String(),
+		TransactionType:  transactionDto.TransactionType,
+		Amount:           transactionDto.Amount,
+		Sender:           requestSender,
+		Receiver:         requestReceiver,
+		Metadata:         transactionDto.Metadata,
 	}
 
 	transactionRequest := event.TransactionCreateRequest{
@@ -2253,8 +3413,27 @@ func (service *userService) CreateTransactionRequest(ctx context.Context, user_r
 		},
 	}
 
-	eventPublisher := publisher.NewPublisher(service.redisClient)
-	eventPublisher.PublishCreateTransactionRequestEvent(ctx, transactionRequest)
+	outboxEvent, err := newOutboxEvent(transactionRequest)
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to prepare event for publishing")
+		return nil, errors.New("failed to prepare event for publishing")
+	}
+
+	// The request counts towards the cooling-off total as it is queued, so
+	// concurrent requests cannot each pass the check above.
+	if coolingOff {
+		_, err = service.userRepository.AddCoolingOffSpend(ctx, sender.UserReference, entity.Device(currentUserDto.Device), transactionDto.Amount, deviceCoolingOffLimit(), outboxEvent)
+		if errors.Is(err, ports.ErrVersionConflict) {
+			logger.LogEvent("ERROR", "The transaction amount exceeds the limit of a newly bound device")
+			return nil, ErrCoolingOffLimitExceeded
+		}
+	} else {
+		_, err = service.outboxRepository.CreateOutboxEvent(ctx, outboxEvent)
+	}
+	if err != nil {
+		logger.LogEvent("ERROR", "Failed to queue event for publishing")
+		return nil, errors.New("failed to queue event for publishing")
+	}
 	return request.RequestReference, nil
 }
 func (service *userService) CreateUserReference(ctx context.Context) (interface{}, error) {
@@ -2278,14 +3457,172 @@ func (service *userService) CreateDocumentation(ctx context.Context, user_refere
 	return nil, nil
 }
 
-func isRegisteredDevice(currentDevice entity.Device, registeredDevice entity.Device) bool {
+// retryOnConflict - runs a read-modify-write of a user again, from a fresh read,
+// while it conflicts with a concurrent write, up to maxUpdateAttempts times.
+func retryOnConflict(update func() (interface{}, error)) (interface{}, error) {
+	for attempt := 1; ; attempt++ {
+		result, err := update()
+		if !errors.Is(err, ports.ErrVersionConflict) || attempt == maxUpdateAttempts {
+			return result, err
+		}
+		logger.LogEvent("INFO", fmt.Sprintf("User changed by another request, retrying update (attempt %d of %d)", attempt+1, maxUpdateAttempts))
+	}
+}
+
+// updateError - a failed user write: a version conflict as is, so the update can
+// be retried, a duplicate as is, so it is reported as a conflict, otherwise the
+// given failure.
+func updateError(err error, failure string) error {
+	if errors.Is(err, ports.ErrVersionConflict) || errors.Is(err, ports.ErrDuplicateKey) {
+		return err
+	}
+	return errors.New(failure)
+}
+
+// newOutboxEvent - captures a domain event for the outbox relay. The payload is
+// encoded once, so every retry publishes the same EventReference.
+func newOutboxEvent(domainEvent interface{ Envelope() eto.Event }, eventType ...string) (entity.OutboxEvent, error) {
+	channel, err := event.ChannelFor(domainEvent, eventType...)
+	if err != nil {
+		return entity.OutboxEvent{}, err
+	}
+
+	payload, err := helper.EncodeEvent(domainEvent, channel)
+	if err != nil {
+		return entity.OutboxEvent{}, err
+	}
+
+	envelope := domainEvent.Envelope()
+	now := time.Now().UTC().Format(time.RFC3339)
+	outboxEvent := entity.OutboxEvent{
+		OutboxReference: uuid.New().String(),
+		EventReference:  envelope.EventReference,
+		UserReference:   envelope.EventUserReference,
+		Channel:         channel,
+		Payload:         string(payload),
+		Status:          shared.OutboxPending,
+		CreatedOn:       now,
+		NextAttemptOn:   now,
+	}
+	return outboxEvent, nil
+}
+
+// catalogueTier - the tier with its limits as the tier catalogue holds them. A
+// tier missing from the catalogue keeps the limits it was given.
+func (service *userService) catalogueTier(ctx context.Context, tier entity.Tier) entity.Tier {
+	if service.tierRepository == nil || tier.TierReference == "" {
+		return tier
+	}
+
+	tierData, err := service.tierRepository.GetTierByReference(ctx, tier.TierReference)
+	if err != nil {
+		return tier
+	}
+	return tierData.(entity.TierConfig).Tier
+}
+
+// withCatalogueTier - resolves a retrieved user's tier limits from the tier catalogue.
+func (service *userService) withCatalogueTier(ctx context.Context, userData interface{}) interface{} {
+	user, ok := userData.(entity.User)
+	if !ok {
+		return userData
+	}
+
+	user.Wallet.Tier = service.catalogueTier(ctx, user.Wallet.Tier)
+	return user
+}
+
+// encodeUserCursor - the opaque form of a user search cursor handed to clients.
+func encodeUserCursor(cursor entity.UserCursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeUserCursor(encoded string) (entity.UserCursor, error) {
+	cursor := entity.UserCursor{}
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return cursor, err
+	}
+	err = json.Unmarshal(data, &cursor)
+	return cursor, err
+}
+
+// searchLimit - DBConnection__PageLimit, the page size of a user search that
+// asks for none.
+func searchLimit() int64 {
+	limit, err := strconv.ParseInt(configuration.ServiceConfiguration.PageLimit, 10, 64)
+	if err != nil || limit <= 0 {
+		return defaultSearchLimit
+	}
+	return limit
+}
+
+// isRegisteredDevice - whether the device is one of the user's trusted devices. Devices
+// are matched on their reference and IMEI, so an updated brand or model still matches.
+// Users registered before the device list are matched on their single device.
+func isRegisteredDevice(currentDevice entity.Device, user entity.User) bool {
+	if len(user.Devices) == 0 {
+		return user.Device.Is(currentDevice)
+	}
+
+	for _, device := range user.Devices {
+		if device.Is(currentDevice) && device.Status == shared.DeviceTrusted {
+			return true
+		}
+	}
+	return false
+}
+
+// isPendingDevice - whether the device is waiting for its binding to the user to be confirmed.
+func isPendingDevice(currentDevice entity.Device, user entity.User) bool {
+	for _, device := range user.Devices {
+		if device.Is(currentDevice) && device.Status == shared.DevicePending {
+			return true
+		}
+	}
+	return false
+}
+
+// isCoolingOff - whether the device was bound to the user too recently to send
+// more than the cooling-off limit.
+func isCoolingOff(currentDevice entity.Device, user entity.User) bool {
+	for _, device := range user.Devices {
+		if device.Is(currentDevice) && device.CoolingOffUntil != "" {
+			until, err := time.Parse(time.RFC3339, device.CoolingOffUntil)
+			return err == nil && time.Now().Before(until)
+		}
+	}
+	return false
+}
+
+// coolingOffSent - what the device has sent since it was bound to the user.
+func coolingOffSent(currentDevice entity.Device, user entity.User) float64 {
+	for _, device := range user.Devices {
+		if device.Is(currentDevice) {
+			return device.CoolingOffSent
+		}
+	}
+	return 0
+}
 
-	return currentDevice.Imei == registeredDevice.Imei &&
-		currentDevice.Type == registeredDevice.Type &&
-		currentDevice.Brand == registeredDevice.Brand &&
-		currentDevice.Model == registeredDevice.Model &&
-		currentDevice.DeviceReference == registeredDevice.DeviceReference
+// deviceCoolingOff - Device__CoolingOffHours, a day when unset.
+func deviceCoolingOff() time.Duration {
+	hours, err := strconv.Atoi(configuration.ServiceConfiguration.DeviceCoolingOff)
+	if err != nil || hours < 0 {
+		return defaultDeviceCoolingOff
+	}
+	return time.Duration(hours) * time.Hour
+}
 
+// deviceCoolingOffLimit - Device__CoolingOffLimit, the most a device may send in
+// total while it cools off.
+func deviceCoolingOffLimit() float64 {
+	limit, err := strconv.ParseFloat(configuration.ServiceConfiguration.DeviceCoolingLimit, 64)
+	if err != nil || limit < 0 {
+		return defaultDeviceCoolingLimit
+	}
+	return limit
 }
 
 func isRegisteredPhoneNumber(currentPhone string, registeredPhone string) bool {
@@ -0,0 +1,936 @@
+package services
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	memoryRepository "walls-user-service/internal/adapter/repository/memory"
+	"walls-user-service/internal/core/domain/dto"
+	"walls-user-service/internal/core/domain/entity"
+	"walls-user-service/internal/core/domain/shared"
+	configuration "walls-user-service/internal/core/helper/configuration-helper"
+	errorhelper "walls-user-service/internal/core/helper/error-helper"
+	ports "walls-user-service/internal/port"
+)
+
+func newTestService(t *testing.T, users ...entity.User) (*userService, *memoryRepository.UserInfra) {
+	t.Helper()
+	outbox := memoryRepository.NewOutbox()
+	userRepository := memoryRepository.NewUser(outbox)
+	for _, user := range users {
+		if _, err := userRepository.CreateUser(context.Background(), user); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return NewUserService(userRepository, outbox, memoryRepository.NewTier()), userRepository
+}
+
+func storedUser(t *testing.T, userRepository *memoryRepository.UserInfra, user_reference string) entity.User {
+	t.Helper()
+	userData, err := userRepository.GetUserByReference(context.Background(), user_reference)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return userData.(entity.User)
+}
+
+func TestVerifyDocumentationStoresTheKycStatus(t *testing.T) {
+	user := entity.User{
+		UserReference: "user-1",
+		UserProfile:   entity.UserProfile{Phone: "+2348000000001"},
+		Kyc: entity.Kyc{
+			ProfileType: "user",
+			Documentations: []entity.Documentation{
+				{DocumentationReference: "documentation-1"},
+				{DocumentationReference: "documentation-2"},
+			},
+		},
+	}
+	service, userRepository := newTestService(t, user)
+
+	for _, documentation_reference := range []string{"documentation-1", "documentation-2"} {
+		verification := dto.DocumentationVerificationDto{IsVerified: true, VerificationMethod: "manual"}
+		if _, err := service.VerifyDocumentation(context.Background(), "user-1", documentation_reference, verification); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	kyc := storedUser(t, userRepository, "user-1").Kyc
+	if !kyc.IsVerified || kyc.VerifiedDocumentCount != 2 {
+		t.Errorf("expected the stored KYC status to count both documentations, got %+v", kyc)
+	}
+
+	verification := dto.DocumentationVerificationDto{IsVerified: false, VerificationMethod: "manual"}
+	if _, err := service.VerifyDocumentation(context.Background(), "user-1", "documentation-1", verification); err != nil {
+		t.Fatal(err)
+	}
+	if kyc := storedUser(t, userRepository, "user-1").Kyc; !kyc.IsVerified || kyc.VerifiedDocumentCount != 1 {
+		t.Errorf("expected a rejected documentation to be uncounted, got %+v", kyc)
+	}
+}
+
+func TestUpdateDocumentationUnverifiesTheKycStatus(t *testing.T) {
+	device := dto.DeviceDto{DeviceReference: "device-1", Imei: "123456789012345"}
+	currentUser := dto.CurrentUserDto{UserReference: "user-1", Phone: "+2348000000001", Device: device}
+	user := entity.User{
+		UserReference: "user-1",
+		UserProfile:   entity.UserProfile{Phone: "+2348000000001"},
+		Device:        entity.Device(device),
+		Kyc: entity.Kyc{
+			Documentations:        []entity.Documentation{{DocumentationReference: "documentation-1", IsVerified: true}},
+			IsVerified:            true,
+			VerifiedDocumentCount: 1,
+		},
+	}
+	outbox := memoryRepository.NewOutbox()
+	userRepository := memoryRepository.NewUser(outbox)
+	if _, err := userRepository.CreateUser(context.Background(), user); err != nil {
+		t.Fatal(err)
+	}
+	service := NewUserService(userRepository, outbox, memoryRepository.NewTier())
+
+	documentation := dto.AddDocumentationDto{DocumentationType: "nin", DocumentationNumber: "ABC", Expiry: "2030-01-01"}
+	if _, err := service.UpdateDocumentation(context.Background(), "user-1", "documentation-1", documentation, currentUser); err != nil {
+		t.Fatal(err)
+	}
+
+	if kyc := storedUser(t, userRepository, "user-1").Kyc; kyc.IsVerified || kyc.VerifiedDocumentCount != 0 || kyc.Documentations[0].IsVerified {
+		t.Errorf("expected the replaced documentation to leave the user unverified, got %+v", kyc)
+	}
+	outboxEvents, err := outbox.GetPendingOutboxEvents(context.Background(), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	channels := []string{}
+	for _, outboxEvent := range outboxEvents.([]entity.OutboxEvent) {
+		channels = append(channels, outboxEvent.Channel)
+	}
+	if len(channels) != 2 || channels[1] != "KYCSTATUSUPDATEDEVENT.V1" {
+		t.Errorf("expected the documentation and KYC status updates to be published, got %v", channels)
+	}
+}
+
+func TestApplyTierUpgradeDecisionStoresTheDecidedRequest(t *testing.T) {
+	user := entity.User{
+		UserReference: "user-1",
+		UserProfile:   entity.UserProfile{Phone: "+2348000000001"},
+		Wallet:        entity.Wallet{Tier: entity.Tier{TierReference: "tier-1", TierName: "basic"}},
+		TierRequests: []entity.TierRequest{
+			{RequestReference: "request-1", RequestStatus: shared.TierRequestPending},
+			{RequestReference: "request-2", RequestStatus: shared.TierRequestPending},
+		},
+	}
+	service, userRepository := newTestService(t, user)
+	ctx := context.Background()
+
+	approval := dto.TierUpgradeDecisionDto{
+		RequestStatus: shared.TierRequestApproved,
+		ApprovedTier:  entity.Tier{TierReference: "tier-2", TierName: "premium"},
+	}
+	if _, err := service.ApplyTierUpgradeDecision(ctx, "user-1", "request-1", approval); err != nil {
+		t.Fatal(err)
+	}
+	rejection := dto.TierUpgradeDecisionDto{RequestStatus: shared.TierRequestRejected, Reason: "expired documents"}
+	if _, err := service.ApplyTierUpgradeDecision(ctx, "user-1", "request-2", rejection); err != nil {
+		t.Fatal(err)
+	}
+
+	stored := storedUser(t, userRepository, "user-1")
+	if stored.Wallet.Tier.TierReference != "tier-2" {
+		t.Errorf("expected the approved tier to be stored, got %+v", stored.Wallet.Tier)
+	}
+	if len(stored.TierRequests) != 2 {
+		t.Fatalf("expected both tier requests to be kept, got %+v", stored.TierRequests)
+	}
+	if approved := stored.TierRequests[0]; approved.RequestStatus != shared.TierRequestApproved || approved.DecidedOn == "" {
+		t.Errorf("expected request-1 to be stored as approved, got %+v", approved)
+	}
+	if rejected := stored.TierRequests[1]; rejected.RequestStatus != shared.TierRequestRejected || rejected.Reason != "expired documents" {
+		t.Errorf("expected request-2 to be stored as rejected, got %+v", rejected)
+	}
+
+	// A decided request is not decided again.
+	if _, err := service.ApplyTierUpgradeDecision(ctx, "user-1", "request-2", approval); err != nil {
+		t.Fatal(err)
+	}
+	if stored := storedUser(t, userRepository, "user-1"); stored.TierRequests[1].RequestStatus != shared.TierRequestRejected {
+		t.Errorf("expected request-2 to stay rejected, got %+v", stored.TierRequests[1])
+	}
+}
+
+func TestUpdatesKeepTheCatalogueLimitsOutOfTheUser(t *testing.T) {
+	user := entity.User{
+		UserReference: "user-1",
+		UserProfile:   entity.UserProfile{Phone: "+2348000000001"},
+		Wallet:        entity.Wallet{Tier: entity.Tier{TierReference: "tier-1", TierName: "basic", SendingLimit: 100}},
+		TierRequests: []entity.TierRequest{
+			{RequestReference: "request-1", RequestStatus: shared.TierRequestPending},
+			{RequestReference: "request-2", RequestStatus: shared.TierRequestPending},
+		},
+	}
+	service, userRepository := newTestService(t, user)
+	ctx := context.Background()
+	for _, tier := range []entity.Tier{
+		{TierReference: "tier-1", TierName: "basic", SendingLimit: 500},
+		{TierReference: "tier-2", TierName: "premium", SendingLimit: 900},
+	} {
+		if _, err := service.tierRepository.UpsertTier(ctx, entity.TierConfig{Tier: tier, UpdatedOn: "2024-01-01T00:00:00Z"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rejection := dto.TierUpgradeDecisionDto{RequestStatus: shared.TierRequestRejected, Reason: "expired documents"}
+	if _, err := service.ApplyTierUpgradeDecision(ctx, "user-1", "request-1", rejection); err != nil {
+		t.Fatal(err)
+	}
+	if tier := storedUser(t, userRepository, "user-1").Wallet.Tier; tier.SendingLimit != 100 {
+		t.Errorf("expected the catalogue's limits to stay out of the stored user, got %+v", tier)
+	}
+
+	approval := dto.TierUpgradeDecisionDto{
+		RequestStatus: shared.TierRequestApproved,
+		ApprovedTier:  entity.Tier{TierReference: "tier-2", TierName: "premium"},
+	}
+	if _, err := service.ApplyTierUpgradeDecision(ctx, "user-1", "request-2", approval); err != nil {
+		t.Fatal(err)
+	}
+	if tier := storedUser(t, userRepository, "user-1").Wallet.Tier; tier.TierReference != "tier-2" || tier.TierName != "premium" || tier.SendingLimit != 100 {
+		t.Errorf("expected only the approved tier to be stored, got %+v", tier)
+	}
+	userData, err := service.GetUserByReference(ctx, "user-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tier := userData.(entity.User).Wallet.Tier; tier.SendingLimit != 900 {
+		t.Errorf("expected the user to be read with the catalogue's limits, got %+v", tier)
+	}
+}
+
+func TestStaffUpdatesNeedNoDeviceOfTheUser(t *testing.T) {
+	user := entity.User{
+		UserReference: "user-1",
+		UserProfile:   entity.UserProfile{Phone: "+2348000000001"},
+		Device:        entity.Device{DeviceReference: "device-1", Imei: "123456789012345"},
+	}
+	service, userRepository := newTestService(t, user)
+	ctx := context.Background()
+
+	if _, err := service.UpdateBalance(ctx, "user-1", dto.BalanceDto{BookAmount: 150, AvailableAmount: 100}); err != nil {
+		t.Fatal(err)
+	}
+	tier := dto.TierDto{TierReference: "tier-2", TierName: "premium", DailyTransactionLimit: 500}
+	if _, err := service.UpdateTier(ctx, "user-1", tier); err != nil {
+		t.Fatal(err)
+	}
+
+	stored := storedUser(t, userRepository, "user-1")
+	if balance := stored.Wallet.Balance; balance.AvailableAmount != 100 || balance.PendingIncomingAmount != 150 || !balance.IsSynced {
+		t.Errorf("expected the balance to be stored, got %+v", balance)
+	}
+	if stored.Wallet.Tier.TierReference != "tier-2" || stored.Wallet.Tier.TierName != "premium" {
+		t.Errorf("expected the tier to be stored, got %+v", stored.Wallet.Tier)
+	}
+}
+
+// racingUserRepository - a user repository where another request disables the
+// user right after each of the first racingWrites reads.
+type racingUserRepository struct {
+	*memoryRepository.UserInfra
+	racingWrites int
+	reads        int
+}
+
+func (r *racingUserRepository) GetUserByReference(ctx context.Context, user_reference string) (interface{}, error) {
+	userData, err := r.UserInfra.GetUserByReference(ctx, user_reference)
+	r.reads++
+	if err == nil && r.reads <= r.racingWrites {
+		if _, err := r.UserInfra.SetUserActive(ctx, user_reference, false); err != nil {
+			return nil, err
+		}
+	}
+	return userData, err
+}
+
+func TestUpdatesRetryAVersionConflictFromAFreshRead(t *testing.T) {
+	user := entity.User{
+		UserReference: "user-1",
+		IsActive:      true,
+		UserProfile:   entity.UserProfile{Phone: "+2348000000001"},
+		Kyc:           entity.Kyc{Documentations: []entity.Documentation{{DocumentationReference: "documentation-1"}}},
+	}
+	_, userRepository := newTestService(t, user)
+	racing := &racingUserRepository{UserInfra: userRepository, racingWrites: 1}
+	service := NewUserService(racing, memoryRepository.NewOutbox(), memoryRepository.NewTier())
+
+	verification := dto.DocumentationVerificationDto{IsVerified: true}
+	if _, err := service.VerifyDocumentation(context.Background(), "user-1", "documentation-1", verification); err != nil {
+		t.Fatal(err)
+	}
+	if racing.reads != 2 {
+		t.Errorf("expected the conflicting update to read the user again, got %d reads", racing.reads)
+	}
+
+	// The retry starts from the concurrent write rather than overwriting it.
+	stored := storedUser(t, userRepository, "user-1")
+	if stored.IsActive || !stored.Kyc.Documentations[0].IsVerified || stored.Version != 2 {
+		t.Errorf("expected both writes to be kept at version 2, got %+v", stored)
+	}
+
+	// An update losing every race gives up with the conflict.
+	racing.reads, racing.racingWrites = 0, maxUpdateAttempts
+	_, err := service.VerifyDocumentation(context.Background(), "user-1", "documentation-1", verification)
+	if !errors.Is(err, ports.ErrVersionConflict) {
+		t.Errorf("expected ErrVersionConflict after %d attempts, got %v", maxUpdateAttempts, err)
+	}
+	if racing.reads != maxUpdateAttempts {
+		t.Errorf("expected %d reads, got %d", maxUpdateAttempts, racing.reads)
+	}
+}
+
+// interleavedUserRepository - a user repository where another request writes
+// the user right after the first read, through interleave.
+type interleavedUserRepository struct {
+	*memoryRepository.UserInfra
+	interleave func(ctx context.Context, user entity.User) error
+	reads      int
+}
+
+func (r *interleavedUserRepository) GetUserByReference(ctx context.Context, user_reference string) (interface{}, error) {
+	userData, err := r.UserInfra.GetUserByReference(ctx, user_reference)
+	r.reads++
+	if err == nil && r.reads == 1 {
+		if err := r.interleave(ctx, userData.(entity.User)); err != nil {
+			return nil, err
+		}
+	}
+	return userData, err
+}
+
+func TestSetDefaultInstrumentRejectedAfterTheReadIsRefused(t *testing.T) {
+	device := dto.DeviceDto{DeviceReference: "device-1", Imei: "123456789012345"}
+	currentUser := dto.CurrentUserDto{UserReference: "user-1", Phone: "+2348000000001", Device: device}
+	user := entity.User{
+		UserReference: "user-1",
+		UserProfile:   entity.UserProfile{Phone: "+2348000000001"},
+		Device:        entity.Device(device),
+		BankAccounts:  []entity.Bank{{BankReference: "bank-1", Status: shared.VerificationVerified}},
+		Cards:         []entity.Card{{CardReference: "card-1", Status: shared.VerificationVerified}},
+	}
+
+	for name, test := range map[string]struct {
+		update   func(ctx context.Context, service *userService) (interface{}, error)
+		expected error
+	}{
+		"bank": {
+			update: func(ctx context.Context, service *userService) (interface{}, error) {
+				return service.SetDefaultBank(ctx, "user-1", "bank-1", currentUser)
+			},
+			expected: ErrBankNotVerified,
+		},
+		"card": {
+			update: func(ctx context.Context, service *userService) (interface{}, error) {
+				return service.SetDefaultCard(ctx, "user-1", "card-1", currentUser)
+			},
+			expected: ErrCardNotVerified,
+		},
+	} {
+		_, userRepository := newTestService(t, user)
+		// The integrations fail every instrument of the user after the read.
+		rejecting := &interleavedUserRepository{UserInfra: userRepository, interleave: func(ctx context.Context, user entity.User) error {
+			for i := range user.BankAccounts {
+				user.BankAccounts[i].Status = shared.VerificationFailed
+			}
+			for i := range user.Cards {
+				user.Cards[i].Status = shared.VerificationFailed
+			}
+			_, err := userRepository.UpdateUser(ctx, "user-1", user)
+			return err
+		}}
+		service := NewUserService(rejecting, memoryRepository.NewOutbox(), memoryRepository.NewTier())
+
+		if _, err := test.update(context.Background(), service); !errors.Is(err, test.expected) {
+			t.Errorf("%s: expected %v, got %v", name, test.expected, err)
+		}
+		if stored := storedUser(t, userRepository, "user-1"); stored.BankAccounts[0].IsDefault || stored.Cards[0].IsDefault {
+			t.Errorf("%s: expected the failed instrument not to become the default, got %+v", name, stored)
+		}
+	}
+}
+
+func TestTargetedUpdatesOfAStoredUser(t *testing.T) {
+	device := dto.DeviceDto{DeviceReference: "device-1", Imei: "123456789012345", Type: "mobile", Brand: "acme", Model: "one"}
+	currentUser := dto.CurrentUserDto{UserReference: "user-1", Phone: "+2348000000001", Device: device}
+	newUser := func() entity.User {
+		return entity.User{
+			UserReference: "user-1",
+			UserProfile:   entity.UserProfile{Phone: "+2348000000001"},
+			Device:        entity.Device(device),
+			Wallet:        entity.Wallet{Reward: entity.Reward{Points: 10}},
+			BankAccounts: []entity.Bank{
+				{BankReference: "bank-1", Status: shared.VerificationVerified, IsDefault: true},
+				{BankReference: "bank-2", Status: shared.VerificationVerified},
+			},
+			Cards: []entity.Card{
+				{CardReference: "card-1", Status: shared.VerificationVerified, IsDefault: true},
+				{CardReference: "card-2", Status: shared.VerificationVerified},
+			},
+		}
+	}
+
+	for name, test := range map[string]struct {
+		update func(ctx context.Context, service *userService) (interface{}, error)
+		stored func(user entity.User) bool
+	}{
+		"add coupon": {
+			update: func(ctx context.Context, service *userService) (interface{}, error) {
+				return service.AddCoupon(ctx, "user-1", dto.CouponDto{CouponReference: "coupon-1", CouponId: "SAVE10", Discount_Percentage: 10})
+			},
+			stored: func(user entity.User) bool {
+				return len(user.Wallet.Coupons) == 1 && user.Wallet.Coupons[0].CouponReference == "coupon-1"
+			},
+		},
+		"update rewards": {
+			update: func(ctx context.Context, service *userService) (interface{}, error) {
+				return service.UpdateRewards(ctx, "user-1", dto.RewardDto{Points: 5})
+			},
+			stored: func(user entity.User) bool { return user.Wallet.Reward.Points == 15 },
+		},
+		"enable user": {
+			update: func(ctx context.Context, service *userService) (interface{}, error) {
+				return service.EnableUser(ctx, "user-1")
+			},
+			stored: func(user entity.User) bool { return user.IsActive },
+		},
+		"disable user": {
+			update: func(ctx context.Context, service *userService) (interface{}, error) {
+				service.EnableUser(ctx, "user-1")
+				return service.DisableUser(ctx, "user-1")
+			},
+			stored: func(user entity.User) bool { return !user.IsActive },
+		},
+		"set default bank": {
+			update: func(ctx context.Context, service *userService) (interface{}, error) {
+				return service.SetDefaultBank(ctx, "user-1", "bank-2", currentUser)
+			},
+			stored: func(user entity.User) bool {
+				return !user.BankAccounts[0].IsDefault && user.BankAccounts[1].IsDefault
+			},
+		},
+		"set default card": {
+			update: func(ctx context.Context, service *userService) (interface{}, error) {
+				return service.SetDefaultCard(ctx, "user-1", "card-2", currentUser)
+			},
+			stored: func(user entity.User) bool { return !user.Cards[0].IsDefault && user.Cards[1].IsDefault },
+		},
+		"add bank": {
+			update: func(ctx context.Context, service *userService) (interface{}, error) {
+				return service.AddBank(ctx, "user-1", dto.BankDto{BankName: "First Bank", AccountNumber: 1234567890, AccountName: "Ada Obi"}, currentUser)
+			},
+			stored: func(user entity.User) bool {
+				return len(user.BankAccounts) == 3 && user.BankAccounts[2].AccountNumber == 1234567890
+			},
+		},
+		"add contact": {
+			update: func(ctx context.Context, service *userService) (interface{}, error) {
+				return service.AddContact(ctx, "user-1", dto.ContactDto{Phone: "+2348000000002", FullName: "Chidi Obi", WallsTag: "walls002"}, currentUser)
+			},
+			stored: func(user entity.User) bool { return len(user.Contacts) == 1 && user.Contacts[0].WallsTag == "walls002" },
+		},
+		"update balance": {
+			update: func(ctx context.Context, service *userService) (interface{}, error) {
+				return service.UpdateBalance(ctx, "user-1", dto.BalanceDto{BookAmount: 20, AvailableAmount: 20})
+			},
+			stored: func(user entity.User) bool { return user.Wallet.Balance.AvailableAmount == 20 },
+		},
+		"update tier": {
+			update: func(ctx context.Context, service *userService) (interface{}, error) {
+				return service.UpdateTier(ctx, "user-1", dto.TierDto{TierReference: "tier-2", TierName: "premium", DailyTransactionLimit: 100})
+			},
+			stored: func(user entity.User) bool { return user.Wallet.Tier.TierReference == "tier-2" },
+		},
+	} {
+		service, userRepository := newTestService(t, newUser())
+		if _, err := test.update(context.Background(), service); err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		if stored := storedUser(t, userRepository, "user-1"); !test.stored(stored) {
+			t.Errorf("%s: the update was not stored, got %+v", name, stored)
+		}
+	}
+}
+
+func TestUpdateRewardsReportsTheTotalItProduced(t *testing.T) {
+	user := entity.User{
+		UserReference: "user-1",
+		UserProfile:   entity.UserProfile{Phone: "+2348000000001"},
+		Wallet:        entity.Wallet{Reward: entity.Reward{Points: 10}},
+	}
+	service, _ := newTestService(t, user)
+	ctx := context.Background()
+
+	for _, points := range []int{5, 7} {
+		if _, err := service.UpdateRewards(ctx, "user-1", dto.RewardDto{Points: points}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	outboxData, err := service.outboxRepository.GetPendingOutboxEvents(ctx, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	outboxEvents := outboxData.([]entity.OutboxEvent)
+	if len(outboxEvents) != 2 {
+		t.Fatalf("expected an event per addition, got %d", len(outboxEvents))
+	}
+	for i, total := range []string{`"points":15`, `"points":22`} {
+		if !strings.Contains(outboxEvents[i].Payload, total) {
+			t.Errorf("expected event %d to report %s, got %s", i, total, outboxEvents[i].Payload)
+		}
+	}
+
+	if _, err := service.UpdateRewards(ctx, "user-2", dto.RewardDto{Points: 5}); !errors.Is(err, ErrUserNotFound) {
+		t.Errorf("expected ErrUserNotFound for an unknown user, got %v", err)
+	}
+}
+
+func TestSearchUsersCursorRoundTrip(t *testing.T) {
+	users := []entity.User{}
+	for _, reference := range []string{"user-1", "user-2", "user-3"} {
+		users = append(users, entity.User{UserReference: reference, CreatedOn: "2024-01-01T00:00:00Z"})
+	}
+	service, _ := newTestService(t, users...)
+
+	search := dto.UserSearchDto{Order: "asc", Limit: 2}
+	result, err := service.SearchUsers(context.Background(), search)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := result.(dto.UserPageDto)
+	if len(first.Users) != 2 || first.NextCursor == "" {
+		t.Fatalf("expected a first page of 2 users and a cursor, got %d and %q", len(first.Users), first.NextCursor)
+	}
+	expected := entity.UserCursor{SortBy: "created_on", SortValue: "2024-01-01T00:00:00Z", UserReference: "user-2"}
+	if cursor, err := decodeUserCursor(first.NextCursor); err != nil || cursor != expected {
+		t.Errorf("expected the cursor to decode to %+v, got %+v and %v", expected, cursor, err)
+	}
+
+	search.Cursor = first.NextCursor
+	result, err = service.SearchUsers(context.Background(), search)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second := result.(dto.UserPageDto); len(second.Users) != 1 || second.Users[0].UserReference != "user-3" || second.NextCursor != "" {
+		t.Errorf("expected a last page of user-3, got %+v", second)
+	}
+}
+
+func TestSearchUsersSummarisesTheStoredKycStatus(t *testing.T) {
+	verified := entity.User{
+		UserReference:  "user-1",
+		CreatedOn:      "2024-01-01T00:00:00Z",
+		IsActive:       true,
+		UserProfile:    entity.UserProfile{FullName: "Ada Obi", Phone: "+2348000000001", Email: "ada@example.com"},
+		CompanyProfile: []entity.CompanyProfile{{CompanyName: "Walls Ltd"}},
+		Wallet:         entity.Wallet{Tier: entity.Tier{TierReference: "tier-1", TierName: "basic"}},
+		Cards:          []entity.Card{{CardReference: "card-1", Pan: "4111111111111111"}},
+		Kyc:            entity.Kyc{IsVerified: true, VerifiedDocumentCount: 1},
+	}
+	// Verified documentations do not count until the KYC status says so.
+	unverified := entity.User{
+		UserReference: "user-2",
+		CreatedOn:     "2024-01-02T00:00:00Z",
+		Kyc:           entity.Kyc{Documentations: []entity.Documentation{{DocumentationReference: "documentation-2", IsVerified: true}}},
+	}
+	service, _ := newTestService(t, verified, unverified)
+
+	kycVerified := true
+	result, err := service.SearchUsers(context.Background(), dto.UserSearchDto{KycVerified: &kycVerified})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := dto.UserPageDto{Total: 1, Users: []dto.UserSummaryDto{{
+		UserReference: "user-1",
+		FullName:      "Ada Obi",
+		Phone:         "+2348000000001",
+		Email:         "ada@example.com",
+		IsActive:      true,
+		TierName:      "basic",
+		KycVerified:   true,
+		CompanyNames:  []string{"Walls Ltd"},
+		CreatedOn:     "2024-01-01T00:00:00Z",
+	}}}
+	if page := result.(dto.UserPageDto); !reflect.DeepEqual(page, expected) {
+		t.Errorf("expected %+v, got %+v", expected, page)
+	}
+}
+
+func TestSearchUsersRejectsABadCursorOrSort(t *testing.T) {
+	service, _ := newTestService(t)
+	createdOn := encodeUserCursor(entity.UserCursor{SortBy: "created_on", Descending: true, SortValue: "2024-01-01", UserReference: "user-1"})
+
+	for _, test := range []struct {
+		name     string
+		search   dto.UserSearchDto
+		expected error
+	}{
+		{"cursor not base64", dto.UserSearchDto{Cursor: "not a cursor!"}, ErrInvalidCursor},
+		{"cursor not json", dto.UserSearchDto{Cursor: base64.RawURLEncoding.EncodeToString([]byte("user-1"))}, ErrInvalidCursor},
+		{"cursor of another sort", dto.UserSearchDto{Sort: "full_name", Cursor: createdOn}, ErrInvalidCursor},
+		{"cursor of another order", dto.UserSearchDto{Order: "asc", Cursor: createdOn}, ErrInvalidCursor},
+		{"unknown sort", dto.UserSearchDto{Sort: "password"}, ErrInvalidSort},
+	} {
+		_, err := service.SearchUsers(context.Background(), test.search)
+		if !errors.Is(err, test.expected) {
+			t.Errorf("%s: expected %v, got %v", test.name, test.expected, err)
+			continue
+		}
+		if response := errorhelper.ErrorFrom(err); response.Code != 400 {
+			t.Errorf("%s: expected a 400, got %d", test.name, response.Code)
+		}
+	}
+
+	if _, err := service.SearchUsers(context.Background(), dto.UserSearchDto{Cursor: createdOn}); err != nil {
+		t.Errorf("expected the cursor to continue its own search, got %v", err)
+	}
+}
+
+func TestApplyLedgerBalanceInSequenceOrder(t *testing.T) {
+	user := entity.User{
+		UserReference: "user-1",
+		UserProfile:   entity.UserProfile{Phone: "+2348000000001"},
+		Wallet:        entity.Wallet{WalletReference: "wallet-1"},
+	}
+	service, userRepository := newTestService(t, user)
+	ctx := context.Background()
+
+	for _, push := range []struct {
+		sequence  int64
+		amount    float64
+		expected  error
+		available float64
+	}{
+		{sequence: 2, amount: 200, available: 200},
+		{sequence: 1, amount: 100, expected: ErrStaleBalanceUpdate, available: 200},
+		{sequence: 2, amount: 250, expected: ErrStaleBalanceUpdate, available: 200},
+		{sequence: 5, amount: 500, available: 500},
+	} {
+		balance := dto.LedgerBalanceDto{WalletReference: "wallet-1", AvailableAmount: push.amount, LedgerSequence: push.sequence}
+		if _, err := service.ApplyLedgerBalance(ctx, "user-1", balance); !errors.Is(err, push.expected) {
+			t.Errorf("sequence %d: expected %v, got %v", push.sequence, push.expected, err)
+		}
+		if stored := storedUser(t, userRepository, "user-1").Wallet.Balance; stored.AvailableAmount != push.available {
+			t.Errorf("sequence %d: expected an available balance of %v, got %+v", push.sequence, push.available, stored)
+		}
+	}
+
+	balance := dto.LedgerBalanceDto{WalletReference: "wallet-2", AvailableAmount: 600, LedgerSequence: 6}
+	if _, err := service.ApplyLedgerBalance(ctx, "user-1", balance); !errors.Is(err, ErrWalletNotOwned) {
+		t.Errorf("expected ErrWalletNotOwned for another wallet, got %v", err)
+	}
+}
+
+func TestConcurrentLedgerBalancesWithTheSameSequenceApplyOnce(t *testing.T) {
+	user := entity.User{UserReference: "user-1", UserProfile: entity.UserProfile{Phone: "+2348000000001"}}
+	_, userRepository := newTestService(t, user)
+
+	// The same push is applied by another consumer between the read and the write.
+	concurrent := entity.Balance{AvailableAmount: 100, LedgerSequence: 3}
+	interleaved := &interleavedUserRepository{UserInfra: userRepository, interleave: func(ctx context.Context, user entity.User) error {
+		_, err := userRepository.SetLedgerBalance(ctx, "user-1", concurrent)
+		return err
+	}}
+	service := NewUserService(interleaved, memoryRepository.NewOutbox(), memoryRepository.NewTier())
+
+	balance := dto.LedgerBalanceDto{AvailableAmount: 90, LedgerSequence: 3}
+	if _, err := service.ApplyLedgerBalance(context.Background(), "user-1", balance); !errors.Is(err, ErrStaleBalanceUpdate) {
+		t.Errorf("expected the second push of sequence 3 to be stale, got %v", err)
+	}
+	if stored := storedUser(t, userRepository, "user-1").Wallet.Balance; stored.AvailableAmount != 100 {
+		t.Errorf("expected the first push to be kept, got %+v", stored)
+	}
+}
+
+func TestUsersBeforeTheDeviceRegistryAreMatchedOnReferenceAndImei(t *testing.T) {
+	device := entity.Device{DeviceReference: "device-1", Imei: "123456789012345", Type: "mobile", Brand: "acme", Model: "one"}
+	user := entity.User{UserReference: "user-1", Device: device}
+
+	updated := device
+	updated.Model, updated.Type = "one-2", "phablet"
+	if !isRegisteredDevice(updated, user) {
+		t.Error("expected a device whose model changed to still be registered")
+	}
+	for name, other := range map[string]entity.Device{
+		"another reference": {DeviceReference: "device-2", Imei: device.Imei},
+		"another imei":      {DeviceReference: device.DeviceReference, Imei: "999999999999999"},
+	} {
+		if isRegisteredDevice(other, user) {
+			t.Errorf("%s: expected the device not to be registered", name)
+		}
+	}
+}
+
+func TestADevicePendingOnAnotherUserCannotBeBound(t *testing.T) {
+	device := dto.DeviceDto{DeviceReference: "device-9", Imei: "999999999999999"}
+	newUser := func(reference string, phone string) entity.User {
+		return entity.User{
+			UserReference: reference,
+			UserProfile:   entity.UserProfile{Phone: phone},
+			Device:        entity.Device{DeviceReference: reference + "-device", Imei: "123456789012345"},
+		}
+	}
+	service, _ := newTestService(t, newUser("user-1", "+2348000000001"), newUser("user-2", "+2348000000002"))
+	ctx := context.Background()
+
+	bind := dto.BindDeviceDto{Contact: "+2348000000001", Channel: "sms"}
+	if _, err := service.RequestDeviceBinding(ctx, "user-1", bind, dto.CurrentUserDto{Phone: "+2348000000001", Device: device}); err != nil {
+		t.Fatal(err)
+	}
+	// Asking again, say after the otp was lost, keeps the device pending.
+	if _, err := service.RequestDeviceBinding(ctx, "user-1", bind, dto.CurrentUserDto{Phone: "+2348000000001", Device: device}); err != nil {
+		t.Errorf("expected the user to ask again for their pending device, got %v", err)
+	}
+
+	bind.Contact = "+2348000000002"
+	_, err := service.RequestDeviceBinding(ctx, "user-2", bind, dto.CurrentUserDto{Phone: "+2348000000002", Device: device})
+	if !errors.Is(err, ErrDeviceAlreadyRegistered) {
+		t.Errorf("expected ErrDeviceAlreadyRegistered binding a device pending on another user, got %v", err)
+	}
+	currentUser := dto.CurrentUserDto{Phone: "+2348000000002", Device: dto.DeviceDto{DeviceReference: "user-2-device", Imei: "123456789012345"}}
+	_, err = service.UpdateDevice(ctx, "user-2", dto.UpdateDeviceDto{NewDevice: entity.Device(device)}, currentUser)
+	if !errors.Is(err, ErrDeviceAlreadyRegistered) {
+		t.Errorf("expected ErrDeviceAlreadyRegistered moving to a device pending on another user, got %v", err)
+	}
+}
+
+func TestANewlyBoundDeviceIsLimitedInTotalWhileItCoolsOff(t *testing.T) {
+	coolingLimit := configuration.ServiceConfiguration.DeviceCoolingLimit
+	configuration.ServiceConfiguration.DeviceCoolingLimit = "100"
+	defer func() { configuration.ServiceConfiguration.DeviceCoolingLimit = coolingLimit }()
+
+	oldDevice := dto.DeviceDto{DeviceReference: "device-1", Imei: "111111111111111"}
+	newDevice := dto.DeviceDto{DeviceReference: "device-2", Imei: "222222222222222"}
+	user := entity.User{
+		UserReference: "user-1",
+		UserProfile:   entity.UserProfile{Phone: "+2348000000001"},
+		Device:        entity.Device(oldDevice),
+		Wallet: entity.Wallet{
+			Balance: entity.Balance{AvailableAmount: 1000},
+			Tier:    entity.Tier{SendingLimit: 1000, ReceivingLimit: 10000, WalletLimit: 10000},
+		},
+	}
+	service, userRepository := newTestService(t, user)
+	ctx := context.Background()
+
+	bind := dto.BindDeviceDto{Contact: "+2348000000001", Channel: "sms"}
+	if _, err := service.RequestDeviceBinding(ctx, "user-1", bind, dto.CurrentUserDto{Phone: "+2348000000001", Device: newDevice}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.CompleteDeviceBinding(ctx, "user-1", entity.Device(newDevice)); err != nil {
+		t.Fatal(err)
+	}
+
+	send := func(device dto.DeviceDto, amount float64) error {
+		currentUser := dto.CurrentUserDto{UserReference: "user-1", Phone: "+2348000000001", Device: device}
+		transaction := dto.CreateTransactionDto{TransactionType: "wallet_wallet", Amount: amount, ReceiverWallsBadgeReference: "badge-2"}
+		_, err := service.CreateTransactionRequest(ctx, "user-1", transaction, currentUser)
+		return err
+	}
+	for _, transaction := range []struct {
+		device   dto.DeviceDto
+		amount   float64
+		expected error
+	}{
+		{newDevice, 60, nil},
+		{newDevice, 40, nil},
+		{newDevice, 1, ErrCoolingOffLimitExceeded},
+		{oldDevice, 500, nil},
+	} {
+		if err := send(transaction.device, transaction.amount); !errors.Is(err, transaction.expected) {
+			t.Errorf("%s sending %v: expected %v, got %v", transaction.device.DeviceReference, transaction.amount, transaction.expected, err)
+		}
+	}
+
+	for _, device := range storedUser(t, userRepository, "user-1").Devices {
+		if device.DeviceReference == "device-2" && device.CoolingOffSent != 100 {
+			t.Errorf("expected the new device to have sent 100 while cooling off, got %v", device.CoolingOffSent)
+		}
+	}
+}
+
+func TestBindingAndRevokingDevicesKeepsATrustedDevice(t *testing.T) {
+	oldDevice := dto.DeviceDto{DeviceReference: "device-1", Imei: "111111111111111"}
+	newDevice := dto.DeviceDto{DeviceReference: "device-2", Imei: "222222222222222"}
+	user := entity.User{
+		UserReference: "user-1",
+		UserProfile:   entity.UserProfile{Phone: "+2348000000001"},
+		Device:        entity.Device(oldDevice),
+	}
+	service, userRepository := newTestService(t, user)
+	ctx := context.Background()
+	onOldDevice := dto.CurrentUserDto{UserReference: "user-1", Phone: "+2348000000001", Device: oldDevice}
+	onNewDevice := dto.CurrentUserDto{UserReference: "user-1", Phone: "+2348000000001", Device: newDevice}
+
+	bind := dto.BindDeviceDto{Contact: "+2348000000001", Channel: "sms", Name: "tablet"}
+	if _, err := service.RequestDeviceBinding(ctx, "user-1", bind, onNewDevice); err != nil {
+		t.Fatal(err)
+	}
+	// A pending device does not count as trusted, so the only trusted one stays.
+	if _, err := service.RevokeDevice(ctx, "user-1", "device-1", onOldDevice); !errors.Is(err, ErrLastTrustedDevice) {
+		t.Errorf("expected ErrLastTrustedDevice revoking the only trusted device, got %v", err)
+	}
+	if _, err := service.RevokeDevice(ctx, "user-1", "device-2", onNewDevice); !errors.Is(err, ErrDeviceNotRegistered) {
+		t.Errorf("expected a pending device to be unable to act for the user, got %v", err)
+	}
+
+	if _, err := service.CompleteDeviceBinding(ctx, "user-1", entity.Device(newDevice)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.CompleteDeviceBinding(ctx, "user-1", entity.Device(newDevice)); !errors.Is(err, ErrNoPendingDeviceBinding) {
+		t.Errorf("expected ErrNoPendingDeviceBinding completing a binding twice, got %v", err)
+	}
+	stored := storedUser(t, userRepository, "user-1")
+	if stored.Device.DeviceReference != "device-2" || len(stored.Devices) != 2 {
+		t.Fatalf("expected the bound device to be the user's device, got %+v", stored.Devices)
+	}
+	if bound := stored.Devices[1]; bound.Status != shared.DeviceTrusted || bound.Name != "tablet" || bound.CoolingOffUntil == "" {
+		t.Errorf("expected the bound device to be trusted and cooling off, got %+v", bound)
+	}
+
+	if _, err := service.RevokeDevice(ctx, "user-1", "device-1", onNewDevice); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.RevokeDevice(ctx, "user-1", "device-2", onNewDevice); !errors.Is(err, ErrLastTrustedDevice) {
+		t.Errorf("expected ErrLastTrustedDevice revoking the bound device once it is the only one, got %v", err)
+	}
+	if _, err := service.RevokeDevice(ctx, "user-1", "device-1", onOldDevice); !errors.Is(err, ErrDeviceNotRegistered) {
+		t.Errorf("expected a revoked device to be unable to act for the user, got %v", err)
+	}
+
+	devicesData, err := service.ListDevices(ctx, "user-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	statuses := map[string]string{}
+	for _, device := range devicesData.([]entity.RegisteredDevice) {
+		statuses[device.DeviceReference] = device.Status
+	}
+	expected := map[string]string{"device-1": shared.DeviceRevoked, "device-2": shared.DeviceTrusted}
+	if !reflect.DeepEqual(statuses, expected) {
+		t.Errorf("expected devices %v, got %v", expected, statuses)
+	}
+}
+
+func TestVerifyPhotoPromotesTheNewestVerifiedPhoto(t *testing.T) {
+	user := entity.User{
+		UserReference: "user-1",
+		UserProfile: entity.UserProfile{
+			Phone: "+2348000000001",
+			Photos: []entity.Photo{
+				{PhotoReference: "photo-1", Status: shared.PhotoVerified, IsVerified: true, IsDefault: true},
+				{PhotoReference: "photo-2", Status: shared.PhotoPending},
+				{PhotoReference: "photo-3", Status: shared.PhotoPending},
+			},
+		},
+	}
+	service, userRepository := newTestService(t, user)
+	ctx := context.Background()
+
+	verified := dto.PhotoVerificationDto{Status: shared.PhotoVerified}
+	rejected := dto.PhotoVerificationDto{Status: shared.PhotoRejected, RejectionReason: "blurred"}
+	for _, step := range []struct {
+		photo_reference string
+		verification    dto.PhotoVerificationDto
+		expectedDefault string
+	}{
+		{"photo-3", verified, "photo-3"},
+		{"photo-2", verified, "photo-3"},
+		{"photo-3", rejected, "photo-2"},
+		{"photo-2", rejected, "photo-1"},
+		{"photo-1", rejected, ""},
+	} {
+		if _, err := service.VerifyPhoto(ctx, "user-1", step.photo_reference, step.verification); err != nil {
+			t.Fatal(err)
+		}
+		defaults := []string{}
+		for _, photo := range storedUser(t, userRepository, "user-1").UserProfile.Photos {
+			if photo.IsDefault {
+				defaults = append(defaults, photo.PhotoReference)
+			}
+		}
+		expected := []string{}
+		if step.expectedDefault != "" {
+			expected = append(expected, step.expectedDefault)
+		}
+		if !reflect.DeepEqual(defaults, expected) {
+			t.Errorf("after %s is %s: expected default %q, got %v", step.photo_reference, step.verification.Status, step.expectedDefault, defaults)
+		}
+	}
+
+	photo := storedUser(t, userRepository, "user-1").UserProfile.Photos[1]
+	if photo.IsVerified || photo.RejectionReason != "blurred" || photo.VerifiedOn != "" {
+		t.Errorf("expected a rejected photo to keep only its rejection, got %+v", photo)
+	}
+	if _, err := service.VerifyPhoto(ctx, "user-1", "photo-9", verified); !errors.Is(err, ErrPhotoNotFound) {
+		t.Errorf("expected ErrPhotoNotFound, got %v", err)
+	}
+	if _, err := service.VerifyPhoto(ctx, "user-1", "photo-1", dto.PhotoVerificationDto{Status: shared.PhotoPending}); !errors.Is(err, ErrInvalidVerificationStatus) {
+		t.Errorf("expected ErrInvalidVerificationStatus, got %v", err)
+	}
+}
+
+// wallsTagRaceRepository - a user repository where another request claims a
+// walls tag right after the first lookup found it free, through claim.
+type wallsTagRaceRepository struct {
+	*memoryRepository.UserInfra
+	claim func(ctx context.Context) error
+}
+
+func (r *wallsTagRaceRepository) GetUserByWallsTag(ctx context.Context, wallsTag string) (interface{}, error) {
+	userData, err := r.UserInfra.GetUserByWallsTag(ctx, wallsTag)
+	if r.claim != nil {
+		claim := r.claim
+		r.claim = nil
+		if err := claim(ctx); err != nil {
+			return nil, err
+		}
+	}
+	return userData, err
+}
+
+func TestDuplicateKeysAreReportedAsConflicts(t *testing.T) {
+	device := dto.DeviceDto{DeviceReference: "device-1", Imei: "123456789012345"}
+	users := []entity.User{
+		{UserReference: "user-1", UserProfile: entity.UserProfile{Phone: "+2348000000001"}, Device: entity.Device(device)},
+		{UserReference: "user-2", UserProfile: entity.UserProfile{Phone: "+2348000000002"}},
+	}
+	service, userRepository := newTestService(t, users...)
+	ctx := context.Background()
+
+	// The phone number is held by user-2, which the lookup by reference misses.
+	newUser := dto.CurrentUserDto{UserReference: "user-3", Phone: "+2348000000002", Device: dto.DeviceDto{DeviceReference: "device-3", Imei: "333333333333333"}}
+	_, err := service.CreateUser(ctx, dto.CreateUserDto{Phone: "+2348000000002"}, newUser)
+	if !errors.Is(err, ErrUserExists) || errorhelper.ErrorFrom(err).Code != 409 {
+		t.Errorf("expected a 409 ErrUserExists creating a user with a phone number in use, got %v", err)
+	}
+
+	racing := &wallsTagRaceRepository{UserInfra: userRepository}
+	racing.claim = func(ctx context.Context) error {
+		other := storedUser(t, userRepository, "user-2")
+		other.UserProfile.WallsBadge = []entity.WallsBadge{{WallsBadgeReference: "badge-2", WallsTag: "walls001"}}
+		_, err := userRepository.UpdateUser(ctx, "user-2", other)
+		return err
+	}
+	service.userRepository = racing
+	currentUser := dto.CurrentUserDto{UserReference: "user-1", Phone: "+2348000000001", Device: device}
+	_, err = service.CreateUserWallsBadge(ctx, "user-1", dto.UserWallsBadgeDto{WallsTag: "walls001"}, currentUser)
+	if !errors.Is(err, ErrWallsTagInUse) || !errors.Is(err, ports.ErrDuplicateKey) || errorhelper.ErrorFrom(err).Code != 409 {
+		t.Errorf("expected a 409 ErrWallsTagInUse from the duplicate walls tag, got %v", err)
+	}
+	if badges := storedUser(t, userRepository, "user-1").UserProfile.WallsBadge; len(badges) != 0 {
+		t.Errorf("expected user-1 to be left without the walls tag, got %+v", badges)
+	}
+}
@@ -3,21 +3,117 @@ package ports
 import (
 	"context"
 	"walls-user-service/internal/core/domain/entity"
+	errorhelper "walls-user-service/internal/core/helper/error-helper"
 )
 
+// ErrVersionConflict - UpdateUser's error when the user was written since the
+// copy being saved was read. The write should be redone from a fresh read.
+var ErrVersionConflict = errorhelper.Conflict("VERSION_CONFLICT", "the user was changed by another request")
+
+// ErrDuplicateKey - CreateUser's and UpdateUser's error when the user would
+// share its reference, phone number or a walls tag with another user.
+var ErrDuplicateKey = errorhelper.Conflict("DUPLICATE_KEY", "the user's reference, phone or walls tag is already in use")
+
 type UserRepository interface {
 	// USER MANAGEMENT
 	//--------------------------------------------------------------------------
 
 	// CRUD Operations on User
-	CreateUser(ctx context.Context, user entity.User) (interface{}, error)
-	UpdateUser(ctx context.Context, user_reference string, user entity.User) (interface{}, error)
+	// Outbox events passed along are persisted atomically with the user write.
+	// UpdateUser only writes over the version of the user that was read and
+	// returns ErrVersionConflict otherwise. Both return ErrDuplicateKey when
+	// another user holds the same reference, phone number or walls tag.
+	CreateUser(ctx context.Context, user entity.User, outboxEvents ...entity.OutboxEvent) (interface{}, error)
+	UpdateUser(ctx context.Context, user_reference string, user entity.User, outboxEvents ...entity.OutboxEvent) (interface{}, error)
+
+	// Targeted updates write only the fields they change and need no prior read.
+	// SetDefaultBank, SetDefaultCard, SetBalance and SetLedgerBalance return
+	// ErrVersionConflict when the user no longer has the bank or card verified, or
+	// holds a newer ledger balance. SetLedgerBalance also refuses a balance with
+	// the ledger sequence already held.
+	PushBank(ctx context.Context, user_reference string, bank entity.Bank, outboxEvents ...entity.OutboxEvent) (interface{}, error)
+	PushCard(ctx context.Context, user_reference string, card entity.Card, outboxEvents ...entity.OutboxEvent) (interface{}, error)
+	PushContact(ctx context.Context, user_reference string, contact entity.Contact, outboxEvents ...entity.OutboxEvent) (interface{}, error)
+	PushCoupon(ctx context.Context, user_reference string, coupon entity.Coupon, outboxEvents ...entity.OutboxEvent) (interface{}, error)
+	SetDefaultBank(ctx context.Context, user_reference string, bankReference string, outboxEvents ...entity.OutboxEvent) (interface{}, error)
+	SetDefaultCard(ctx context.Context, user_reference string, cardReference string, outboxEvents ...entity.OutboxEvent) (interface{}, error)
+	SetBalance(ctx context.Context, user_reference string, balance entity.Balance, outboxEvents ...entity.OutboxEvent) (interface{}, error)
+	SetLedgerBalance(ctx context.Context, user_reference string, balance entity.Balance, outboxEvents ...entity.OutboxEvent) (interface{}, error)
+	SetUserActive(ctx context.Context, user_reference string, isActive bool, outboxEvents ...entity.OutboxEvent) (interface{}, error)
+
+	// AddCoolingOffSpend adds amount to what the user's device has sent while
+	// cooling off, with the outbox events of the transaction it sends. Returns
+	// ErrVersionConflict when that would take the device over limit.
+	AddCoolingOffSpend(ctx context.Context, user_reference string, device entity.Device, amount float64, limit float64, outboxEvents ...entity.OutboxEvent) (interface{}, error)
+
+	// IncrementRewardPoints adds the points to the user's rewards and returns the
+	// updated user. Its outbox events are built from that user within the same
+	// write, so they report the total the increment produced.
+	IncrementRewardPoints(ctx context.Context, user_reference string, points int, outboxEventsFor func(user entity.User) ([]entity.OutboxEvent, error)) (interface{}, error)
 
 	// Retrieval of Users by various identifiers
 	GetUserByReference(ctx context.Context, user_reference string) (interface{}, error)
 	GetUserByPhone(ctx context.Context, phone string) (interface{}, error)
 	GetUserByWallsTag(ctx context.Context, wallsTag string) (interface{}, error)
 	GetUserByWallsBadgeReference(ctx context.Context, wallsBadgeReference string) (interface{}, error)
+	// Devices are matched on their reference and IMEI. GetUserByDevice finds the
+	// user trusting the device, GetOtherUserByDevice any user but user_reference
+	// the device is trusted by or pending binding to.
 	GetUserByDevice(ctx context.Context, device entity.Device) (interface{}, error)
+	GetOtherUserByDevice(ctx context.Context, user_reference string, device entity.Device) (interface{}, error)
 	GetUserDefaultWallsBadge(ctx context.Context, userReference string) (interface{}, error)
+
+	// Back-office search. Returns an entity.UserPage of at most query.Limit users
+	// following query.After, with Next set when more users match.
+	SearchUsers(ctx context.Context, query entity.UserQuery) (interface{}, error)
+}
+
+// ErrStaleTierUpdate - UpsertTier's error when the catalogue already holds the
+// tier as updated after the version being saved.
+var ErrStaleTierUpdate = errorhelper.Conflict("STALE_TIER_UPDATE", "the catalogue holds a later update of the tier")
+
+type TierRepository interface {
+	// TIER CATALOGUE
+	//--------------------------------------------------------------------------
+
+	// UpsertTier only writes over a tier updated on or before tier.UpdatedOn,
+	// compared as RFC3339 UTC timestamps, and returns ErrStaleTierUpdate otherwise.
+	UpsertTier(ctx context.Context, tier entity.TierConfig) (interface{}, error)
+	GetTiers(ctx context.Context) (interface{}, error)
+	GetTierByReference(ctx context.Context, tierReference string) (interface{}, error)
+}
+
+type OutboxRepository interface {
+	// EVENT OUTBOX
+	//--------------------------------------------------------------------------
+
+	CreateOutboxEvent(ctx context.Context, outboxEvent entity.OutboxEvent) (interface{}, error)
+	GetPendingOutboxEvents(ctx context.Context, limit int64) (interface{}, error)
+	MarkOutboxEventDispatched(ctx context.Context, outboxReference string) (interface{}, error)
+	MarkOutboxEventFailed(ctx context.Context, outboxReference string, lastError string, nextAttemptOn string) (interface{}, error)
+}
+
+// ErrDeadLetterNotFound - the error of the DeadLetterRepository reads and
+// deletes when no event is parked under the reference.
+var ErrDeadLetterNotFound = errorhelper.NotFound("DEAD_LETTER_NOT_FOUND", "dead-lettered event not found")
+
+type DeadLetterRepository interface {
+	// DEAD-LETTERED EVENTS
+	//--------------------------------------------------------------------------
+
+	CreateDeadLetterEvent(ctx context.Context, deadLetterEvent entity.DeadLetterEvent) (interface{}, error)
+	GetDeadLetterEvents(ctx context.Context, limit int64) (interface{}, error)
+	GetDeadLetterEventByReference(ctx context.Context, deadLetterReference string) (interface{}, error)
+	DeleteDeadLetterEvent(ctx context.Context, deadLetterReference string) (interface{}, error)
+}
+
+type ProcessedEventRepository interface {
+	// PROCESSED EVENTS
+	//--------------------------------------------------------------------------
+
+	// ClaimEvent reports false when the event is already processed or being processed
+	ClaimEvent(ctx context.Context, eventReference string) (bool, error)
+	MarkEventProcessed(ctx context.Context, eventReference string) (interface{}, error)
+	ReleaseEvent(ctx context.Context, eventReference string) (interface{}, error)
+	CountDuplicateEvent(ctx context.Context, channel string) (interface{}, error)
 }
@@ -3,6 +3,7 @@ package ports
 import (
 	"context"
 	"walls-user-service/internal/core/domain/dto"
+	"walls-user-service/internal/core/domain/entity"
 )
 
 type UserService interface {
@@ -19,6 +20,7 @@ type UserService interface {
 	GetUserByWallsTag(ctx context.Context, wallsTag string) (interface{}, error)
 	GetUserByWallsBagdeReference(ctx context.Context, wallsBadgeReference string) (interface{}, error)
 	GetUserByDevice(ctx context.Context, device dto.DeviceDto) (interface{}, error)
+	SearchUsers(ctx context.Context, userSearchDto dto.UserSearchDto) (interface{}, error)
 
 	// User details updates
 	UpdateUserName(ctx context.Context, user_reference string, updateUserNameDto dto.UserNameDto, currentUser dto.CurrentUserDto) (interface{}, error)
@@ -26,26 +28,33 @@ type UserService interface {
 	UpdateDateOfBirth(ctx context.Context, user_reference string, updateDobDto dto.DobDto, currentUser dto.CurrentUserDto) (interface{}, error)
 	UpdateAddress(ctx context.Context, user_reference string, updateAddressDto dto.AddressDto, currentUser dto.CurrentUserDto) (interface{}, error)
 	UpdatePhoto(ctx context.Context, user_reference string, updatePhotoDto dto.PhotoDto, currentUser dto.CurrentUserDto) (interface{}, error)
+	VerifyPhoto(ctx context.Context, user_reference string, photo_reference string, photoVerificationDto dto.PhotoVerificationDto) (interface{}, error)
 
 	// User communication statuses
 	UpdateUserProfileEmailStatus(ctx context.Context, user_reference string) (interface{}, error)
 	// UpdateUserProfilePhoneStatus(ctx context.Context, user_reference string) (interface{}, error)
 
 	// User finance & rewards management
-	UpdateBalance(ctx context.Context, user_reference string, balance dto.BalanceDto, currentUserDto dto.CurrentUserDto) (interface{}, error)
+	UpdateBalance(ctx context.Context, user_reference string, balance dto.BalanceDto) (interface{}, error)
+	ApplyLedgerBalance(ctx context.Context, user_reference string, ledgerBalanceDto dto.LedgerBalanceDto) (interface{}, error)
 	UpdateWallet(ctx context.Context, user_reference string, updateWalletDto dto.UpdateWalletDto, currentUser dto.CurrentUserDto) (interface{}, error)
-	UpdateTier(ctx context.Context, user_reference string, tierDto dto.TierDto, currentUserDto dto.CurrentUserDto) (interface{}, error)
+	UpdateTier(ctx context.Context, user_reference string, tierDto dto.TierDto) (interface{}, error)
 	AddCoupon(ctx context.Context, user_reference string, couponDto dto.CouponDto) (interface{}, error)
 	UpdateRewards(ctx context.Context, user_reference string, rewardDto dto.RewardDto) (interface{}, error)
 
 	// KYC and ID related
 	AddDocumentation(ctx context.Context, user_reference string, updateDocumentationDto dto.AddDocumentationDto, currentUser dto.CurrentUserDto) (interface{}, error)
 	UpdateDocumentation(ctx context.Context, user_reference string, documentation_reference string, updateDocumentationDto dto.AddDocumentationDto, currentUser dto.CurrentUserDto) (interface{}, error)
+	VerifyDocumentation(ctx context.Context, user_reference string, documentation_reference string, documentationVerificationDto dto.DocumentationVerificationDto) (interface{}, error)
 
 	// DEVICE & NOTIFICATION MANAGEMENT
 	//---------------------------------------------------------------------------
 
 	UpdateDevice(ctx context.Context, user_reference string, updateDeviceDto dto.UpdateDeviceDto, currentUser dto.CurrentUserDto) (interface{}, error)
+	RequestDeviceBinding(ctx context.Context, user_reference string, bindDeviceDto dto.BindDeviceDto, currentUser dto.CurrentUserDto) (interface{}, error)
+	CompleteDeviceBinding(ctx context.Context, user_reference string, device entity.Device) (interface{}, error)
+	ListDevices(ctx context.Context, user_reference string) (interface{}, error)
+	RevokeDevice(ctx context.Context, user_reference string, device_reference string, currentUser dto.CurrentUserDto) (interface{}, error)
 	UpdateNotificationOptions(ctx context.Context, user_reference string, updateNotificationOptionsDto dto.UpdateNotificationOptionsDto, currentUser dto.CurrentUserDto) (interface{}, error)
 
 	// COMPANY MANAGEMENT
@@ -85,11 +94,13 @@ type UserService interface {
 	AddBank(ctx context.Context, user_reference string, updateBankDto dto.BankDto, currentUser dto.CurrentUserDto) (interface{}, error)
 	UpdateBank(ctx context.Context, user_reference string, bank_reference string, updateBankDto dto.BankDto, currentUser dto.CurrentUserDto) (interface{}, error)
 	SetDefaultBank(ctx context.Context, user_reference string, bankReference string, currentUserDto dto.CurrentUserDto) (interface{}, error)
+	VerifyBank(ctx context.Context, user_reference string, bank_reference string, verificationDto dto.PaymentMethodVerificationDto) (interface{}, error)
 
 	// Card management
 	AddCard(ctx context.Context, user_reference string, updateCardDto dto.CardDto, currentUser dto.CurrentUserDto) (interface{}, error)
 	UpdateCard(ctx context.Context, user_reference string, card_reference string, updateCardDto dto.CardDto, currentUser dto.CurrentUserDto) (interface{}, error)
 	SetDefaultCard(ctx context.Context, user_reference string, cardReference string, currentUserDto dto.CurrentUserDto) (interface{}, error)
+	VerifyCard(ctx context.Context, user_reference string, card_reference string, verificationDto dto.PaymentMethodVerificationDto) (interface{}, error)
 
 	// CONTACT MANAGEMENT
 	//---------------------------------------------------------------------------
@@ -103,6 +114,7 @@ type UserService interface {
 	CreateOtpRequest(ctx context.Context, requestOtpDto dto.CreateOtpDto, currentUser dto.CurrentUserDto) (interface{}, error)
 	ValidateOtpRequest(ctx context.Context, user_reference string, validateOtpDto dto.ValidateOtpDto, currentUserDto dto.CurrentUserDto) (interface{}, error)
 	UpgradeTierRequest(ctx context.Context, user_reference string, requestTierDto dto.TierUpgradeRequestDto, currentUser dto.CurrentUserDto) (interface{}, error)
+	ApplyTierUpgradeDecision(ctx context.Context, user_reference string, request_reference string, tierUpgradeDecisionDto dto.TierUpgradeDecisionDto) (interface{}, error)
 
 	// Implement these
 	GetUserByPhone(ctx context.Context, phone string) (interface{}, error)
@@ -111,3 +123,21 @@ type UserService interface {
 	CreateTransactionRequest(ctx context.Context, user_reference string, createTransactionDto dto.CreateTransactionDto, currentUser dto.CurrentUserDto) (interface{}, error)
 	CreateDocumentation(ctx context.Context, user_reference string, documentReferenceDto dto.DocumentReferenceDto)(interface{}, error)
 }
+
+type TierService interface {
+	// TIER CATALOGUE
+	//--------------------------------------------------------------------------
+
+	GetTiers(ctx context.Context) (interface{}, error)
+	GetTierByReference(ctx context.Context, tierReference string) (interface{}, error)
+	UpdateTierConfig(ctx context.Context, tierConfigDto dto.TierConfigDto) (interface{}, error)
+}
+
+type DeadLetterService interface {
+	// DEAD-LETTERED EVENTS
+	//--------------------------------------------------------------------------
+
+	GetDeadLetterEvents(ctx context.Context, limit int64) (interface{}, error)
+	GetDeadLetterEvent(ctx context.Context, deadLetterReference string) (interface{}, error)
+	ReplayDeadLetterEvent(ctx context.Context, deadLetterReference string) (interface{}, error)
+}
@@ -2,34 +2,73 @@ package main
 
 import (
 	"context"
+	"walls-user-service/internal/adapter/events/outbox"
 	"walls-user-service/internal/adapter/events/subscriber"
 	extensions "walls-user-service/internal/adapter/extensions"
+	memoryRepository "walls-user-service/internal/adapter/repository/memory"
 	mongoRepository "walls-user-service/internal/adapter/repository/mongodb"
+	redisRepository "walls-user-service/internal/adapter/repository/redis"
 
 	"fmt"
+	"log"
+	"os"
 	"walls-user-service/internal/adapter/routes"
 	channel "walls-user-service/internal/core/domain/event/channel"
+	event "walls-user-service/internal/core/domain/event/eto"
 	configuration "walls-user-service/internal/core/helper/configuration-helper"
 	logger "walls-user-service/internal/core/helper/log-helper"
 	message "walls-user-service/internal/core/helper/message-helper"
+	ports "walls-user-service/internal/port"
 )
 
 func main() {
 	//Initialize request Log
 	logger.InitializeLog()
+	logger.InitializeAuditLog()
+	config := configuration.ServiceConfiguration
+
+	//Refuse to start with an event type the registry does not know
+	err := event.ValidateRegistry()
+	if err != nil {
+		logger.LogEvent("ERROR", "Event registry validation error: "+err.Error())
+		log.Fatal(err)
+	}
+
+	//Run the migrate subcommand instead of the service
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		err := extensions.RunMigrations(os.Args[2:])
+		if err != nil {
+			logger.LogEvent("ERROR", "Migration error: "+err.Error())
+			log.Fatal(err)
+		}
+		return
+	}
+
 	//Start DB Connection
-	mongoRepo := extensions.StartDatabase("mongodb")
+	var userRepository ports.UserRepository
+	var outboxRepository ports.OutboxRepository
+	var tierRepository ports.TierRepository
+	switch repo := extensions.StartDatabase(config.DBConnectionType).(type) {
+	case memoryRepository.MemoryRepositories:
+		userRepository = repo.User
+		outboxRepository = repo.Outbox
+		tierRepository = repo.Tier
+	case mongoRepository.MongoRepositories:
+		userRepository = repo.User
+		outboxRepository = repo.Outbox
+		tierRepository = repo.Tier
+	}
 
-	logger.LogEvent("INFO", "MongoDB Connected and Initialized!")
+	logger.LogEvent("INFO", "Database Connected and Initialized!")
 
 	logger.LogEvent("INFO", message.StartingRedis)
 	redisClient := extensions.StartEventBus("redis")
+	deadLetterRepository := redisRepository.NewDeadLetter(redisClient)
+	processedEventRepository := redisRepository.NewProcessedEvent(redisClient)
 	ctx := context.Background()
 
 	//Set up routes
-	router := routes.SetupRouter(mongoRepo.(mongoRepository.MongoRepositories).User, redisClient)
-
-	config := configuration.ServiceConfiguration
+	router := routes.SetupRouter(userRepository, outboxRepository, deadLetterRepository, tierRepository)
 
 	go func() {
 		logger.LogEvent("INFO", message.StartingServer)
@@ -41,12 +80,39 @@ func main() {
 		}
 	}()
 
+	// Drain the outbox into the event bus
+	outboxRelay := outbox.NewOutboxRelay(outboxRepository, redisClient)
+	go func() {
+		outboxRelay.Start(ctx)
+	}()
+
 	// Initialize the event subscriber
-	eventSubscriber := subscriber.NewEventSubscriber(redisClient)
+	eventSubscriber := subscriber.NewEventSubscriber(redisClient, deadLetterRepository, processedEventRepository)
 	// Run the subscription code in a Goroutine
 	go func() {
 		eventSubscriber.SubscribeToOtpValidatedEvent(ctx, channel.OtpValidatedEvent)
 	}()
+	go func() {
+		eventSubscriber.SubscribeToIdentificationVerifiedEvent(ctx, channel.IdentificationVerifiedEvent)
+	}()
+	go func() {
+		eventSubscriber.SubscribeToTierUpgradeApprovedEvent(ctx, channel.TierUpgradeApprovedEvent)
+	}()
+	go func() {
+		eventSubscriber.SubscribeToBankVerifiedEvent(ctx, channel.BankVerifiedEvent)
+	}()
+	go func() {
+		eventSubscriber.SubscribeToCardVerifiedEvent(ctx, channel.CardVerifiedEvent)
+	}()
+	go func() {
+		eventSubscriber.SubscribeToPhotoVerifiedEvent(ctx, channel.PhotoVerifiedEvent)
+	}()
+	go func() {
+		eventSubscriber.SubscribeToBalanceUpdateRequestEvent(ctx, channel.BalanceUpdateRequestEvent)
+	}()
+	go func() {
+		eventSubscriber.SubscribeToTierConfigUpdatedEvent(ctx, channel.TierConfigUpdatedEvent)
+	}()
 
 	select {}
 }